@@ -2,7 +2,9 @@ package balancers
 
 import (
 	"context"
+	"time"
 
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/conn"
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/endpoint"
 )
 
@@ -13,3 +15,143 @@ import (
 func WithNodeID(ctx context.Context, nodeID uint32) context.Context {
 	return endpoint.WithNodeID(ctx, nodeID)
 }
+
+// ErrNodeUnavailable returned if client balancer cannot route call with context WithStrictNodeID
+// to YDB endpoint of node
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+var ErrNodeUnavailable = conn.ErrNodeUnavailable
+
+// ErrShuttingDown returned on calls after begin of shutdown of driver (ydb.Driver.BeginShutdown)
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+var ErrShuttingDown = conn.ErrShuttingDown
+
+// ErrNoEndpoints returned if client balancer have no discovered YDB endpoints
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+var ErrNoEndpoints = conn.ErrNoEndpoints
+
+// ErrAllEndpointsBanned returned if client balancer have discovered YDB endpoints but all of them banned.
+// ErrAllEndpointsBanned wraps ErrNoEndpoints and is retryable
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+var ErrAllEndpointsBanned = conn.ErrAllEndpointsBanned
+
+// WithStrictNodeID returns the copy of context with NodeID which the client balancer uses exclusively
+// (such as for reproduce of node-specific issues). Unlike WithNodeID call fails with ErrNodeUnavailable
+// instead of choice of other YDB endpoint if endpoint of node not discovered or not alive.
+// Honored node ID reports with trace of choice of endpoint
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithStrictNodeID(ctx context.Context, nodeID uint32) context.Context {
+	return conn.WithStrictNodeID(ctx, nodeID)
+}
+
+// WithEndpointAffinity returns the copy of context with affinity key. Client balancer routes calls
+// with the same affinity key to the same YDB endpoint (such as calls of interactive transaction).
+// Affinity survives discovery cycles while endpoint persists in discovery response.
+// If endpoint banned - balancer uses default choice of endpoint.
+// Preferred NodeID from context has priority over affinity key
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithEndpointAffinity(ctx context.Context, key string) context.Context {
+	return conn.WithEndpointAffinity(ctx, key)
+}
+
+// WithPreferReadReplica returns the copy of context with preference of read replica endpoints
+// for calls which tolerate stale reads (such as analytical queries).
+// If no one read replica available - balancer uses default choice of endpoint.
+// Read replicas defines with balancer option WithReadReplicaFilter
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithPreferReadReplica(ctx context.Context) context.Context {
+	return conn.WithPreferReadReplica(ctx)
+}
+
+// WithHedging returns the copy of context with hedging of idempotent unary calls.
+// If call not completed within delay - balancer invokes duplicate of call on other endpoint.
+// Result of first completed call returns, other call cancels. Error of canceled call not pessimizes endpoint
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithHedging(ctx context.Context, delay time.Duration) context.Context {
+	return conn.WithHedging(ctx, delay)
+}
+
+// WithDirectEndpoint returns the copy of context with address (host:port) of endpoint for direct calls.
+// Client balancer invokes calls with this context on connection to this exact address (existing connection
+// reuses) without choice of endpoint. Bans and pessimization of endpoints not applies to direct calls.
+// Direct endpoint is an expert escape hatch (such as for node-local introspection of admin tools),
+// direct calls never routes to other endpoints
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithDirectEndpoint(ctx context.Context, address string) context.Context {
+	return conn.WithDirectEndpoint(ctx, address)
+}
+
+// WithShardKey returns the copy of context with application-level sharding key. Client balancer routes calls
+// with the same sharding key to the same YDB endpoint with rendezvous hashing (such as for cache locality on server).
+// Change of endpoints set remaps only keys of added or removed endpoints.
+// Banned endpoint temporarily reroutes its keys to other endpoints until endpoint allowed again.
+// Preferred NodeID and affinity key from context has priority over sharding key
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithShardKey(ctx context.Context, key []byte) context.Context {
+	return conn.WithShardKey(ctx, key)
+}
+
+// ErrEndpointAttemptsExhausted returned if logical operation with context WithMaxEndpointAttempts
+// already tried maximum distinct YDB endpoints
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+var ErrEndpointAttemptsExhausted = conn.ErrEndpointAttemptsExhausted
+
+// WithMaxEndpointAttempts returns the copy of context with limit of distinct YDB endpoints for logical operation.
+// Calls with this context (including retries of operation) fail fast with ErrEndpointAttemptsExhausted
+// instead of trying next endpoint if maxAttempts distinct endpoints already tried.
+// Counter of tried endpoints shares between all calls with returned context, so use new context
+// for every logical operation
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithMaxEndpointAttempts(ctx context.Context, maxAttempts int) context.Context {
+	return conn.WithMaxEndpointAttempts(ctx, maxAttempts)
+}
+
+// ErrAcquireTimeout returned if client balancer cannot choose YDB endpoint for call within acquire timeout
+// from context WithAcquireTimeout
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+var ErrAcquireTimeout = conn.ErrAcquireTimeout
+
+// WithAcquireTimeout returns the copy of context with timeout of choice of YDB endpoint for call.
+// Acquire timeout bounds only choice of endpoint by client balancer, call uses remaining deadline of context.
+// If balancer cannot choose endpoint within acquire timeout - call fails with ErrAcquireTimeout
+// (such as for separate accounting of waiting of connection and latency of call)
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithAcquireTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return conn.WithAcquireTimeout(ctx, timeout)
+}
+
+// WithAutoReconnect returns the copy of context with auto reconnect of streams (such as long-lived topic
+// or coordination streams). Stream opened with this context on loss of connection transparently re-opens
+// on other YDB endpoint and replays messages sent before first received message (initialization of stream).
+// Delivery is at-least-once: replayed initialization messages and message which send failed may be delivered
+// twice, messages sent after initialization and before loss of connection are not replayed.
+// Non-retryable errors of stream returns as is
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithAutoReconnect(ctx context.Context) context.Context {
+	return conn.WithAutoReconnect(ctx)
+}
+
+// WithRequestTag returns the copy of context with request tag (such as tenant ID or feature name for cost
+// attribution and analysis of slow queries on server). Multiple tags with different keys allowed,
+// tag with same key overrides previous value. Tag sends in header "x-ydb-request-tag-<key>" of every call
+// with returned context, so tags never override headers of SDK. Key of tag is case-insensitive and
+// must contain only characters allowed in gRPC metadata keys (lowercase letters, digits, '-', '_' and '.')
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithRequestTag(ctx context.Context, key, value string) context.Context {
+	return conn.WithRequestTag(ctx, key, value)
+}
@@ -0,0 +1,97 @@
+package balancers
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	balancerConfig "github.com/ydb-platform/ydb-go-sdk/v3/internal/balancer/config"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/endpoint"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xerrors"
+)
+
+// DiscoveryCache persists last successful discovery result for fast cold start
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+type DiscoveryCache = balancerConfig.DiscoveryCache
+
+type discoveryCacheEndpoint struct {
+	Address    string  `json:"address"`
+	Location   string  `json:"location,omitempty"`
+	NodeID     uint32  `json:"node_id,omitempty"`
+	LoadFactor float32 `json:"load_factor,omitempty"`
+}
+
+type discoveryCacheFile string
+
+// DiscoveryCacheFile creates discovery cache which stores endpoints in json file by path
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func DiscoveryCacheFile(path string) DiscoveryCache {
+	return discoveryCacheFile(path)
+}
+
+func (path discoveryCacheFile) Load() ([]endpoint.Endpoint, error) {
+	data, err := os.ReadFile(string(path))
+	if err != nil {
+		return nil, xerrors.WithStackTrace(err)
+	}
+
+	var cached []discoveryCacheEndpoint
+	if err = json.Unmarshal(data, &cached); err != nil {
+		return nil, xerrors.WithStackTrace(err)
+	}
+
+	endpoints := make([]endpoint.Endpoint, 0, len(cached))
+	for _, e := range cached {
+		endpoints = append(endpoints, endpoint.New(e.Address,
+			endpoint.WithLocation(e.Location),
+			endpoint.WithID(e.NodeID),
+			endpoint.WithLoadFactor(e.LoadFactor),
+		))
+	}
+
+	return endpoints, nil
+}
+
+func (path discoveryCacheFile) Save(endpoints []endpoint.Endpoint) error {
+	cached := make([]discoveryCacheEndpoint, 0, len(endpoints))
+	for _, e := range endpoints {
+		cached = append(cached, discoveryCacheEndpoint{
+			Address:    e.Address(),
+			Location:   e.Location(),
+			NodeID:     e.NodeID(),
+			LoadFactor: e.LoadFactor(),
+		})
+	}
+
+	data, err := json.Marshal(cached)
+	if err != nil {
+		return xerrors.WithStackTrace(err)
+	}
+
+	// write to temporary file and rename for atomic replace of stale cache
+	tmp, err := os.CreateTemp(filepath.Dir(string(path)), filepath.Base(string(path))+".*")
+	if err != nil {
+		return xerrors.WithStackTrace(err)
+	}
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+
+	if _, err = tmp.Write(data); err != nil {
+		_ = tmp.Close()
+
+		return xerrors.WithStackTrace(err)
+	}
+
+	if err = tmp.Close(); err != nil {
+		return xerrors.WithStackTrace(err)
+	}
+
+	if err = os.Rename(tmp.Name(), string(path)); err != nil {
+		return xerrors.WithStackTrace(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,39 @@
+package balancers
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/endpoint"
+)
+
+func TestDiscoveryCacheFile(t *testing.T) {
+	cache := DiscoveryCacheFile(filepath.Join(t.TempDir(), "endpoints.json"))
+
+	_, err := cache.Load()
+	require.Error(t, err)
+
+	require.NoError(t, cache.Save([]endpoint.Endpoint{
+		endpoint.New("a:123", endpoint.WithID(1), endpoint.WithLocation("a"), endpoint.WithLoadFactor(0.5)),
+		endpoint.New("b:234", endpoint.WithID(2), endpoint.WithLocation("b")),
+	}))
+
+	endpoints, err := cache.Load()
+	require.NoError(t, err)
+	require.Len(t, endpoints, 2)
+	require.Equal(t, "a:123", endpoints[0].Address())
+	require.Equal(t, uint32(1), endpoints[0].NodeID())
+	require.Equal(t, "a", endpoints[0].Location())
+	require.InDelta(t, 0.5, endpoints[0].LoadFactor(), 1e-6)
+	require.Equal(t, "b:234", endpoints[1].Address())
+
+	require.NoError(t, cache.Save([]endpoint.Endpoint{
+		endpoint.New("c:345", endpoint.WithID(3)),
+	}))
+	endpoints, err = cache.Load()
+	require.NoError(t, err)
+	require.Len(t, endpoints, 1)
+	require.Equal(t, "c:345", endpoints[0].Address())
+}
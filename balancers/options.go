@@ -0,0 +1,466 @@
+package balancers
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+
+	balancerConfig "github.com/ydb-platform/ydb-go-sdk/v3/internal/balancer/config"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/conn"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/endpoint"
+)
+
+type (
+	// Conn is a balancer connection which reports endpoint info and connection state
+	//
+	// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+	Conn = conn.Conn
+
+	// Option is an option for balancer config. Options applies with balancer config method With
+	//
+	// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+	Option = balancerConfig.Option
+
+	// ConnectionPicker selects connection for next call from current balancer connections
+	//
+	// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+	ConnectionPicker = balancerConfig.ConnectionPicker
+
+	// LocalDCDetectionMode defines algorithm of detection nearest DC
+	//
+	// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+	LocalDCDetectionMode = balancerConfig.LocalDCDetectionMode
+
+	// AddressFamily defines preferred address family of endpoints
+	//
+	// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+	AddressFamily = balancerConfig.AddressFamily
+
+	// SelectionMode defines algorithm of choice of connection among connections of same priority
+	//
+	// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+	SelectionMode = balancerConfig.SelectionMode
+
+	// NewEndpointBias defines order of connections of new and previously discovered endpoints after discovery
+	//
+	// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+	NewEndpointBias = balancerConfig.NewEndpointBias
+
+	// Policy defines routing of calls of gRPC method with routing hints in context of call
+	//
+	// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+	Policy = balancerConfig.Policy
+)
+
+const (
+	// FastestDial detects nearest DC as location of first endpoint which accepts tcp connection
+	FastestDial = balancerConfig.FastestDial
+
+	// LatencyProbe detects nearest DC as location with lowest median round-trip time of probes
+	// to a sample of endpoints in each location. Probe is a raw tcp connect to endpoint (not a gRPC call),
+	// so probe not uses dial options of driver (such as TLS or proxy dialer) and not measures TLS handshake
+	LatencyProbe = balancerConfig.LatencyProbe
+
+	// DualStack uses endpoints of all address families equally
+	DualStack = balancerConfig.DualStack
+
+	// IPv4 prefers endpoints with IPv4 addresses
+	IPv4 = balancerConfig.IPv4
+
+	// IPv6 prefers endpoints with IPv6 addresses
+	IPv6 = balancerConfig.IPv6
+
+	// RandomSelection chooses random connection
+	RandomSelection = balancerConfig.Random
+
+	// RoundRobinSelection rotates through connections deterministically
+	// (RoundRobin name reserved by deprecated balancer)
+	RoundRobinSelection = balancerConfig.RoundRobin
+
+	// NoNewEndpointBias chooses connections of new and previously discovered endpoints equally
+	NoNewEndpointBias = balancerConfig.NoNewEndpointBias
+
+	// WarmFirst prefers connections of previously discovered endpoints
+	WarmFirst = balancerConfig.WarmFirst
+
+	// ColdFirst prefers connections of newly discovered endpoints
+	ColdFirst = balancerConfig.ColdFirst
+)
+
+// WithConnectionPicker defines custom algorithm for choose connection (such as least-in-flight or latency-weighted)
+// Picker consulted before default random choice. If picker returns nil - balancer uses default algorithm
+// Picker does not override choice of connection by node ID from context
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithConnectionPicker(picker ConnectionPicker) Option {
+	return balancerConfig.WithConnectionPicker(picker)
+}
+
+// WithLoadWeighting enables weighting of random choice of connection by endpoint load factor
+// Heavily loaded endpoints receive proportionally less traffic. Endpoints with zero or negative load have weight 1
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithLoadWeighting(loadWeighting bool) Option {
+	return balancerConfig.WithLoadWeighting(loadWeighting)
+}
+
+// WithSelectionMode defines algorithm of choice of connection among connections of same priority.
+// RandomSelection is a default mode. RoundRobinSelection rotates through connections deterministically
+// and skips banned connections, so distribution of calls is even on low rate of calls (such as background jobs).
+// Load weighting overrides selection mode
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithSelectionMode(mode SelectionMode) Option {
+	return balancerConfig.WithSelectionMode(mode)
+}
+
+// WithEndpointComparator defines total order of endpoints (such as order by node ID) for deterministic choice
+// of endpoint among endpoints of same priority: balancer rotates through sorted endpoints (round-robin).
+// With load weighting balancer chooses group of endpoints with equal load weight by weighted random choice
+// and rotates through sorted endpoints inside group
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithEndpointComparator(cmp func(a, b Endpoint) int) Option {
+	return balancerConfig.WithEndpointComparator(func(a, b endpoint.Info) int {
+		return cmp(a, b)
+	})
+}
+
+// WithNewEndpointBias defines order of connections for a short window (30 seconds by default, see
+// WithNewEndpointBiasWindow) after discovery which adds new endpoints. Connections of new endpoints are cold
+// (not dialed yet): WarmFirst avoids latency of first calls to new nodes, ColdFirst balances load onto
+// new nodes quickly. Bias orders connections only within groups of connections of same priority (such as local DC)
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithNewEndpointBias(bias NewEndpointBias) Option {
+	return balancerConfig.WithNewEndpointBias(bias)
+}
+
+// WithNewEndpointBiasWindow defines duration of bias of connections after discovery of new endpoints
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithNewEndpointBiasWindow(window time.Duration) Option {
+	return balancerConfig.WithNewEndpointBiasWindow(window)
+}
+
+// WithEndpointFilter excludes endpoints from balancing if filter returns false (such as nodes under maintenance)
+// Filter applies on every discovery, filtered endpoints never used for calls even with fallback
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithEndpointFilter(filter func(endpoint Endpoint) bool) Option {
+	return balancerConfig.WithEndpointFilter(func(e endpoint.Info) bool {
+		return filter(e)
+	})
+}
+
+// WithLocalDCDetectionMode defines algorithm of detection nearest DC for PreferNearestDC balancers
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithLocalDCDetectionMode(mode LocalDCDetectionMode) Option {
+	return balancerConfig.WithLocalDCDetectionMode(mode)
+}
+
+// WithLatencyProbeCount defines count of probes for each sampled endpoint in LatencyProbe mode
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithLatencyProbeCount(count int) Option {
+	return balancerConfig.WithLatencyProbeCount(count)
+}
+
+// WithLocalDCProbeConcurrency limits count of simultaneous probes of endpoints on detection of nearest DC
+// for PreferNearestDC balancers (such as for avoid of spike of connections in large clusters).
+// Probes covers sampled endpoints of all DCs equally regardless of limit. Zero value means no limit
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithLocalDCProbeConcurrency(concurrency int) Option {
+	return balancerConfig.WithLocalDCProbeConcurrency(concurrency)
+}
+
+// WithLocalDCProbeEndpointsPerDC defines count of random endpoints of every DC for probes on detection
+// of nearest DC for PreferNearestDC balancers. Zero value means default count (5 endpoints of every DC)
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithLocalDCProbeEndpointsPerDC(count int) Option {
+	return balancerConfig.WithLocalDCProbeEndpointsPerDC(count)
+}
+
+// WithLatencyProbeTimeout defines timeout of single probe (tcp connect) in LatencyProbe mode
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithLatencyProbeTimeout(timeout time.Duration) Option {
+	return balancerConfig.WithLatencyProbeTimeout(timeout)
+}
+
+// WithDiscoveryCache defines storage of last successful discovery result
+// On driver initialization balancer uses cached endpoints immediately and runs fresh discovery in background.
+// Cached endpoints replaces with fresh endpoints after discovery completes.
+// Cache uses only with enabled background discovery (discovery interval greater than zero)
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithDiscoveryCache(cache DiscoveryCache) Option {
+	return balancerConfig.WithDiscoveryCache(cache)
+}
+
+// WithInitialState defines snapshot of balancer state exported by balancer of other process
+// (such as old process on blue/green deploy). On driver initialization balancer uses endpoints and local DC
+// of snapshot immediately without initial discovery and runs fresh discovery in background.
+// Snapshot of incompatible version ignores and balancer makes initial discovery.
+// Initial state uses only with enabled background discovery (discovery interval greater than zero)
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithInitialState(state []byte) Option {
+	return balancerConfig.WithInitialState(state)
+}
+
+// WithCircuitBreaker enables per-endpoint circuit breaker.
+// Endpoint with threshold failures within window refused for cooldown, after that single probe call allowed.
+// Successful probe closes circuit breaker, failed probe opens it for cooldown again
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithCircuitBreaker(threshold int, window, cooldown time.Duration) Option {
+	return balancerConfig.WithCircuitBreaker(threshold, window, cooldown)
+}
+
+// WithDrainTimeout enables draining of endpoints removed from discovery response.
+// Draining endpoints not used for new calls, but existing calls (such as streams) continues for drain timeout.
+// After drain timeout connections to removed endpoints closes
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithDrainTimeout(timeout time.Duration) Option {
+	return balancerConfig.WithDrainTimeout(timeout)
+}
+
+// WithDiscoveryConn defines established connection (such as *grpc.ClientConn shared with other client)
+// for cluster discovery instead of dial of discovery endpoint. Balancer never closes connection,
+// so owner of connection closes it after close of driver
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithDiscoveryConn(cc grpc.ClientConnInterface) Option {
+	return balancerConfig.WithDiscoveryConn(cc)
+}
+
+// WithMaxStreamsPerConn limits count of open streams (such as streams of topic readers) through connection
+// for prevent of stream hotspot on single node. Balancer opens new stream through less loaded connection
+// if chosen connection reached maximum, new stream fails if all connections reached maximum
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithMaxStreamsPerConn(n int) Option {
+	return balancerConfig.WithMaxStreamsPerConn(n)
+}
+
+// WithIdleTimeout enables parking of connections which not chosen by balancer and not used for calls
+// longer than idle timeout (same as ydb.WithConnectionTTL, shortest of both wins).
+// Parked connections stay in balancer and lazily re-dials on next selection
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithIdleTimeout(timeout time.Duration) Option {
+	return balancerConfig.WithIdleTimeout(timeout)
+}
+
+// WithReadReplicaFilter defines which endpoints are read replicas (followers).
+// Discovery does not report role of endpoint, so role of endpoint defines by filter (such as by location or node ID).
+// Calls with context from WithPreferReadReplica routes to read replicas if they available
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithReadReplicaFilter(filter func(endpoint Endpoint) bool) Option {
+	return balancerConfig.WithReadReplicaFilter(func(e endpoint.Info) bool {
+		return filter(e)
+	})
+}
+
+// WithConnectionWarmup enables dial of new connections in background after discovery (with bounded concurrency)
+// Warmup smooths latency of first calls to new endpoints. Failures of warmup reports with OnConnDial trace
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithConnectionWarmup(warmup bool) Option {
+	return balancerConfig.WithConnectionWarmup(warmup)
+}
+
+// WithReadinessCheck enables check of new connections with lightweight WhoAmI request before use
+// of connection for calls (such as on scale-up of cluster while nodes are initializing).
+// Connections which failed check stay pending and checks again with exponential backoff.
+// Pending connections used for calls only if no one ready connection available
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithReadinessCheck(check bool) Option {
+	return balancerConfig.WithReadinessCheck(check)
+}
+
+// WithAddressRewriter defines rewrite of discovered endpoint address (host:port) before dial.
+// Rewriter helps to connect to cluster through NAT or proxy with different mapping of addresses.
+// Node ID and location of endpoint preserves, so balancing works as without rewriter
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithAddressRewriter(rewrite func(endpoint Endpoint) (address string)) Option {
+	return balancerConfig.WithAddressRewriter(func(e endpoint.Endpoint) endpoint.Endpoint {
+		return endpoint.New(rewrite(e))
+	})
+}
+
+// WithDialGuard defines check of endpoint before every dial of connection (such as policy check of endpoint
+// in zero-trust environment). Unlike endpoint filter guard decides on every attempt of dial.
+// Non-nil error of guard refuses dial and excludes endpoint from balancing until next discovery
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithDialGuard(guard func(ctx context.Context, endpoint Endpoint) error) Option {
+	return balancerConfig.WithDialGuard(func(ctx context.Context, e endpoint.Endpoint) error {
+		return guard(ctx, e)
+	})
+}
+
+// WithMaxConnections limits count of simultaneous connections of balancer for constrained environments.
+// Balancer selects up to maxConnections endpoints on every discovery. Endpoints preferred by balancer
+// (such as endpoints in nearest DC) selects first, other endpoints selects only with allowed fallback.
+// Selection rotates across discovery cycles, so load distributes over all endpoints of cluster.
+// Calls never routes to not selected endpoints. Connections to not selected endpoints closes
+// after drain timeout (if defined with WithDrainTimeout) or immediately
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithMaxConnections(maxConnections int) Option {
+	return balancerConfig.WithMaxConnections(maxConnections)
+}
+
+// WithMinEndpointsFraction defines minimum fraction of previously discovered endpoints in discovery result
+// (such as 0.5 for half of endpoints). Smaller discovery result (such as on partition of control plane of cluster)
+// treats as partial, balancer keeps previous endpoints and connections and reports warning with trace.
+// Partial results skips up to two consecutive times, next consecutive partial result applies as is
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithMinEndpointsFraction(fraction float64) Option {
+	return balancerConfig.WithMinEndpointsFraction(fraction)
+}
+
+// WithKeepStateOnEmptyDiscovery defines skip of empty discovery result (such as on brief reconfiguration
+// of cluster), so balancer keeps previous endpoints and connections instead of fail of all calls
+// with no endpoints error and reports warning with trace (as partial discovery result).
+// Empty results skips up to three consecutive times, next consecutive empty result applies as is
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithKeepStateOnEmptyDiscovery(keep bool) Option {
+	return balancerConfig.WithKeepStateOnEmptyDiscovery(keep)
+}
+
+// WithRemoteDCConnectionLimit limits count of connections to endpoints outside local DC (such as connections
+// for fallback), connections to endpoints in local DC not limited. Limit applies only with known local DC
+// (such as with PreferNearestDC or forced local DC). Selection of remote endpoints rotates across discovery cycles.
+// Connections to not selected endpoints closes after drain timeout (if defined with WithDrainTimeout) or immediately
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithRemoteDCConnectionLimit(limit int) Option {
+	return balancerConfig.WithRemoteDCConnectionLimit(limit)
+}
+
+// WithLocalDCDetectTimeout defines timeout of nearest DC detection separately from discovery timeout.
+// If detection exceeds timeout balancer uses all discovered endpoints without preference of nearest DC
+// until next discovery
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithLocalDCDetectTimeout(timeout time.Duration) Option {
+	return balancerConfig.WithLocalDCDetectTimeout(timeout)
+}
+
+// WithForcedLocalDC defines local DC of client for PreferNearestDC balancers instead of detection of nearest DC
+// (such as DC from environment variable of orchestrator).
+// Balancer skips probes of endpoints for detection of nearest DC.
+// If no one discovered endpoint located in forced local DC and fallback not allowed - balancer traces warning
+// with OnBalancerLocalDCNotFound event
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithForcedLocalDC(name string) Option {
+	return balancerConfig.WithForcedLocalDC(name)
+}
+
+// WithLocality defines locality path of endpoint from DC to rack (such as parsed from location "dc/rack")
+// for PreferNearestDC balancers. Balancer detects locality of client with detection of nearest DC and prefers
+// endpoints with deepest matched locality (such as same rack, then same DC).
+// Matched level of chosen endpoint reports with LocalityLevel of OnBalancerChooseEndpoint trace
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithLocality(locality func(endpoint Endpoint) []string) Option {
+	return balancerConfig.WithLocality(func(e endpoint.Info) []string {
+		return locality(e)
+	})
+}
+
+// WithStrictLocalDC allows only connections in local DC for PreferNearestDC balancers (such as for data residency).
+// Strict local DC takes precedence over fallback and DC priority: balancer never uses connections outside
+// local DC (even for node ID from context) and returns error on exhaustion of local DC connections.
+// If local DC not detected (such as on timeout of detection) and not forced - balancer has no one usable connection
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithStrictLocalDC(strict bool) Option {
+	return balancerConfig.WithStrictLocalDC(strict)
+}
+
+// WithDCPriority defines order of DCs for choose of fallback connections when preferred connections
+// (such as connections in nearest DC) exhausted and fallback allowed. Balancer consults fallback DCs
+// in order of priority (such as by geographical distance). DCs not listed in priority consults last
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithDCPriority(dcPriority ...string) Option {
+	return balancerConfig.WithDCPriority(dcPriority)
+}
+
+// WithStartupFallbackSingleConn enables start of driver with single connection to initial endpoint
+// if initial cluster discovery failed (such as on unavailability of discovery service).
+// Balancer upgrades to all discovered endpoints after first successful background discovery.
+// Option works only with background discovery (discovery interval greater than zero)
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithStartupFallbackSingleConn(fallback bool) Option {
+	return balancerConfig.WithStartupFallbackSingleConn(fallback)
+}
+
+// WithBanRecoveryProbe enables active probes of banned endpoints. Probes repeats with exponentially
+// growing intervals from minInterval to maxInterval. Endpoint returns to balancing after first successful probe
+// without waiting of next discovery
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithBanRecoveryProbe(minInterval, maxInterval time.Duration) Option {
+	return balancerConfig.WithBanRecoveryProbe(minInterval, maxInterval)
+}
+
+// WithDiscoveryHistory defines count of last applied discovery results which balancer keeps in memory
+// for debugging (see ydb.Driver.DiscoveryHistory). Zero value disables discovery history
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithDiscoveryHistory(size int) Option {
+	return balancerConfig.WithDiscoveryHistory(size)
+}
+
+// WithAddressFamilyPreference defines preferred address family of endpoints (such as IPv6 in IPv6-migration).
+// Balancer uses endpoints of other address family if no one endpoint of preferred address family available.
+// Address family defines by IP address of endpoint, endpoints with host names are not of preferred address family
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithAddressFamilyPreference(family AddressFamily) Option {
+	return balancerConfig.WithAddressFamilyPreference(family)
+}
+
+// WithMethodPolicy defines routing policy for calls of gRPC method (such as sticky routing of coordination sessions).
+// Method is a full gRPC method name (such as /Ydb.Coordination.V1.CoordinationService/Session)
+// or gRPC service name (such as /Ydb.Coordination.V1.CoordinationService).
+// Policy for exact method name has priority over policy for service name.
+// Calls of other methods routes with default choice of balancer
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithMethodPolicy(method string, policy Policy) Option {
+	return balancerConfig.WithMethodPolicy(method, policy)
+}
+
+// RandomPolicy routes calls with default choice of balancer (random choice of endpoint)
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func RandomPolicy() Policy {
+	return balancerConfig.RandomPolicy()
+}
+
+// StickyPolicy routes all calls of method to the same YDB endpoint while endpoint alive.
+// If endpoint banned - balancer uses default choice of endpoint.
+// Affinity key from context of call has priority over sticky policy
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func StickyPolicy() Policy {
+	return balancerConfig.StickyPolicy()
+}
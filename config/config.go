@@ -1,16 +1,23 @@
 package config
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"fmt"
+	"net"
+	"strings"
 	"time"
 
 	"google.golang.org/grpc"
 	grpcCodes "google.golang.org/grpc/codes"
+	_ "google.golang.org/grpc/encoding/gzip" // registers gzip compressor for compression of calls and responses
+	"google.golang.org/grpc/keepalive"
 
 	"github.com/ydb-platform/ydb-go-sdk/v3/credentials"
 	balancerConfig "github.com/ydb-platform/ydb-go-sdk/v3/internal/balancer/config"
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/config"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/endpoint"
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/meta"
 	"github.com/ydb-platform/ydb-go-sdk/v3/retry/budget"
 	"github.com/ydb-platform/ydb-go-sdk/v3/trace"
@@ -23,17 +30,67 @@ type Config struct {
 	trace          *trace.Driver
 	dialTimeout    time.Duration
 	connectionTTL  time.Duration
+	keepalive      keepalive.ClientParameters
 	balancerConfig *balancerConfig.Config
 	secure         bool
 	endpoint       string
+	bootstrap      []string
+	dialTarget     string
 	database       string
 	metaOptions    []meta.Option
 	grpcOptions    []grpc.DialOption
+	contextDialer  func(ctx context.Context, address string) (net.Conn, error)
 	credentials    credentials.Credentials
 	tlsConfig      *tls.Config
 	meta           *meta.Meta
 
+	// customTLSConfig is true if TLS config defined with WithTLSConfig
+	customTLSConfig    bool
+	clientCertificates []tls.Certificate
+	// rootCertificates contains certificates appended to root certificates of TLS config
+	rootCertificates []*x509.Certificate
+
 	excludeGRPCCodesForPessimization []grpcCodes.Code
+	pessimizationFunc                func(err error, excludeCodes ...grpcCodes.Code) bool
+
+	// compression is a name of gRPC compressor of unary calls, compressionMinSize is a minimum size
+	// of compressed request message
+	compression        string
+	compressionMinSize int
+
+	// failover contains standby database for failover of calls
+	failover *failoverDatabase
+}
+
+// failoverDatabase contains standby database and delays of failover and failback
+type failoverDatabase struct {
+	endpoint      string
+	database      string
+	failoverDelay time.Duration
+	failbackDelay time.Duration
+}
+
+// FailoverDatabase returns config of standby database for failover of calls with delays of failover and
+// failback. Config of standby database is a copy of config with endpoint and database of standby database.
+// ok is false if standby database not defined
+func (c *Config) FailoverDatabase() (_ *Config, failoverDelay, failbackDelay time.Duration, ok bool) {
+	if c.failover == nil {
+		return nil, 0, 0, false
+	}
+
+	standby := *c
+	standby.endpoint, standby.database = c.failover.endpoint, c.failover.database
+	standby.bootstrap, standby.dialTarget = nil, ""
+	standby.failover = nil
+	standby.meta = meta.New(standby.database, standby.credentials, standby.trace, standby.metaOptions...)
+
+	return &standby, c.failover.failoverDelay, c.failover.failbackDelay, true
+}
+
+// Compression returns name of gRPC compressor and minimum size of compressed request message of unary calls.
+// Empty algorithm means that calls not compresses
+func (c *Config) Compression() (algorithm string, minMessageSize int) {
+	return c.compression, c.compressionMinSize
 }
 
 func (c *Config) Credentials() credentials.Credentials {
@@ -45,12 +102,72 @@ func (c *Config) ExcludeGRPCCodesForPessimization() []grpcCodes.Code {
 	return c.excludeGRPCCodesForPessimization
 }
 
+// PessimizationFunc returns custom check of error which must ban endpoint.
+// Returns nil if balancer uses default check
+func (c *Config) PessimizationFunc() func(err error, excludeCodes ...grpcCodes.Code) bool {
+	return c.pessimizationFunc
+}
+
 // GrpcDialOptions reports about used grpc dialing options
 func (c *Config) GrpcDialOptions() []grpc.DialOption {
-	return append(
-		defaultGrpcOptions(c.trace, c.secure, c.tlsConfig),
-		c.grpcOptions...,
+	opts := defaultGrpcOptions(c.trace, c.secure, c.TLSConfig(), c.keepalive)
+	if c.contextDialer != nil {
+		opts = append(opts, grpc.WithContextDialer(c.contextDialer))
+	}
+
+	return append(opts, c.grpcOptions...)
+}
+
+// DescribeDialOptions returns human-readable descriptions of grpc dialing options for diagnostics.
+// Descriptions not contains sensitive material such as certificates and credentials
+func (c *Config) DescribeDialOptions() []string {
+	descriptions := make([]string, 0, 8)
+	if c.secure {
+		tlsConfig := c.TLSConfig()
+		rootCAs := "system"
+		if c.customTLSConfig || len(c.rootCertificates) > 0 {
+			rootCAs = "custom"
+		}
+		descriptions = append(descriptions, fmt.Sprintf(
+			"tls: enabled (min version %s, root CAs %s, client certificates %d, insecure skip verify %t)",
+			tls.VersionName(tlsConfig.MinVersion), rootCAs, len(tlsConfig.Certificates), tlsConfig.InsecureSkipVerify,
+		))
+	} else {
+		descriptions = append(descriptions, "tls: disabled")
+	}
+	descriptions = append(descriptions,
+		fmt.Sprintf("keepalive: time %v, timeout %v, permit without stream %t",
+			c.keepalive.Time, c.keepalive.Timeout, c.keepalive.PermitWithoutStream,
+		),
+		"load balancing policy: round_robin",
+		fmt.Sprintf("max message size: %d bytes", DefaultGRPCMsgSize),
 	)
+	if algorithm, minMessageSize := c.Compression(); algorithm != "" {
+		descriptions = append(descriptions, fmt.Sprintf(
+			"compression: %s (min message size %d bytes)", algorithm, minMessageSize,
+		))
+	} else {
+		descriptions = append(descriptions, "compression: disabled")
+	}
+	if c.meta != nil {
+		descriptions = append(descriptions, "user-agent: "+c.meta.UserAgent())
+		if applicationName := c.meta.ApplicationName(); applicationName != "" {
+			descriptions = append(descriptions, "application name: "+applicationName)
+		}
+	}
+	if c.contextDialer != nil {
+		descriptions = append(descriptions, "context dialer: custom")
+	}
+	if len(c.grpcOptions) > 0 {
+		descriptions = append(descriptions, fmt.Sprintf("custom dial options: %d", len(c.grpcOptions)))
+	}
+
+	return descriptions
+}
+
+// KeepaliveParams reports about grpc keepalive parameters of discovery and pooled connections
+func (c *Config) KeepaliveParams() keepalive.ClientParameters {
+	return c.keepalive
 }
 
 // Meta reports meta information about database connection
@@ -59,10 +176,16 @@ func (c *Config) Meta() *meta.Meta {
 }
 
 // ConnectionTTL defines interval for parking grpc connections.
+// Idle timeout of balancer (balancers.WithIdleTimeout) overrides ConnectionTTL if it shorter.
 //
 // If ConnectionTTL is zero - connections are not park.
 func (c *Config) ConnectionTTL() time.Duration {
-	return c.connectionTTL
+	ttl := c.connectionTTL
+	if b := c.balancerConfig; b != nil && b.IdleTimeout > 0 && (ttl == 0 || b.IdleTimeout < ttl) {
+		return b.IdleTimeout
+	}
+
+	return ttl
 }
 
 // Secure is a flag for secure connection
@@ -75,9 +198,55 @@ func (c *Config) Endpoint() string {
 	return c.endpoint
 }
 
+// BootstrapEndpoints is a list of endpoints for initial cluster discovery
+//
+// If bootstrap endpoints are not defined - used single Endpoint
+func (c *Config) BootstrapEndpoints() []string {
+	if len(c.bootstrap) == 0 {
+		return []string{c.endpoint}
+	}
+
+	return c.bootstrap
+}
+
+// DialTarget returns grpc dial target for endpoint address.
+//
+// Dial target from WithDialTarget option used verbatim for driver endpoint.
+// Other addresses prepends with scheme of dial target (by default "ydb") for grpc resolver
+// to find the proper scheme. Three slashes in "ydb:///" is ok. It needs for good parse scheme in grpc resolver
+func (c *Config) DialTarget(address string) string {
+	if c.dialTarget != "" && address == c.endpoint {
+		return c.dialTarget
+	}
+
+	return c.dialScheme() + ":///" + address
+}
+
+func (c *Config) dialScheme() string {
+	if scheme, _, has := strings.Cut(c.dialTarget, "://"); has && scheme != "" {
+		return scheme
+	}
+
+	return DefaultDialScheme
+}
+
 // TLSConfig reports about TLS configuration
+//
+// Client certificates appends to TLS config only if TLS config not defined with WithTLSConfig
 func (c *Config) TLSConfig() *tls.Config {
-	return c.tlsConfig
+	if c.customTLSConfig || len(c.clientCertificates) == 0 {
+		return c.tlsConfig
+	}
+
+	tlsConfig := c.tlsConfig.Clone()
+	tlsConfig.Certificates = append(tlsConfig.Certificates, c.clientCertificates...)
+
+	return tlsConfig
+}
+
+// HasTLSOptions reports about TLS config or client certificates defined explicitly
+func (c *Config) HasTLSOptions() bool {
+	return c.customTLSConfig || len(c.clientCertificates) > 0
 }
 
 // DialTimeout is the maximum amount of time a dial will wait for a connect to
@@ -104,6 +273,15 @@ func (c *Config) Balancer() *balancerConfig.Config {
 	return c.balancerConfig
 }
 
+// DialGuard returns check of endpoint before dial from balancer config
+func (c *Config) DialGuard() func(ctx context.Context, e endpoint.Endpoint) error {
+	if c.balancerConfig == nil {
+		return nil
+	}
+
+	return c.balancerConfig.DialGuard
+}
+
 type Option func(c *Config)
 
 // WithInternalDNSResolver
@@ -121,6 +299,22 @@ func WithEndpoint(endpoint string) Option {
 	}
 }
 
+// WithBootstrapEndpoints defines list of endpoints for initial cluster discovery.
+// Endpoints are tried in order until one of them answers on discovery request
+func WithBootstrapEndpoints(endpoints ...string) Option {
+	return func(c *Config) {
+		c.bootstrap = append(c.bootstrap, endpoints...)
+	}
+}
+
+// WithDialTarget defines grpc dial target of driver endpoint (such as "xds:///cluster") for custom grpc name resolver.
+// Dial target used verbatim for bootstrap dial, scheme of dial target used for dial discovered endpoints
+func WithDialTarget(target string) Option {
+	return func(c *Config) {
+		c.dialTarget = target
+	}
+}
+
 // WithSecure changes secure connection flag.
 //
 // Warning: if secure is false - TLS config options has no effect.
@@ -140,15 +334,45 @@ func WithDatabase(database string) Option {
 func WithCertificate(certificate *x509.Certificate) Option {
 	return func(c *Config) {
 		c.tlsConfig.RootCAs.AddCert(certificate)
+		c.rootCertificates = append(c.rootCertificates, certificate)
+	}
+}
+
+// WithSystemCertPool defines usage of system certificates pool as base of TLS config root certificates.
+// Certificates from WithCertificate appends to copy of system pool or to empty pool regardless of order of options.
+// System certificates pool used by default
+func WithSystemCertPool(use bool) Option {
+	return func(c *Config) {
+		rootCAs := x509.NewCertPool()
+		if use {
+			rootCAs = certPool()
+		}
+		for _, certificate := range c.rootCertificates {
+			rootCAs.AddCert(certificate)
+		}
+		c.tlsConfig = c.tlsConfig.Clone()
+		c.tlsConfig.RootCAs = rootCAs
+	}
+}
+
+// WithClientCertificate appends client certificate for mutual TLS authentication
+//
+// Warning: client certificates has no effect if TLS config defined with WithTLSConfig
+func WithClientCertificate(certificate tls.Certificate) Option {
+	return func(c *Config) {
+		c.clientCertificates = append(c.clientCertificates, certificate)
 	}
 }
 
 // WithTLSConfig replaces older TLS config
 //
-// Warning: all early changes of TLS config will be lost
+// Warning: all early changes of TLS config will be lost.
+// TLS config takes precedence over client certificates from WithClientCertificate
 func WithTLSConfig(tlsConfig *tls.Config) Option {
 	return func(c *Config) {
 		c.tlsConfig = tlsConfig
+		c.customTLSConfig = true
+		c.rootCertificates = nil
 	}
 }
 
@@ -178,6 +402,22 @@ func WithApplicationName(applicationName string) Option {
 	}
 }
 
+// WithStaticMetadata appends static headers to metadata of all api requests (including discovery requests).
+// Reserved headers of SDK are ignored, headers from outgoing metadata of context takes precedence over static headers
+func WithStaticMetadata(md map[string]string) Option {
+	return func(c *Config) {
+		c.metaOptions = append(c.metaOptions, meta.WithStaticMetadataOption(md))
+	}
+}
+
+// WithProductToken prepends product token "name/version" to sdk version in version header of all api requests
+// (such as "myorm/1.2 ydb-go-sdk/3.53.3") for attribute traffic in server-side telemetry
+func WithProductToken(name, version string) Option {
+	return func(c *Config) {
+		c.metaOptions = append(c.metaOptions, meta.WithProductTokenOption(name+"/"+version))
+	}
+}
+
 // WithUserAgent add provided user agent to all api requests
 //
 // Deprecated: use WithApplicationName instead.
@@ -189,6 +429,15 @@ func WithUserAgent(userAgent string) Option {
 	}
 }
 
+// WithCompression enables compression of request messages of unary calls with size not less than minMessageSize
+// by registered gRPC compressor (such as "gzip"). Empty algorithm disables compression
+func WithCompression(algorithm string, minMessageSize int) Option {
+	return func(c *Config) {
+		c.compression = algorithm
+		c.compressionMinSize = minMessageSize
+	}
+}
+
 func WithConnectionTTL(ttl time.Duration) Option {
 	return func(c *Config) {
 		c.connectionTTL = ttl
@@ -271,6 +520,43 @@ func WithTLSSInsecureSkipVerify() Option {
 	}
 }
 
+// WithKeepaliveTime defines duration of inactivity of connection after which client sends keepalive ping.
+// Values lower than MinKeepaliveInterval are raised by grpc to MinKeepaliveInterval.
+//
+// Server enforces minimal interval between pings. Client which pings more often than server allows
+// receives GOAWAY with "too_many_pings" and loses connection, so keepalive time must not be lower than
+// server-side enforcement interval
+func WithKeepaliveTime(d time.Duration) Option {
+	return func(c *Config) {
+		c.keepalive.Time = d
+	}
+}
+
+// WithKeepaliveTimeout defines duration of waiting for keepalive ping ack before closing of connection
+func WithKeepaliveTimeout(d time.Duration) Option {
+	return func(c *Config) {
+		c.keepalive.Timeout = d
+	}
+}
+
+// WithKeepalivePermitWithoutStream defines sending of keepalive pings on connections without active streams.
+//
+// Server must permit pings without streams, otherwise server treats pings of idle connection as abuse
+// and closes connection with GOAWAY
+func WithKeepalivePermitWithoutStream(permit bool) Option {
+	return func(c *Config) {
+		c.keepalive.PermitWithoutStream = permit
+	}
+}
+
+// WithContextDialer defines custom dialer of network connections (such as in-memory or tunneled connections)
+// instead of default TCP dialer. Dialer used for discovery and for pooled connections to endpoints
+func WithContextDialer(dialer func(ctx context.Context, address string) (net.Conn, error)) Option {
+	return func(c *Config) {
+		c.contextDialer = dialer
+	}
+}
+
 // WithGrpcOptions appends custom grpc dial options to defaults
 func WithGrpcOptions(option ...grpc.DialOption) Option {
 	return func(c *Config) {
@@ -287,6 +573,28 @@ func ExcludeGRPCCodesForPessimization(codes ...grpcCodes.Code) Option {
 	}
 }
 
+// WithPessimizationFunc defines custom check of call error which must ban endpoint of call
+// instead of default check (conn.MustPessimizeEndpoint). Check receives codes from ExcludeGRPCCodesForPessimization
+func WithPessimizationFunc(f func(err error, excludeCodes ...grpcCodes.Code) bool) Option {
+	return func(c *Config) {
+		c.pessimizationFunc = f
+	}
+}
+
+// WithFailoverDatabase defines standby database (such as for disaster recovery) for failover of calls
+// if calls of primary database fails without successful call during failoverDelay. Calls fails back to primary database
+// after primary database has available connections during failbackDelay
+func WithFailoverDatabase(endpoint, database string, failoverDelay, failbackDelay time.Duration) Option {
+	return func(c *Config) {
+		c.failover = &failoverDatabase{
+			endpoint:      endpoint,
+			database:      database,
+			failoverDelay: failoverDelay,
+			failbackDelay: failbackDelay,
+		}
+	}
+}
+
 func New(opts ...Option) *Config {
 	c := defaultConfig()
 
@@ -17,9 +17,14 @@ import (
 	"github.com/ydb-platform/ydb-go-sdk/v3/trace"
 )
 
+// DefaultDialScheme is a scheme of grpc name resolver which interprets endpoints as for dns resolver
+const DefaultDialScheme = "ydb"
+
 var (
-	// DefaultKeepaliveInterval contains default duration between grpc keepalive
-	DefaultKeepaliveInterval    = 10 * time.Second
+	// DefaultKeepaliveInterval contains default duration between grpc keepalive.
+	// Default interval is much lower than typical idle timeouts (such as 5 minutes) of intermediary load balancers
+	DefaultKeepaliveInterval = 10 * time.Second
+	// MinKeepaliveInterval is a minimal duration between grpc keepalive. Lower values are raised by grpc to this one
 	MinKeepaliveInterval        = 10 * time.Second
 	DefaultDialTimeout          = 5 * time.Second
 	DefaultGRPCMsgSize          = 64 * 1024 * 1024 // 64MB
@@ -30,11 +35,13 @@ var (
 	}
 )
 
-func defaultGrpcOptions(t *trace.Driver, secure bool, tlsConfig *tls.Config) (opts []grpc.DialOption) {
+func defaultGrpcOptions(
+	t *trace.Driver, secure bool, tlsConfig *tls.Config, keepaliveParams keepalive.ClientParameters,
+) (opts []grpc.DialOption) {
 	opts = append(opts,
 		// keep-aliving all connections
 		grpc.WithKeepaliveParams(
-			DefaultGrpcConnectionPolicy,
+			keepaliveParams,
 		),
 		// use round robin balancing policy for fastest dialing
 		grpc.WithDefaultServiceConfig(`{
@@ -92,6 +99,7 @@ func defaultConfig() (c *Config) {
 		balancerConfig: balancers.Default(),
 		tlsConfig:      defaultTLSConfig(),
 		dialTimeout:    DefaultDialTimeout,
+		keepalive:      DefaultGrpcConnectionPolicy,
 		trace:          &trace.Driver{},
 	}
 }
@@ -4,6 +4,9 @@ import (
 	"context"
 	"time"
 
+	grpcCodes "google.golang.org/grpc/codes"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/conn"
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/operation"
 )
 
@@ -19,3 +22,13 @@ func WithOperationTimeout(ctx context.Context, operationTimeout time.Duration) c
 func WithOperationCancelAfter(ctx context.Context, operationCancelAfter time.Duration) context.Context {
 	return operation.WithCancelAfter(ctx, operationCancelAfter)
 }
+
+// WithPessimizationCodes returns a copy of parent context with grpc codes which triggers pessimization
+// (ban) of endpoint on transport errors of calls with this context.
+// Codes from context overrides codes from config.ExcludeGRPCCodesForPessimization option for this calls only.
+// Empty codes list disables pessimization of endpoint for calls with this context
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithPessimizationCodes(ctx context.Context, codes ...grpcCodes.Code) context.Context {
+	return conn.WithPessimizationCodes(ctx, codes...)
+}
@@ -96,7 +96,7 @@ type Driver struct {
 	pool *conn.Pool
 
 	mtx      sync.Mutex
-	balancer *balancer.Balancer
+	balancer driverBalancer
 
 	children    map[uint64]*Driver
 	childrenMtx xsync.Mutex
@@ -105,6 +105,38 @@ type Driver struct {
 	panicCallback func(e interface{})
 }
 
+// driverBalancer is a balancer of single database or failover balancer of primary and standby databases
+type driverBalancer interface {
+	grpc.ClientConnInterface
+
+	BeginShutdown()
+	Shutdown(ctx context.Context) error
+	Close(ctx context.Context) error
+
+	ForceDiscovery(ctx context.Context) ([]endpoint.Info, error)
+	CircuitBreakerStates() map[string]balancer.CircuitBreakerState
+	BannedEndpoints() []balancer.BannedEndpoint
+	OnLocalDCChange(onLocalDCChange func(ctx context.Context, oldDC, newDC string))
+	Healthy() (healthy bool, reason string)
+	RecycleEndpoint(ctx context.Context, e endpoint.Info) error
+	ExportState() ([]byte, error)
+	Unban(ctx context.Context, e endpoint.Info) bool
+	UnbanAll(ctx context.Context) int
+	ClusterInfo() balancer.ClusterInfo
+	DiscoveryHistory() []balancer.DiscoveryEvent
+	InvokeWithInfo(
+		ctx context.Context, method string, args interface{}, reply interface{}, opts ...grpc.CallOption,
+	) (endpoint.Info, error)
+	OnBan(onBan func(ctx context.Context, e trace.EndpointInfo, err error))
+	WaitReady(ctx context.Context) error
+	Stats() balancer.BalancerStats
+}
+
+var (
+	_ driverBalancer = (*balancer.Balancer)(nil)
+	_ driverBalancer = (*balancer.MultiDatabaseBalancer)(nil)
+)
+
 func (d *Driver) trace() *trace.Driver {
 	if d.config != nil {
 		return d.config.Trace()
@@ -123,6 +155,32 @@ func (d *Driver) Close(ctx context.Context) (finalErr error) {
 	defer func() {
 		onDone(finalErr)
 	}()
+
+	return d.close(ctx, false)
+}
+
+// Shutdown gracefully closes Driver: new calls immediately fail with balancers.ErrShuttingDown,
+// in-flight calls continue until completion or done of ctx. After that Driver closes and clear resources
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+//
+//nolint:nonamedreturns
+func (d *Driver) Shutdown(ctx context.Context) (finalErr error) {
+	onDone := trace.DriverOnClose(d.trace(), &ctx,
+		stack.FunctionID("github.com/ydb-platform/ydb-go-sdk/v3/ydb.(*Driver).Shutdown"),
+	)
+	defer func() {
+		onDone(finalErr)
+	}()
+
+	d.BeginShutdown()
+
+	return d.close(ctx, true)
+}
+
+// close closes children drivers, clients and balancer of Driver. Graceful close waits for in-flight calls
+// of balancer before close of clients
+func (d *Driver) close(ctx context.Context, graceful bool) error {
 	d.ctxCancel()
 
 	d.mtx.Lock()
@@ -139,11 +197,21 @@ func (d *Driver) Close(ctx context.Context) (finalErr error) {
 	closes := make([]func(context.Context) error, 0)
 	d.childrenMtx.WithLock(func() {
 		for _, child := range d.children {
-			closes = append(closes, child.Close)
+			if graceful {
+				closes = append(closes, child.Shutdown)
+			} else {
+				closes = append(closes, child.Close)
+			}
 		}
 		d.children = nil
 	})
 
+	closeBalancer := d.balancer.Close
+	if graceful {
+		closes = append(closes, d.balancer.Shutdown)
+		closeBalancer = func(context.Context) error { return nil }
+	}
+
 	closes = append(
 		closes,
 		d.ratelimiter.Close,
@@ -155,7 +223,7 @@ func (d *Driver) Close(ctx context.Context) (finalErr error) {
 		d.query.Close,
 		d.topic.Close,
 		d.discovery.Close,
-		d.balancer.Close,
+		closeBalancer,
 		d.pool.Release,
 	)
 
@@ -173,6 +241,22 @@ func (d *Driver) Close(ctx context.Context) (finalErr error) {
 	return nil
 }
 
+// BeginShutdown switches Driver to fast-fail mode for graceful shutdown of process: new calls immediately
+// fail with balancers.ErrShuttingDown, but in-flight calls continue until Close
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func (d *Driver) BeginShutdown() {
+	d.childrenMtx.WithLock(func() {
+		for _, child := range d.children {
+			child.BeginShutdown()
+		}
+	})
+
+	if d.balancer != nil {
+		d.balancer.BeginShutdown()
+	}
+}
+
 // Endpoint returns initial endpoint
 func (d *Driver) Endpoint() string {
 	return d.config.Endpoint()
@@ -274,7 +358,9 @@ func Open(ctx context.Context, dsn string, opts ...Option) (_ *Driver, _ error)
 	}()
 
 	if err = d.connect(ctx); err != nil {
-		_ = d.pool.Release(ctx)
+		if d.pool != nil {
+			_ = d.pool.Release(ctx)
+		}
 
 		return nil, xerrors.WithStackTrace(err)
 	}
@@ -402,6 +488,12 @@ func (d *Driver) connect(ctx context.Context) (err error) {
 		return xerrors.WithStackTrace(errors.New("configuration: empty database")) //nolint:goerr113
 	}
 
+	if !d.config.Secure() && d.config.HasTLSOptions() {
+		return xerrors.WithStackTrace(errors.New( //nolint:goerr113
+			"configuration: TLS config or client certificate defined for insecure connection",
+		))
+	}
+
 	if d.userInfo != nil {
 		d.config = d.config.With(config.WithCredentials(
 			credentials.NewStaticCredentials(
@@ -416,8 +508,7 @@ func (d *Driver) connect(ctx context.Context) (err error) {
 		d.pool = conn.NewPool(ctx, d.config)
 	}
 
-	d.balancer, err = balancer.New(ctx, d.config, d.pool, d.discoveryOptions...)
-	if err != nil {
+	if d.balancer, err = d.newBalancer(ctx); err != nil {
 		return xerrors.WithStackTrace(err)
 	}
 
@@ -558,6 +649,24 @@ func (d *Driver) connect(ctx context.Context) (err error) {
 	return nil
 }
 
+// newBalancer makes balancer of database or failover balancer of primary and standby databases
+// if standby database defined
+func (d *Driver) newBalancer(ctx context.Context) (driverBalancer, error) {
+	primary, err := balancer.New(ctx, d.config, d.pool, d.discoveryOptions...)
+	if err != nil {
+		return nil, xerrors.WithStackTrace(err)
+	}
+
+	standbyConfig, failoverDelay, failbackDelay, has := d.config.FailoverDatabase()
+	if !has {
+		return primary, nil
+	}
+
+	return balancer.NewMultiDatabase(primary, func(ctx context.Context) (*balancer.Balancer, error) {
+		return balancer.New(ctx, standbyConfig, d.pool, d.discoveryOptions...)
+	}, failoverDelay, failbackDelay), nil
+}
+
 // GRPCConn casts *ydb.Driver to grpc.ClientConnInterface for executing
 // unary and streaming RPC over internal driver balancer.
 //
@@ -0,0 +1,140 @@
+package ydb
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/balancer"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/endpoint"
+	"github.com/ydb-platform/ydb-go-sdk/v3/trace"
+)
+
+// ForceDiscovery runs cluster discovery of driver balancer synchronously and returns endpoints which applied
+// to balancer state. With failover database (WithFailoverDatabase) discovery runs for active database
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func (d *Driver) ForceDiscovery(ctx context.Context) ([]endpoint.Info, error) {
+	return d.balancer.ForceDiscovery(ctx)
+}
+
+// Stats returns snapshot of driver balancer state (connections by state, last discovery, open streams).
+// With failover database (WithFailoverDatabase) stats describes active database
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func (d *Driver) Stats() balancer.BalancerStats {
+	return d.balancer.Stats()
+}
+
+// WaitReady blocks until driver balancer has at least one usable (not banned) connection or ctx done
+// (such as for readiness probes of service)
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func (d *Driver) WaitReady(ctx context.Context) error {
+	return d.balancer.WaitReady(ctx)
+}
+
+// OnBan registers callback which calls on ban of endpoint of driver balancer after failed call.
+// Callback receives error of call which caused the ban
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func (d *Driver) OnBan(onBan func(ctx context.Context, e trace.EndpointInfo, err error)) {
+	d.balancer.OnBan(onBan)
+}
+
+// InvokeWithInfo invokes unary call with driver balancer and returns info of endpoint which served the call
+// (such as node ID and location for audit logs). Info is nil if balancer cannot choose connection
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func (d *Driver) InvokeWithInfo(
+	ctx context.Context,
+	method string,
+	args interface{},
+	reply interface{},
+	opts ...grpc.CallOption,
+) (endpoint.Info, error) {
+	return d.balancer.InvokeWithInfo(ctx, method, args, reply, opts...)
+}
+
+// DiscoveryHistory returns last applied discovery results of driver balancer from oldest to newest.
+// Discovery history is empty if it not enabled with balancer option balancers.WithDiscoveryHistory
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func (d *Driver) DiscoveryHistory() []balancer.DiscoveryEvent {
+	return d.balancer.DiscoveryHistory()
+}
+
+// ClusterInfo returns info of cluster from last successful discovery of driver balancer.
+// ClusterInfo is zero if no one discovery completed
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func (d *Driver) ClusterInfo() balancer.ClusterInfo {
+	return d.balancer.ClusterInfo()
+}
+
+// Unban allows banned connection of driver balancer to endpoint immediately without waiting of discovery
+// or probes. Returns false if connection to endpoint not banned
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func (d *Driver) Unban(ctx context.Context, e endpoint.Info) bool {
+	return d.balancer.Unban(ctx, e)
+}
+
+// UnbanAll allows all banned connections of driver balancer immediately without waiting of discovery or probes
+// (such as after fix of network issue). Returns count of unbanned connections
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func (d *Driver) UnbanAll(ctx context.Context) int {
+	return d.balancer.UnbanAll(ctx)
+}
+
+// ExportState serializes discovered endpoints and local DC of driver balancer into versioned snapshot.
+// Snapshot seeds driver balancer of other process (such as on blue/green deploy) with balancer option
+// balancers.WithInitialState
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func (d *Driver) ExportState() ([]byte, error) {
+	return d.balancer.ExportState()
+}
+
+// RecycleEndpoint closes connection of driver balancer to known endpoint without removing endpoint
+// from balancer. Connection will be lazily re-dialed on next use (such as after credentials or network changes)
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func (d *Driver) RecycleEndpoint(ctx context.Context, e endpoint.Info) error {
+	return d.balancer.RecycleEndpoint(ctx, e)
+}
+
+// Healthy reports about degraded state of driver balancer with reason of degradation (such as for health
+// endpoint of service). Driver balancer is degraded if it has no available connections, serves calls from
+// fallback connections outside local DC or from cached endpoints, recent discovery attempts failed
+// or calls served by failover database (WithFailoverDatabase)
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func (d *Driver) Healthy() (healthy bool, reason string) {
+	return d.balancer.Healthy()
+}
+
+// OnLocalDCChange registers callback which calls on change of detected local DC of driver balancer
+// between discovery cycles. Callback not calls on first detection of local DC
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func (d *Driver) OnLocalDCChange(onLocalDCChange func(ctx context.Context, oldDC, newDC string)) {
+	d.balancer.OnLocalDCChange(onLocalDCChange)
+}
+
+// BannedEndpoints returns currently banned endpoints of driver balancer connections
+// with time and cause of ban
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func (d *Driver) BannedEndpoints() []balancer.BannedEndpoint {
+	return d.balancer.BannedEndpoints()
+}
+
+// CircuitBreakerStates returns states of per-endpoint circuit breakers of driver balancer by endpoint address.
+// States are empty if circuit breaker not enabled with balancer option balancers.WithCircuitBreaker
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func (d *Driver) CircuitBreakerStates() map[string]balancer.CircuitBreakerState {
+	return d.balancer.CircuitBreakerStates()
+}
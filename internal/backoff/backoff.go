@@ -2,6 +2,7 @@ package backoff
 
 import (
 	"math"
+	"sync/atomic"
 	"time"
 
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xrand"
@@ -52,6 +53,13 @@ type logBackoff struct {
 	// duration D; and R is a random sized part from [0,(D - F)].
 	jitterLimit float64
 
+	// jitter is a strategy of randomization of Backoff Delay.
+	// If jitter is not NoJitter, then jitterLimit is ignored.
+	jitter Jitter
+
+	// previous is a Delay returned for previous attempt, used by DecorrelatedJitter
+	previous *atomic.Int64
+
 	// generator of jitter
 	r xrand.Rand
 }
@@ -101,6 +109,12 @@ func (b logBackoff) Delay(i int) time.Duration {
 	if s <= 0 {
 		s = time.Second
 	}
+	switch b.jitter {
+	case FullJitter:
+		return b.fullJitterDelay(s, i)
+	case DecorrelatedJitter:
+		return b.decorrelatedJitterDelay(s, i)
+	}
 	n := 1 << min(uint(i), max(1, b.ceiling))
 	d := s * time.Duration(n)
 	f := time.Duration(math.Min(1, math.Abs(b.jitterLimit)) * float64(d))
@@ -0,0 +1,83 @@
+package backoff
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// Jitter reports how to randomize Backoff Delay
+type Jitter uint8
+
+const (
+	// NoJitter keeps Backoff Delay as is
+	NoJitter = Jitter(iota)
+
+	// FullJitter makes Backoff Delay as random duration from [0, D],
+	// where D is a calculated (without jitter) exponential Delay
+	FullJitter
+
+	// DecorrelatedJitter makes Backoff Delay as random duration from [S, 3*P] limited by maximum Delay,
+	// where S is a slot duration and P is a Delay returned for previous attempt (S for first attempt)
+	DecorrelatedJitter
+)
+
+func (j Jitter) String() string {
+	switch j {
+	case NoJitter:
+		return "no jitter"
+	case FullJitter:
+		return "full jitter"
+	case DecorrelatedJitter:
+		return "decorrelated jitter"
+	default:
+		return fmt.Sprintf("unknown jitter %d", j)
+	}
+}
+
+// WithJitter returns Backoff with applied jitter strategy.
+// Jitter applies only to Backoff created with New, other Backoff returns as is.
+// DecorrelatedJitter Backoff remembers previous Delay, so it must not be shared between retry loops
+func WithJitter(b Backoff, j Jitter) Backoff {
+	if lb, ok := b.(logBackoff); ok {
+		lb.jitter = j
+		if j == DecorrelatedJitter {
+			lb.previous = &atomic.Int64{}
+		}
+
+		return lb
+	}
+
+	return b
+}
+
+// fullJitterDelay returns random Delay from [0, D] of i-th attempt
+func (b logBackoff) fullJitterDelay(s time.Duration, i int) time.Duration {
+	d := s * time.Duration(1<<min(uint(i), max(1, b.ceiling)))
+
+	return time.Duration(b.r.Int64(int64(d) + 1))
+}
+
+// decorrelatedJitterDelay returns random Delay from [S, 3*P] of i-th attempt limited by maximum Delay,
+// where P is a Delay returned for previous attempt
+func (b logBackoff) decorrelatedJitterDelay(s time.Duration, i int) time.Duration {
+	var (
+		limit    = s * time.Duration(1<<max(1, b.ceiling))
+		previous = s
+	)
+	if i > 0 && b.previous != nil {
+		if p := time.Duration(b.previous.Load()); p > 0 {
+			previous = p
+		}
+	}
+	upper := 3 * previous //nolint:gomnd
+	if upper > limit {
+		upper = limit
+	}
+	d := s + time.Duration(b.r.Int64(int64(upper-s)+1))
+	if b.previous != nil {
+		b.previous.Store(int64(d))
+	}
+
+	return d
+}
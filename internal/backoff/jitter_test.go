@@ -0,0 +1,90 @@
+package backoff
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJitter(t *testing.T) {
+	for _, tt := range []struct {
+		jitter Jitter
+		bounds [][2]time.Duration
+	}{
+		{
+			jitter: FullJitter,
+			bounds: [][2]time.Duration{
+				{0, time.Second},
+				{0, 2 * time.Second},
+				{0, 4 * time.Second},
+				{0, 8 * time.Second},
+				{0, 8 * time.Second},
+				{0, 8 * time.Second},
+			},
+		},
+		{
+			jitter: DecorrelatedJitter,
+			bounds: [][2]time.Duration{
+				{time.Second, 3 * time.Second},
+				{time.Second, 8 * time.Second},
+				{time.Second, 8 * time.Second},
+				{time.Second, 8 * time.Second},
+				{time.Second, 8 * time.Second},
+				{time.Second, 8 * time.Second},
+			},
+		},
+	} {
+		t.Run(tt.jitter.String(), func(t *testing.T) {
+			for seed := int64(0); seed < 100; seed++ {
+				var (
+					b = WithJitter(New(
+						WithSlotDuration(time.Second),
+						WithCeiling(3),
+						WithSeed(seed),
+					), tt.jitter)
+					same = WithJitter(New(
+						WithSlotDuration(time.Second),
+						WithCeiling(3),
+						WithSeed(seed),
+					), tt.jitter)
+				)
+				for i, bounds := range tt.bounds {
+					act := b.Delay(i)
+					require.GreaterOrEqual(t, act, bounds[0], fmt.Sprintf("seed=%d, i=%d", seed, i))
+					require.LessOrEqual(t, act, bounds[1], fmt.Sprintf("seed=%d, i=%d", seed, i))
+					require.Equal(t, act, same.Delay(i), fmt.Sprintf("seed=%d, i=%d", seed, i))
+				}
+			}
+		})
+	}
+	t.Run("DecorrelatedJitterPreviousDelay", func(t *testing.T) {
+		for seed := int64(0); seed < 100; seed++ {
+			b := WithJitter(New(
+				WithSlotDuration(time.Second),
+				WithCeiling(3),
+				WithSeed(seed),
+			), DecorrelatedJitter)
+			previous := time.Second
+			for i := 0; i < 10; i++ {
+				upper := 3 * previous
+				if upper > 8*time.Second {
+					upper = 8 * time.Second
+				}
+				act := b.Delay(i)
+				require.GreaterOrEqual(t, act, time.Second, fmt.Sprintf("seed=%d, i=%d", seed, i))
+				require.LessOrEqual(t, act, upper, fmt.Sprintf("seed=%d, i=%d", seed, i))
+				previous = act
+			}
+		}
+	})
+	t.Run("NoJitter", func(t *testing.T) {
+		b := WithJitter(New(
+			WithSlotDuration(time.Second),
+			WithCeiling(3),
+			WithJitterLimit(1),
+		), NoJitter)
+		require.Equal(t, 4*time.Second, b.Delay(2))
+	})
+}
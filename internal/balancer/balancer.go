@@ -3,10 +3,16 @@ package balancer
 import (
 	"context"
 	"fmt"
+	"math"
+	"slices"
 	"strings"
+	"sync"
 	"sync/atomic"
+	"time"
 
+	"github.com/jonboulle/clockwork"
 	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/ydb-platform/ydb-go-sdk/v3/config"
 	balancerConfig "github.com/ydb-platform/ydb-go-sdk/v3/internal/balancer/config"
@@ -16,6 +22,7 @@ import (
 	internalDiscovery "github.com/ydb-platform/ydb-go-sdk/v3/internal/discovery"
 	discoveryConfig "github.com/ydb-platform/ydb-go-sdk/v3/internal/discovery/config"
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/endpoint"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/meta"
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/repeater"
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/stack"
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xcontext"
@@ -26,7 +33,26 @@ import (
 	"github.com/ydb-platform/ydb-go-sdk/v3/trace"
 )
 
-var ErrNoEndpoints = xerrors.Wrap(fmt.Errorf("no endpoints"))
+var (
+	// ErrNoEndpoints returned if balancer have no discovered endpoints
+	ErrNoEndpoints = conn.ErrNoEndpoints
+
+	// ErrAllEndpointsBanned returned if balancer have discovered endpoints but all of them banned or unavailable.
+	// ErrAllEndpointsBanned wraps ErrNoEndpoints
+	ErrAllEndpointsBanned = conn.ErrAllEndpointsBanned
+
+	// ErrTooManyEndpoints returned if discovery response contains more endpoints than allowed by discovery config
+	ErrTooManyEndpoints = xerrors.Wrap(fmt.Errorf("too many endpoints"))
+
+	// ErrClosed returned on call of balancer after start of balancer shutdown
+	ErrClosed = xerrors.Wrap(fmt.Errorf("balancer closed"))
+
+	// ErrUnknownEndpoint returned on recycle of endpoint which not known by balancer
+	ErrUnknownEndpoint = xerrors.Wrap(fmt.Errorf("unknown endpoint"))
+)
+
+// loadFactorThreshold is a maximum change of endpoint load factor which not treats as change of topology
+const loadFactorThreshold = 0.1
 
 type discoveryClient interface {
 	closer.Closer
@@ -42,10 +68,50 @@ type Balancer struct {
 	discoveryRepeater repeater.Repeater
 	localDCDetector   func(ctx context.Context, endpoints []endpoint.Endpoint) (string, error)
 
+	// maxEndpoints is a maximum count of endpoints in discovery response, zero value means no limit
+	maxEndpoints int
+	// discoveryRetryOptions applies over default options of cluster discovery retries
+	discoveryRetryOptions []retry.Option
+	// rawDiscoveryHandler handles state snapshot after every successful cluster discovery, nil if not defined
+	rawDiscoveryHandler func(response []byte)
+
 	connectionsState atomic.Pointer[connectionsState]
+	inFallback       atomic.Bool
+	shuttingDown     atomic.Bool
+	circuitBreakers  *circuitBreakers
+	drainer          *drainer
+	warmer           *warmer
+	readiness        *readinessGate
+	banRecovery      *banRecovery
+	discoveryHistory *discoveryHistory
+
+	// calls counts in-flight calls for graceful shutdown
+	calls inFlightCalls
+	// streams counts open streams through balancer connections
+	streams streamsCounter
+	// releasePool releases connections pool once on shutdown, nil if balancer not takes pool
+	releasePool *sync.Once
+
+	// rotation is a counter of discovery cycles for rotate selection of endpoints with limited connections
+	rotation atomic.Uint64
 
 	mu                         xsync.RWMutex
 	onApplyDiscoveredEndpoints []func(ctx context.Context, endpoints []endpoint.Info)
+	onBan                      []func(ctx context.Context, e trace.EndpointInfo, err error)
+	onLocalDCChange            []func(ctx context.Context, oldDC, newDC string)
+	lastDiscovery              time.Time
+	clusterInfo                ClusterInfo
+	updated                    chan struct{}
+
+	// fromCache is true if active state built from cached endpoints and no one discovery succeeded after that
+	fromCache bool
+	// discoveryFailures is a count of failed discovery attempts after last successful discovery
+	discoveryFailures int
+	// partialDiscoveries is a count of consecutive skipped partial discovery results
+	partialDiscoveries int
+	// localDC is a local DC of last applied state, localDCApplied is false before first applied state
+	localDC        string
+	localDCApplied bool
 }
 
 func (b *Balancer) OnUpdate(onApplyDiscoveredEndpoints func(ctx context.Context, endpoints []endpoint.Info)) {
@@ -54,48 +120,189 @@ func (b *Balancer) OnUpdate(onApplyDiscoveredEndpoints func(ctx context.Context,
 	})
 }
 
+// clock returns source of time of balancer
+func (b *Balancer) clock() clockwork.Clock {
+	if b.config.Clock != nil {
+		return b.config.Clock
+	}
+
+	return clockwork.NewRealClock()
+}
+
+// OnBan registers callback which calls on ban of endpoint after failed call.
+// Callback receives error of call which caused the ban
+func (b *Balancer) OnBan(onBan func(ctx context.Context, e trace.EndpointInfo, err error)) {
+	b.mu.WithLock(func() {
+		b.onBan = append(b.onBan, onBan)
+	})
+}
+
+// OnLocalDCChange registers callback which calls on change of detected local DC between discovery cycles.
+// Callback not calls on first detection of local DC
+func (b *Balancer) OnLocalDCChange(onLocalDCChange func(ctx context.Context, oldDC, newDC string)) {
+	b.mu.WithLock(func() {
+		b.onLocalDCChange = append(b.onLocalDCChange, onLocalDCChange)
+	})
+}
+
+func (b *Balancer) ban(ctx context.Context, cc conn.Conn, err error) {
+	if b.customPessimization(ctx) {
+		b.pool.Pessimize(ctx, cc, err)
+	} else {
+		b.pool.Ban(ctx, cc, err)
+	}
+
+	if cc.GetState() == conn.Banned {
+		b.banRecovery.probe(cc)
+	}
+
+	b.mu.RLock()
+	onBan := b.onBan
+	b.mu.RUnlock()
+
+	for _, f := range onBan {
+		f(ctx, cc.Endpoint(), err)
+	}
+}
+
+// UnbanAll allows all banned connections of balancer immediately without waiting of discovery or probes
+// (such as after fix of network issue). Returns count of unbanned connections
+func (b *Balancer) UnbanAll(ctx context.Context) (unbanned int) {
+	return b.unban(ctx, func(e endpoint.Info) bool {
+		return true
+	})
+}
+
+// Unban allows banned connection to endpoint immediately without waiting of discovery or probes.
+// Returns false if connection to endpoint not banned
+func (b *Balancer) Unban(ctx context.Context, e endpoint.Info) bool {
+	return b.unban(ctx, func(info endpoint.Info) bool {
+		return info.Address() == e.Address() && info.NodeID() == e.NodeID()
+	}) > 0
+}
+
+// RecycleEndpoint closes connection to known endpoint without removing endpoint from balancer.
+// Connection will be lazily re-dialed on next use (such as after credentials or network changes)
+func (b *Balancer) RecycleEndpoint(ctx context.Context, e endpoint.Info) (finalErr error) {
+	onDone := trace.DriverOnBalancerRecycleEndpoint(
+		b.driverConfig.Trace(), &ctx,
+		stack.FunctionID("github.com/ydb-platform/ydb-go-sdk/v3/internal/balancer.(*Balancer).RecycleEndpoint"),
+		endpoint.New(e.Address(), endpoint.WithID(e.NodeID())),
+	)
+	defer func() {
+		onDone(finalErr)
+	}()
+
+	state := b.connections()
+	if state != nil {
+		for _, known := range state.endpoints {
+			if known.Address() != e.Address() || known.NodeID() != e.NodeID() {
+				continue
+			}
+			if err := b.pool.Recycle(ctx, b.pool.Get(known)); err != nil {
+				return xerrors.WithStackTrace(err)
+			}
+
+			return nil
+		}
+	}
+
+	return xerrors.WithStackTrace(fmt.Errorf("%w: %s (node %d)", ErrUnknownEndpoint, e.Address(), e.NodeID()))
+}
+
+func (b *Balancer) unban(ctx context.Context, match func(e endpoint.Info) bool) (unbanned int) {
+	state := b.connections()
+	if state == nil {
+		return 0
+	}
+
+	for _, e := range state.endpoints {
+		if !match(e) {
+			continue
+		}
+		if cc := b.pool.Get(e); cc.GetState() == conn.Banned {
+			b.pool.Allow(ctx, cc)
+			unbanned++
+		}
+	}
+
+	return unbanned
+}
+
 func (b *Balancer) clusterDiscovery(ctx context.Context) (err error) {
 	return retry.Retry(
 		repeater.WithEvent(ctx, repeater.EventInit),
 		func(childCtx context.Context) (err error) {
 			if err = b.clusterDiscoveryAttempt(childCtx); err != nil {
-				if credentials.IsAccessError(err) {
-					return credentials.AccessError("cluster discovery failed", err,
-						credentials.WithEndpoint(b.driverConfig.Endpoint()),
-						credentials.WithDatabase(b.driverConfig.Database()),
-						credentials.WithCredentials(b.driverConfig.Credentials()),
-					)
-				}
-				// if got err but parent context is not done - mark error as retryable
-				if ctx.Err() == nil && xerrors.IsTimeoutError(err) {
-					return xerrors.WithStackTrace(xerrors.Retryable(err))
-				}
-
-				return xerrors.WithStackTrace(err)
+				return b.wrapClusterDiscoveryError(ctx, err)
 			}
 
 			return nil
 		},
-		retry.WithIdempotent(true),
-		retry.WithTrace(b.driverConfig.TraceRetry()),
-		retry.WithBudget(b.driverConfig.RetryBudget()),
+		append([]retry.Option{
+			retry.WithIdempotent(true),
+			retry.WithTrace(b.driverConfig.TraceRetry()),
+			retry.WithBudget(b.driverConfig.RetryBudget()),
+		}, b.discoveryRetryOptions...)...,
 	)
 }
 
-func (b *Balancer) clusterDiscoveryAttempt(ctx context.Context) (err error) {
+func (b *Balancer) wrapClusterDiscoveryError(ctx context.Context, err error) error {
+	if credentials.IsAccessError(err) {
+		return credentials.AccessError("cluster discovery failed", err,
+			credentials.WithEndpoint(b.driverConfig.Endpoint()),
+			credentials.WithDatabase(b.driverConfig.Database()),
+			credentials.WithCredentials(b.driverConfig.Credentials()),
+		)
+	}
+	// if got err but parent context is not done - mark error as retryable
+	if ctx.Err() == nil && xerrors.IsTimeoutError(err) {
+		return xerrors.WithStackTrace(xerrors.Retryable(err))
+	}
+
+	return xerrors.WithStackTrace(err)
+}
+
+// ForceDiscovery runs cluster discovery synchronously and returns endpoints which applied to balancer state
+func (b *Balancer) ForceDiscovery(ctx context.Context) (_ []endpoint.Info, err error) {
+	if b.config.SingleConn {
+		return xslices.Transform(b.connections().All(), func(e endpoint.Endpoint) endpoint.Info { return e }), nil
+	}
+
+	endpoints, err := b.clusterDiscoveryAttemptWithEndpoints(ctx)
+	if err != nil {
+		return nil, b.wrapClusterDiscoveryError(ctx, err)
+	}
+
+	return xslices.Transform(endpoints, func(e endpoint.Endpoint) endpoint.Info { return e }), nil
+}
+
+func (b *Balancer) clusterDiscoveryAttempt(ctx context.Context) error {
+	_, err := b.clusterDiscoveryAttemptWithEndpoints(ctx)
+
+	return err
+}
+
+func (b *Balancer) clusterDiscoveryAttemptWithEndpoints(ctx context.Context) (_ []endpoint.Endpoint, err error) {
 	var (
-		address = "ydb:///" + b.driverConfig.Endpoint()
+		address = b.driverConfig.DialTarget(b.driverConfig.Endpoint())
 		onDone  = trace.DriverOnBalancerClusterDiscoveryAttempt(
 			b.driverConfig.Trace(), &ctx,
 			stack.FunctionID(
-				"github.com/ydb-platform/ydb-go-sdk/v3/internal/balancer.(*Balancer).clusterDiscoveryAttempt"),
+				"github.com/ydb-platform/ydb-go-sdk/v3/internal/balancer.(*Balancer).clusterDiscoveryAttemptWithEndpoints"),
 			address,
 		)
 		endpoints []endpoint.Endpoint
-		localDC   string
+		info      balancerConfig.Info
 		cancel    context.CancelFunc
+		parentCtx = ctx
 	)
 	defer func() {
+		if err != nil {
+			b.mu.WithLock(func() {
+				b.discoveryFailures++
+			})
+		}
 		onDone(err)
 	}()
 
@@ -108,22 +315,229 @@ func (b *Balancer) clusterDiscoveryAttempt(ctx context.Context) (err error) {
 
 	endpoints, err = b.discoveryClient.Discover(ctx)
 	if err != nil {
-		return xerrors.WithStackTrace(err)
+		return nil, xerrors.WithStackTrace(err)
+	}
+
+	if b.maxEndpoints > 0 && len(endpoints) > b.maxEndpoints {
+		return nil, xerrors.WithStackTrace(fmt.Errorf("%w: discovered %d endpoints, maximum is %d",
+			ErrTooManyEndpoints, len(endpoints), b.maxEndpoints,
+		))
+	}
+
+	if previous, partial := b.partialDiscovery(ctx, endpoints); partial {
+		return previous, nil
 	}
 
 	if b.config.DetectNearestDC {
-		localDC, err = b.localDCDetector(ctx, endpoints)
+		info, err = b.detectLocalDC(ctx, parentCtx, endpoints)
 		if err != nil {
-			return xerrors.WithStackTrace(err)
+			return nil, xerrors.WithStackTrace(err)
 		}
 	}
 
-	b.applyDiscoveredEndpoints(ctx, endpoints, localDC)
+	b.applyDiscoveredEndpoints(ctx, endpoints, info)
 
-	return nil
+	b.mu.WithLock(func() {
+		b.lastDiscovery = b.clock().Now()
+		b.fromCache = false
+		b.discoveryFailures = 0
+		b.clusterInfo = ClusterInfo{
+			Endpoint:   b.driverConfig.Endpoint(),
+			Database:   b.driverConfig.Database(),
+			LocalDC:    info.SelfLocation,
+			Endpoints:  len(endpoints),
+			Discovered: b.lastDiscovery,
+		}
+	})
+
+	b.saveDiscoveryCache(endpoints)
+	b.handleRawDiscovery()
+
+	return endpoints, nil
+}
+
+// partialDiscovery checks that discovery result contains less than MinEndpointsFraction of previously
+// discovered endpoints. Partial result skips with previous endpoints until MaxPartialDiscoveries
+// consecutive partial results, next partial result applies as is.
+// With KeepStateOnEmptyDiscovery empty result treats as partial and skips until MaxEmptyDiscoveries
+// consecutive partial results
+func (b *Balancer) partialDiscovery(
+	ctx context.Context, endpoints []endpoint.Endpoint,
+) (previous []endpoint.Endpoint, partial bool) {
+	var (
+		fraction   = b.config.MinEndpointsFraction
+		empty      = b.config.KeepStateOnEmptyDiscovery && len(endpoints) == 0
+		maxPartial = balancerConfig.MaxPartialDiscoveries
+	)
+	if fraction <= 0 && !b.config.KeepStateOnEmptyDiscovery {
+		return nil, false
+	}
+	if empty {
+		maxPartial = balancerConfig.MaxEmptyDiscoveries
+	}
+
+	if state := b.connections(); state != nil {
+		previous = state.discovered
+	}
+
+	var attempts int
+	b.mu.WithLock(func() {
+		if len(previous) == 0 || !empty && (fraction <= 0 ||
+			float64(len(endpoints)) >= fraction*float64(len(previous))) {
+			b.partialDiscoveries = 0
+
+			return
+		}
+		b.partialDiscoveries++
+		attempts = b.partialDiscoveries
+		if attempts > maxPartial {
+			b.partialDiscoveries = 0
+		}
+	})
+	if attempts == 0 {
+		return nil, false
+	}
+
+	applied := attempts > maxPartial
+
+	trace.DriverOnBalancerPartialDiscovery(
+		b.driverConfig.Trace(), &ctx,
+		stack.FunctionID("github.com/ydb-platform/ydb-go-sdk/v3/internal/balancer.(*Balancer).partialDiscovery"),
+		len(previous), len(endpoints), attempts, applied,
+	)
+
+	return previous, !applied
+}
+
+// detectLocalDC detects local DC with own timeout if it defined, otherwise with deadline of discovery.
+// Local DC detection with own timeout not fails discovery on timeout, discovery uses endpoints without local DC.
+// Forced local DC from balancer config uses without detection.
+// If locality of endpoints defined - detection chooses nearest locality and local DC is a location of endpoints
+// with nearest locality
+func (b *Balancer) detectLocalDC(
+	discoveryCtx, parentCtx context.Context, endpoints []endpoint.Endpoint,
+) (info balancerConfig.Info, err error) {
+	if forced := b.config.ForcedLocalDC; forced != "" {
+		b.checkForcedLocalDC(discoveryCtx, endpoints, forced)
+
+		return balancerConfig.Info{SelfLocation: forced}, nil
+	}
+
+	locality := b.config.Locality
+	if locality == nil {
+		localDC, err := b.detectLocation(discoveryCtx, parentCtx, endpoints)
+
+		return balancerConfig.Info{SelfLocation: localDC}, err
+	}
+
+	nearest, err := b.detectLocation(discoveryCtx, parentCtx, localityEndpoints(endpoints, locality))
+	if err != nil || nearest == "" {
+		return balancerConfig.Info{}, err
+	}
+
+	for _, e := range endpoints {
+		if path := locality(e); localityKey(path) == nearest {
+			return balancerConfig.Info{SelfLocation: e.Location(), SelfLocality: path}, nil
+		}
+	}
+
+	return balancerConfig.Info{}, nil
+}
+
+// detectLocation detects location of nearest endpoints with local DC detector
+func (b *Balancer) detectLocation(
+	discoveryCtx, parentCtx context.Context, endpoints []endpoint.Endpoint,
+) (localDC string, err error) {
+	timeout := b.config.LocalDCDetectTimeout
+	if timeout <= 0 {
+		return b.localDCDetector(discoveryCtx, endpoints)
+	}
+
+	ctx, cancel := xcontext.WithTimeout(parentCtx, timeout)
+	defer cancel()
+
+	localDC, err = b.localDCDetector(ctx, endpoints)
+	if err != nil && ctx.Err() != nil && parentCtx.Err() == nil {
+		return "", nil
+	}
+
+	return localDC, err
+}
+
+// checkForcedLocalDC traces warning if no one endpoint located in forced local DC and fallback not allowed,
+// so balancer state will contain no one connection
+func (b *Balancer) checkForcedLocalDC(ctx context.Context, endpoints []endpoint.Endpoint, localDC string) {
+	if b.config.AllowFallback && !b.config.StrictLocalDC {
+		return
+	}
+
+	var (
+		locations []string
+		seen      = make(map[string]struct{}, len(endpoints))
+	)
+	for _, e := range endpoints {
+		if e.Location() == localDC {
+			return
+		}
+		if _, has := seen[e.Location()]; !has {
+			seen[e.Location()] = struct{}{}
+			locations = append(locations, e.Location())
+		}
+	}
+
+	trace.DriverOnBalancerLocalDCNotFound(
+		b.driverConfig.Trace(), &ctx,
+		stack.FunctionID("github.com/ydb-platform/ydb-go-sdk/v3/internal/balancer.(*Balancer).checkForcedLocalDC"),
+		localDC, locations,
+	)
 }
 
-func (b *Balancer) applyDiscoveredEndpoints(ctx context.Context, newest []endpoint.Endpoint, localDC string) {
+func (b *Balancer) applyDiscoveredEndpoints(
+	ctx context.Context, newest []endpoint.Endpoint, info balancerConfig.Info,
+) {
+	newest, duplicates := uniqueEndpoints(newest)
+	if len(duplicates) > 0 {
+		trace.DriverOnBalancerDuplicateEndpoints(b.driverConfig.Trace(), &ctx,
+			stack.FunctionID(
+				"github.com/ydb-platform/ydb-go-sdk/v3/internal/balancer.(*Balancer).applyDiscoveredEndpoints"),
+			xslices.Transform(duplicates, func(e endpoint.Endpoint) trace.EndpointInfo { return e }),
+		)
+	}
+
+	localDC, discovered := info.SelfLocation, newest
+
+	if rewriter := b.config.AddressRewriter; rewriter != nil {
+		newest = rewriteAddresses(newest, rewriter)
+	}
+
+	if filter := b.config.EndpointFilter; filter != nil {
+		newest = xslices.Filter(newest, func(e endpoint.Endpoint) bool {
+			return filter(e)
+		})
+	}
+
+	filter := b.config.Filter
+	if b.config.DetectNearestDC && localDC == "" {
+		// local DC not detected (such as on timeout of detection), all endpoints equally preferred
+		filter = nil
+	}
+
+	allowFallback := b.config.AllowFallback
+	if b.config.StrictLocalDC {
+		// strict local DC overrides fallback, so connections outside local DC never used
+		filter, allowFallback = strictLocalDCFilter{filter: filter}, false
+	}
+
+	if b.limitsConnections() {
+		rotation := int(b.rotation.Add(1) - 1)
+		if limit := b.config.RemoteDCConnectionLimit; limit > 0 && localDC != "" {
+			newest = limitRemoteDCEndpoints(newest, localDC, limit, rotation)
+		}
+		if maxConnections := b.config.MaxConnections; maxConnections > 0 {
+			newest = limitEndpoints(newest, filter, info, allowFallback, maxConnections, rotation)
+		}
+	}
+
 	var (
 		onDone = trace.DriverOnBalancerUpdate(
 			b.driverConfig.Trace(), &ctx,
@@ -137,22 +551,62 @@ func (b *Balancer) applyDiscoveredEndpoints(ctx context.Context, newest []endpoi
 		_, added, dropped := xslices.Diff(previous, newest, func(lhs, rhs endpoint.Endpoint) int {
 			return strings.Compare(lhs.Address(), rhs.Address())
 		})
+		droppedConns := endpointsToConnections(b.pool, dropped)
+		if b.drainer != nil {
+			b.drainer.drain(ctx, droppedConns, newest)
+		} else if b.limitsConnections() {
+			for _, c := range droppedConns {
+				_ = b.pool.CloseConn(ctx, c)
+			}
+		}
+		b.discoveryHistory.add(DiscoveryEvent{
+			Time:      b.clock().Now(),
+			Endpoints: len(newest),
+			LocalDC:   localDC,
+			Forced:    repeater.EventType(ctx) == repeater.EventForce,
+			Added:     xslices.Transform(added, func(e endpoint.Endpoint) string { return e.Address() }),
+			Dropped:   xslices.Transform(dropped, func(e endpoint.Endpoint) string { return e.Address() }),
+		})
+		stats := b.connections().stats()
 		onDone(
 			xslices.Transform(newest, func(t endpoint.Endpoint) trace.EndpointInfo { return t }),
 			xslices.Transform(added, func(t endpoint.Endpoint) trace.EndpointInfo { return t }),
 			xslices.Transform(dropped, func(t endpoint.Endpoint) trace.EndpointInfo { return t }),
 			localDC,
+			stats.PreferredConnections,
+			stats.FallbackConnections,
+			preferredAddressFamily(newest, b.config.AddressFamily),
 		)
 	}()
 
 	connections := endpointsToConnections(b.pool, newest)
 	for _, c := range connections {
 		b.pool.Allow(ctx, c)
-		c.Endpoint().Touch()
+		c.Endpoint().Touch(endpoint.WithLastUpdated(b.clock().Now()))
 	}
 
-	info := balancerConfig.Info{SelfLocation: localDC}
-	state := newConnectionsState(connections, b.config.Filter, info, b.config.AllowFallback)
+	// skip rebuild of state for same topology for avoid contention on b.mu in large clusters
+	if current := b.connections(); current != nil && current.localDC == localDC &&
+		slices.Equal(current.selfLocality, info.SelfLocality) && sameEndpoints(current.endpoints, newest) {
+		return
+	}
+
+	state := newConnectionsState(connections, filter, info, allowFallback,
+		withConnectionPicker(b.config.ConnectionPicker),
+		withLoadWeighting(b.config.LoadWeighting),
+		withSelectionMode(b.config.SelectionMode),
+		withEndpointComparator(b.config.EndpointComparator),
+		withEntryTimes(b.connections().entryTimes(), b.clock()),
+		withNewEndpointBias(b.config.NewEndpointBias, b.config.NewEndpointBiasWindow),
+		withFallbackTrace(b.driverConfig.Trace(), localDC, &b.inFallback),
+		withCircuitBreakers(b.circuitBreakers),
+		withReadinessGate(b.readiness),
+		withReadReplicaFilter(b.config.ReadReplicaFilter),
+		withDCPriority(b.config.DCPriority),
+		withAddressFamily(b.config.AddressFamily),
+		withLocality(b.config.Locality),
+		withEndpoints(newest, discovered),
+	)
 
 	endpointsInfo := make([]endpoint.Info, len(newest))
 	for i, e := range newest {
@@ -161,31 +615,36 @@ func (b *Balancer) applyDiscoveredEndpoints(ctx context.Context, newest []endpoi
 
 	b.connectionsState.Store(state)
 
+	b.readiness.update(connections)
+	b.warmer.warmup(connections)
+
 	b.mu.WithLock(func() {
+		previousLocalDC, localDCChanged := b.localDC, b.localDCApplied && b.localDC != localDC
+		b.localDC, b.localDCApplied = localDC, true
+
 		for _, onApplyDiscoveredEndpoints := range b.onApplyDiscoveredEndpoints {
 			onApplyDiscoveredEndpoints(ctx, endpointsInfo)
 		}
+		if localDCChanged {
+			for _, onLocalDCChange := range b.onLocalDCChange {
+				onLocalDCChange(ctx, previousLocalDC, localDC)
+			}
+		}
+		b.notifyUpdated()
 	})
 }
 
-func (b *Balancer) Close(ctx context.Context) (err error) {
-	onDone := trace.DriverOnBalancerClose(
-		b.driverConfig.Trace(), &ctx,
-		stack.FunctionID("github.com/ydb-platform/ydb-go-sdk/v3/internal/balancer.(*Balancer).Close"),
-	)
-	defer func() {
-		onDone(err)
-	}()
-
-	if b.discoveryRepeater != nil {
-		b.discoveryRepeater.Stop()
-	}
+// limitsConnections reports whether balancer materializes connections only to part of discovered endpoints
+func (b *Balancer) limitsConnections() bool {
+	return b.config.MaxConnections > 0 || b.config.RemoteDCConnectionLimit > 0
+}
 
-	if err = b.discoveryClient.Close(ctx); err != nil {
-		return xerrors.WithStackTrace(err)
-	}
+// Close closes balancer without waiting of in-flight calls
+func (b *Balancer) Close(ctx context.Context) error {
+	ctx, cancel := xcontext.WithCancel(ctx)
+	cancel()
 
-	return nil
+	return b.Shutdown(ctx)
 }
 
 func New(
@@ -200,25 +659,35 @@ func New(
 			stack.FunctionID("github.com/ydb-platform/ydb-go-sdk/v3/internal/balancer.New"),
 			driverConfig.Balancer().String(),
 		)
-		discoveryConfig = discoveryConfig.New(append(opts,
-			discoveryConfig.With(driverConfig.Common),
-			discoveryConfig.WithEndpoint(driverConfig.Endpoint()),
-			discoveryConfig.WithDatabase(driverConfig.Database()),
-			discoveryConfig.WithSecure(driverConfig.Secure()),
-			discoveryConfig.WithMeta(driverConfig.Meta()),
-		)...)
+		discoveryConfig = newDiscoveryConfig(driverConfig, driverConfig.Endpoint(), opts...)
 	)
 	defer func() {
 		onDone(finalErr)
 	}()
 
+	if err := pool.Take(ctx); err != nil {
+		return nil, xerrors.WithStackTrace(err)
+	}
+	defer func() {
+		if finalErr != nil {
+			// parent context may be cancelled (such as on cancellation of initial discovery),
+			// so discovery client and connections closes with own context
+			closeCtx, cancel := closeContext(ctx)
+			defer cancel()
+
+			_ = pool.Release(closeCtx)
+		}
+	}()
+
 	b = &Balancer{
-		driverConfig: driverConfig,
-		pool:         pool,
-		discoveryClient: internalDiscovery.New(ctx, pool.Get(
-			endpoint.New(driverConfig.Endpoint()),
-		), discoveryConfig),
+		driverConfig:    driverConfig,
+		pool:            pool,
 		localDCDetector: detectLocalDC,
+		maxEndpoints:    discoveryConfig.MaxEndpoints(),
+		releasePool:     &sync.Once{},
+
+		discoveryRetryOptions: discoveryConfig.RetryOptions(),
+		rawDiscoveryHandler:   discoveryConfig.RawResponseHandler(),
 	}
 
 	if config := driverConfig.Balancer(); config == nil {
@@ -227,28 +696,166 @@ func New(
 		b.config = *config
 	}
 
+	if newClient := b.config.DiscoveryClient; newClient != nil {
+		client, err := newClient(ctx)
+		if err != nil {
+			return nil, xerrors.WithStackTrace(err)
+		}
+		b.discoveryClient = client
+	} else if cc := b.config.DiscoveryConn; cc != nil {
+		b.discoveryClient = internalDiscovery.New(ctx, unclosableConn{cc}, discoveryConfig)
+	} else {
+		b.discoveryClient = newDiscoveryClient(ctx, driverConfig, pool, discoveryConfig, opts...)
+	}
+	defer func(client discoveryClient) {
+		if finalErr != nil {
+			closeCtx, cancel := closeContext(ctx)
+			defer cancel()
+
+			_ = client.Close(closeCtx)
+		}
+	}(b.discoveryClient)
+
+	if cb := b.config.CircuitBreaker; cb != nil {
+		b.circuitBreakers = newCircuitBreakers(*cb, b.clock(), driverConfig.Trace())
+	}
+
+	if timeout := b.config.DrainTimeout; timeout > 0 {
+		b.drainer = newDrainer(pool, timeout, b.clock())
+	}
+
+	if b.config.ConnectionWarmup {
+		b.warmer = newWarmer(ctx, warmupConcurrency)
+	}
+
+	if b.config.ReadinessCheck {
+		b.readiness = newReadinessGate(ctx, b.clock(), func(ctx context.Context, c conn.Conn) error {
+			return probeConnection(ctx, c, discoveryConfig).Error
+		})
+	}
+
+	if probe := b.config.BanRecoveryProbe; probe != nil {
+		b.banRecovery = newBanRecovery(ctx, probe.MinInterval, probe.MaxInterval, b.clock(),
+			driverConfig.Trace(), pool.Allow,
+		)
+	}
+
+	if size := b.config.DiscoveryHistory; size > 0 {
+		b.discoveryHistory = newDiscoveryHistory(size)
+	}
+
+	if b.config.LocalDCDetectionMode == balancerConfig.LatencyProbe {
+		b.localDCDetector = detectLocalDCByLatency(b.config.LatencyProbeCount, b.config.LatencyProbeTimeout,
+			b.config.LocalDCProbeConcurrency, b.config.LocalDCProbeEndpointsPerDC,
+		)
+	} else if b.config.LocalDCProbeConcurrency > 0 || b.config.LocalDCProbeEndpointsPerDC > 0 {
+		b.localDCDetector = detectLocalDCByFastestDial(
+			b.config.LocalDCProbeConcurrency, b.config.LocalDCProbeEndpointsPerDC,
+		)
+	}
+
 	if b.config.SingleConn {
 		b.applyDiscoveredEndpoints(ctx, []endpoint.Endpoint{
 			endpoint.New(driverConfig.Endpoint()),
-		}, "")
+		}, balancerConfig.Info{})
 	} else {
-		// initialization of balancer state
-		if err := b.clusterDiscovery(ctx); err != nil {
-			return nil, xerrors.WithStackTrace(err)
+		d := discoveryConfig.Interval()
+		// discovery cache used only with background discovering which replaces cached endpoints with fresh
+		forceDiscovery := d > 0 && (b.applyInitialState(ctx) || b.applyDiscoveryCache(ctx))
+		if !forceDiscovery {
+			if err := sleepInitialJitter(ctx, b.clock(), discoveryConfig.InitialJitter()); err != nil {
+				return nil, xerrors.WithStackTrace(err)
+			}
+			// initialization of balancer state
+			if b.config.StartupFallbackSingleConn && d > 0 {
+				forceDiscovery = b.startupDiscoveryWithFallback(ctx)
+			} else if err := b.clusterDiscovery(ctx); err != nil {
+				return nil, xerrors.WithStackTrace(err)
+			}
 		}
 		// run background discovering
-		if d := discoveryConfig.Interval(); d > 0 {
+		if d > 0 {
 			b.discoveryRepeater = repeater.New(xcontext.ValueOnly(ctx),
 				d, b.clusterDiscoveryAttempt,
 				repeater.WithName("discovery"),
 				repeater.WithTrace(b.driverConfig.Trace()),
+				repeater.WithIntervalStrategy(newAdaptiveDiscoveryInterval(d)),
+				repeater.WithClock(b.clock()),
 			)
+			if forceDiscovery {
+				b.discoveryRepeater.Force()
+			}
 		}
 	}
 
 	return b, nil
 }
 
+// startupDiscoveryWithFallback makes single attempt of cluster discovery on startup of balancer.
+// If attempt failed - balancer starts with single connection to initial endpoint and returns true
+// for force background discovering which replaces single connection with discovered endpoints
+func (b *Balancer) startupDiscoveryWithFallback(ctx context.Context) (fallback bool) {
+	if err := b.clusterDiscoveryAttempt(repeater.WithEvent(ctx, repeater.EventInit)); err == nil {
+		return false
+	}
+
+	b.applyDiscoveredEndpoints(ctx, []endpoint.Endpoint{
+		endpoint.New(b.driverConfig.Endpoint()),
+	}, balancerConfig.Info{})
+	b.setFromCache()
+
+	return true
+}
+
+// unclosableConn hides Close method of user-supplied connection, so discovery client never closes it
+type unclosableConn struct {
+	grpc.ClientConnInterface
+}
+
+func newDiscoveryClient(
+	ctx context.Context,
+	driverConfig *config.Config,
+	pool *conn.Pool,
+	cfg *discoveryConfig.Config,
+	opts ...discoveryConfig.Option,
+) discoveryClient {
+	discoveryConn := func(address string) grpc.ClientConnInterface {
+		if cfg.PersistentConnection() {
+			return newPersistentDiscoveryConn(driverConfig, address)
+		}
+
+		return pool.Get(endpoint.New(address))
+	}
+
+	bootstrap := driverConfig.BootstrapEndpoints()
+	if len(bootstrap) == 1 && bootstrap[0] == driverConfig.Endpoint() {
+		return internalDiscovery.New(ctx, discoveryConn(driverConfig.Endpoint()), cfg)
+	}
+
+	clients := make(bootstrapDiscoveryClients, 0, len(bootstrap))
+	for _, address := range bootstrap {
+		clients = append(clients, internalDiscovery.New(ctx, discoveryConn(address),
+			newDiscoveryConfig(driverConfig, address, opts...),
+		))
+	}
+
+	return clients
+}
+
+func newDiscoveryConfig(
+	driverConfig *config.Config,
+	address string,
+	opts ...discoveryConfig.Option,
+) *discoveryConfig.Config {
+	return discoveryConfig.New(append(opts,
+		discoveryConfig.With(driverConfig.Common),
+		discoveryConfig.WithEndpoint(address),
+		discoveryConfig.WithDatabase(driverConfig.Database()),
+		discoveryConfig.WithSecure(driverConfig.Secure()),
+		discoveryConfig.WithMeta(driverConfig.Meta()),
+	)...)
+}
+
 func (b *Balancer) Invoke(
 	ctx context.Context,
 	method string,
@@ -256,7 +863,39 @@ func (b *Balancer) Invoke(
 	reply interface{},
 	opts ...grpc.CallOption,
 ) error {
-	return b.wrapCall(ctx, func(ctx context.Context, cc conn.Conn) error {
+	_, err := b.InvokeWithInfo(ctx, method, args, reply, opts...)
+
+	return err
+}
+
+// InvokeWithInfo invokes unary call same as Invoke and returns info of endpoint which served the call
+// (such as node ID and location for audit logs). Info is nil if balancer cannot choose connection
+func (b *Balancer) InvokeWithInfo(
+	ctx context.Context,
+	method string,
+	args interface{},
+	reply interface{},
+	opts ...grpc.CallOption,
+) (endpoint.Info, error) {
+	if algorithm, minMessageSize := b.driverConfig.Compression(); algorithm != "" {
+		opts = withCompression(algorithm, minMessageSize, args, opts)
+	}
+
+	if delay, has := conn.Hedging(ctx); has && xcontext.IsIdempotent(ctx) && !isDirectCall(ctx) {
+		if msg, ok := reply.(proto.Message); ok {
+			invoke := func(ctx context.Context, cc conn.Conn, reply proto.Message) error {
+				return cc.Invoke(ctx, method, args, reply, opts...)
+			}
+
+			onDone := b.onCall(&ctx, method)
+			e, err := b.invokeHedged(b.withMethodPolicy(ctx, method), delay, msg, invoke)
+			onDone(e, err)
+
+			return e, err
+		}
+	}
+
+	return b.wrapCall(ctx, method, func(ctx context.Context, cc conn.Conn) error {
 		return cc.Invoke(ctx, method, args, reply, opts...)
 	})
 }
@@ -267,35 +906,168 @@ func (b *Balancer) NewStream(
 	method string,
 	opts ...grpc.CallOption,
 ) (_ grpc.ClientStream, err error) {
-	var client grpc.ClientStream
-	err = b.wrapCall(ctx, func(ctx context.Context, cc conn.Conn) error {
+	if conn.AutoReconnect(ctx) {
+		stream, err := newReconnectingStream(ctx, func(ctx context.Context) (grpc.ClientStream, conn.Conn, error) {
+			return b.newStream(ctx, desc, method, opts...)
+		}, b.ban)
+		if err != nil {
+			return nil, err
+		}
+
+		return stream, nil
+	}
+
+	stream, _, err := b.newStream(ctx, desc, method, opts...)
+
+	return stream, err
+}
+
+func (b *Balancer) newStream(
+	ctx context.Context,
+	desc *grpc.StreamDesc,
+	method string,
+	opts ...grpc.CallOption,
+) (_ grpc.ClientStream, _ conn.Conn, err error) {
+	var (
+		client grpc.ClientStream
+		opened conn.Conn
+	)
+	_, err = b.wrapCall(withStreamCall(ctx), method, func(ctx context.Context, cc conn.Conn) error {
 		client, err = cc.NewStream(ctx, desc, method, opts...)
+		if err != nil {
+			return err
+		}
+		opened = cc
 
-		return err
+		done := b.streams.open(cc.Endpoint().Address())
+		go func() {
+			<-client.Context().Done()
+			done()
+		}()
+
+		return nil
 	})
 	if err == nil {
-		return client, nil
+		return client, opened, nil
 	}
 
-	return nil, err
+	return nil, nil, err
 }
 
-func (b *Balancer) wrapCall(ctx context.Context, f func(ctx context.Context, cc conn.Conn) error) (err error) {
-	cc, err := b.getConn(ctx)
+// wrapCall calls f with connection chosen by routing policy of gRPC method and returns info of endpoint
+// of chosen connection
+func (b *Balancer) wrapCall(
+	ctx context.Context, method string, f func(ctx context.Context, cc conn.Conn) error,
+) (e endpoint.Info, err error) {
+	onDone := b.onCall(&ctx, method)
+	defer func() {
+		onDone(e, err)
+	}()
+
+	if !b.calls.add() {
+		return nil, xerrors.WithStackTrace(ErrClosed)
+	}
+	defer b.calls.done()
+
+	if address, has := conn.DirectEndpoint(ctx); has {
+		if b.shuttingDown.Load() {
+			return nil, xerrors.WithStackTrace(conn.ErrShuttingDown)
+		}
+
+		cc := b.pool.GetByAddress(address)
+
+		return cc.Endpoint(), b.call(ctx, cc, f)
+	}
+
+	cc, err := b.getConn(b.withMethodPolicy(ctx, method))
 	if err != nil {
-		return xerrors.WithStackTrace(err)
+		return nil, xerrors.WithStackTrace(err)
 	}
 
+	b.circuitBreakers.onCall(ctx, cc)
+
 	defer func() {
-		if err == nil {
-			if cc.GetState() == conn.Banned {
-				b.pool.Allow(ctx, cc)
-			}
-		} else if conn.IsBadConn(err, b.driverConfig.ExcludeGRPCCodesForPessimization()...) {
-			b.pool.Ban(ctx, cc, err)
-		}
+		b.onCallDone(ctx, cc, err)
 	}()
 
+	return cc.Endpoint(), b.call(ctx, cc, f)
+}
+
+// isDirectCall reports that call with context bypasses balancer
+func isDirectCall(ctx context.Context) bool {
+	_, has := conn.DirectEndpoint(ctx)
+
+	return has
+}
+
+// onCall traces start of call. Context of call can be replaced by trace (such as with span of distributed tracing)
+func (b *Balancer) onCall(ctx *context.Context, method string) func(e endpoint.Info, err error) {
+	onDone := trace.DriverOnBalancerCall(b.driverConfig.Trace(), ctx,
+		stack.FunctionID("github.com/ydb-platform/ydb-go-sdk/v3/internal/balancer.(*Balancer).onCall"),
+		method, retry.Attempt(*ctx),
+	)
+
+	return func(e endpoint.Info, err error) {
+		info, _ := e.(trace.EndpointInfo)
+		onDone(info, err)
+	}
+}
+
+// withMethodPolicy applies routing policy of gRPC method to context of call
+func (b *Balancer) withMethodPolicy(ctx context.Context, method string) context.Context {
+	if policy, has := b.config.MethodPolicy(method); has && policy != nil {
+		return policy(ctx, method)
+	}
+
+	return ctx
+}
+
+// onCallDone updates circuit breaker and pessimizes endpoint of connection by result of call
+func (b *Balancer) onCallDone(ctx context.Context, cc conn.Conn, err error) {
+	if err == nil {
+		b.circuitBreakers.onSuccess(ctx, cc)
+		if cc.GetState() == conn.Banned {
+			b.pool.Allow(ctx, cc)
+		}
+	} else if b.mustPessimize(ctx, err) {
+		b.circuitBreakers.onFailure(ctx, cc)
+		b.ban(ctx, cc, err)
+	} else {
+		b.circuitBreakers.onSuccess(ctx, cc)
+	}
+}
+
+// mustPessimize checks that error of call must ban endpoint with custom check from driver config
+// or with conn.MustPessimizeEndpoint. Pessimization codes from context has priority over custom check
+func (b *Balancer) mustPessimize(ctx context.Context, err error) bool {
+	if b.customPessimization(ctx) {
+		return b.driverConfig.PessimizationFunc()(err, b.driverConfig.ExcludeGRPCCodesForPessimization()...)
+	}
+
+	return conn.MustPessimizeEndpoint(ctx, err, b.driverConfig.ExcludeGRPCCodesForPessimization()...)
+}
+
+// customPessimization checks that pessimization of endpoint on call with context decides by custom check
+func (b *Balancer) customPessimization(ctx context.Context) bool {
+	if b.driverConfig.PessimizationFunc() == nil {
+		return false
+	}
+
+	_, has := conn.PessimizationCodes(ctx)
+
+	return !has
+}
+
+func (b *Balancer) call(
+	ctx context.Context, cc conn.Conn, f func(ctx context.Context, cc conn.Conn) error,
+) (err error) {
+	// in-flight call tracks with defer for decrement of counter even on panic of call
+	defer cc.TrackInFlight()()
+
+	if tags, has := conn.RequestTags(ctx); has {
+		ctx = meta.WithRequestTags(ctx, tags)
+	}
+
 	if ctx, err = b.driverConfig.Meta().Context(ctx); err != nil {
 		return xerrors.WithStackTrace(err)
 	}
@@ -319,6 +1091,17 @@ func (b *Balancer) wrapCall(ctx context.Context, f func(ctx context.Context, cc
 	return nil
 }
 
+// DiscoveryHistory returns last applied discovery results from oldest to newest.
+// Discovery history is empty if it not enabled with balancer config option WithDiscoveryHistory
+func (b *Balancer) DiscoveryHistory() []DiscoveryEvent {
+	return b.discoveryHistory.list()
+}
+
+// CircuitBreakerStates returns states of per-endpoint circuit breakers by endpoint address
+func (b *Balancer) CircuitBreakerStates() map[string]CircuitBreakerState {
+	return b.circuitBreakers.states()
+}
+
 func (b *Balancer) connections() *connectionsState {
 	return b.connectionsState.Load()
 }
@@ -330,9 +1113,10 @@ func (b *Balancer) getConn(ctx context.Context) (c conn.Conn, err error) {
 	)
 	defer func() {
 		if err == nil {
-			onDone(c.Endpoint(), nil)
+			state := b.connections()
+			onDone(c.Endpoint(), state.role(c), state.localityLevel(c), preferredNodeID(ctx, c), nil)
 		} else {
-			onDone(nil, err)
+			onDone(nil, "", 0, 0, err)
 		}
 	}()
 
@@ -340,6 +1124,14 @@ func (b *Balancer) getConn(ctx context.Context) (c conn.Conn, err error) {
 		return nil, xerrors.WithStackTrace(err)
 	}
 
+	if b.shuttingDown.Load() {
+		return nil, xerrors.WithStackTrace(conn.ErrShuttingDown)
+	}
+
+	if b.calls.isClosed() {
+		return nil, xerrors.WithStackTrace(ErrClosed)
+	}
+
 	var (
 		state       = b.connections()
 		failedCount int
@@ -351,16 +1143,140 @@ func (b *Balancer) getConn(ctx context.Context) (c conn.Conn, err error) {
 		}
 	}()
 
-	c, failedCount = state.GetConnection(ctx)
+	selectCtx := ctx
+	if timeout, has := conn.AcquireTimeout(ctx); has {
+		var cancel context.CancelFunc
+		selectCtx, cancel = xcontext.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	if conn.StrictNodeID(ctx) {
+		if c = state.preferConnection(ctx); c == nil {
+			nodeID, _ := endpoint.ContextNodeID(ctx)
+
+			return nil, xerrors.WithStackTrace(fmt.Errorf("%w: node %d", conn.ErrNodeUnavailable, nodeID))
+		}
+	} else {
+		c, failedCount = state.GetConnection(selectCtx)
+	}
+	if c != nil && allBanned(state.conns()) {
+		c = nil
+	}
 	if c == nil {
+		if err = ctx.Err(); err != nil {
+			return nil, xerrors.WithStackTrace(err)
+		}
+
+		if selectCtx.Err() != nil {
+			timeout, _ := conn.AcquireTimeout(ctx)
+
+			return nil, xerrors.WithStackTrace(xerrors.Retryable(
+				fmt.Errorf("%w: cannot get connection from Balancer within %v", conn.ErrAcquireTimeout, timeout),
+				xerrors.WithName("ErrAcquireTimeout"),
+			))
+		}
+
+		if len(state.All()) == 0 {
+			return nil, xerrors.WithStackTrace(
+				fmt.Errorf("%w: cannot get connection from Balancer after %d attempts", ErrNoEndpoints, failedCount),
+			)
+		}
+
+		return nil, xerrors.WithStackTrace(xerrors.Retryable(
+			fmt.Errorf("%w: cannot get connection from Balancer after %d attempts", ErrAllEndpointsBanned, failedCount),
+			xerrors.WithName("ErrAllEndpointsBanned"),
+		))
+	}
+
+	if limit := b.config.MaxStreamsPerConn; limit > 0 && isStreamCall(ctx) {
+		if c, err = b.streamConnection(state, c, limit); err != nil {
+			return nil, err
+		}
+	}
+
+	if !conn.TryEndpoint(ctx, c.Endpoint().Address()) {
+		maxAttempts, _ := conn.MaxEndpointAttempts(ctx)
+
 		return nil, xerrors.WithStackTrace(
-			fmt.Errorf("%w: cannot get connection from Balancer after %d attempts", ErrNoEndpoints, failedCount),
+			fmt.Errorf("%w: tried maximum %d endpoints for call", conn.ErrEndpointAttemptsExhausted, maxAttempts),
 		)
 	}
 
+	// chosen connection is used, so connections pool not parks it as idle (config.WithConnectionTTL
+	// or balancers.WithIdleTimeout) before call
+	c.Touch()
+
 	return c, nil
 }
 
+// preferredNodeID returns node ID from context if chosen connection belongs to this node, otherwise zero
+func preferredNodeID(ctx context.Context, c conn.Conn) uint32 {
+	if nodeID, has := endpoint.ContextNodeID(ctx); has && c.Endpoint().NodeID() == nodeID {
+		return nodeID
+	}
+
+	return 0
+}
+
+// rewriteAddresses applies rewriter to addresses of endpoints. All other fields of endpoints preserves
+func rewriteAddresses(
+	endpoints []endpoint.Endpoint, rewriter func(e endpoint.Endpoint) endpoint.Endpoint,
+) []endpoint.Endpoint {
+	return xslices.Transform(endpoints, func(e endpoint.Endpoint) endpoint.Endpoint {
+		rewritten := e.Copy()
+		rewritten.Touch(endpoint.WithAddress(rewriter(e.Copy()).Address()))
+
+		return rewritten
+	})
+}
+
+// sameEndpoints checks that discovered endpoints not changed since previous discovery.
+// Load factors of endpoints compares with loadFactorThreshold
+func sameEndpoints(previous, newest []endpoint.Endpoint) bool {
+	if len(previous) != len(newest) {
+		return false
+	}
+
+	cmp := func(lhs, rhs endpoint.Endpoint) int {
+		return strings.Compare(lhs.Address(), rhs.Address())
+	}
+	previous, newest = xslices.SortCopy(previous, cmp), xslices.SortCopy(newest, cmp)
+
+	for i := range previous {
+		if previous[i].Address() != newest[i].Address() ||
+			previous[i].NodeID() != newest[i].NodeID() ||
+			previous[i].Location() != newest[i].Location() ||
+			math.Abs(float64(previous[i].LoadFactor()-newest[i].LoadFactor())) > loadFactorThreshold {
+			return false
+		}
+	}
+
+	return true
+}
+
+// uniqueEndpoints drops duplicates of endpoints with same address (such as from malformed discovery response),
+// so duplicated endpoint not skews choice of connection. First occurrence of endpoint keeps
+func uniqueEndpoints(endpoints []endpoint.Endpoint) (unique, duplicates []endpoint.Endpoint) {
+	seen := make(map[string]struct{}, len(endpoints))
+	unique = endpoints
+	for i, e := range endpoints {
+		if _, has := seen[e.Address()]; !has {
+			seen[e.Address()] = struct{}{}
+			if len(duplicates) > 0 {
+				unique = append(unique, e)
+			}
+
+			continue
+		}
+		if len(duplicates) == 0 {
+			unique = append(make([]endpoint.Endpoint, 0, len(endpoints)-1), endpoints[:i]...)
+		}
+		duplicates = append(duplicates, e)
+	}
+
+	return unique, duplicates
+}
+
 func endpointsToConnections(p *conn.Pool, endpoints []endpoint.Endpoint) []conn.Conn {
 	conns := make([]conn.Conn, 0, len(endpoints))
 	for _, e := range endpoints {
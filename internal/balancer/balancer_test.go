@@ -0,0 +1,1544 @@
+package balancer
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"github.com/stretchr/testify/require"
+	"github.com/ydb-platform/ydb-go-genproto/protos/Ydb"
+	"github.com/ydb-platform/ydb-go-genproto/protos/Ydb_Discovery"
+	"github.com/ydb-platform/ydb-go-genproto/protos/Ydb_Operations"
+	"google.golang.org/grpc"
+	grpcCodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	grpcStatus "google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/balancers"
+	"github.com/ydb-platform/ydb-go-sdk/v3/config"
+	balancerConfig "github.com/ydb-platform/ydb-go-sdk/v3/internal/balancer/config"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/conn"
+	discoveryConfig "github.com/ydb-platform/ydb-go-sdk/v3/internal/discovery/config"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/endpoint"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/meta"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/mock"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xerrors"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xslices"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xtest"
+	"github.com/ydb-platform/ydb-go-sdk/v3/retry"
+	"github.com/ydb-platform/ydb-go-sdk/v3/trace"
+)
+
+func TestForceDiscovery(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.New()
+	b := &Balancer{
+		driverConfig: cfg,
+		pool:         conn.NewPool(ctx, cfg),
+		discoveryClient: discoveryMock{endpoints: []endpoint.Endpoint{
+			&mock.Endpoint{AddrField: "a:123", NodeIDField: 1},
+			&mock.Endpoint{AddrField: "b:234", NodeIDField: 2},
+		}},
+	}
+
+	endpoints, err := b.ForceDiscovery(ctx)
+	require.NoError(t, err)
+	require.Len(t, endpoints, 2)
+	require.Equal(t, "a:123", endpoints[0].Address())
+	require.Equal(t, "b:234", endpoints[1].Address())
+	require.Len(t, b.connections().All(), 2)
+
+	t.Run("CanceledContext", func(t *testing.T) {
+		childCtx, cancel := context.WithCancel(ctx)
+		cancel()
+		b.discoveryClient = discoveryErrorMock{err: context.Canceled}
+		_, err := b.ForceDiscovery(childCtx)
+		require.ErrorIs(t, err, context.Canceled)
+		require.False(t, xerrors.IsRetryableError(err))
+		require.Len(t, b.connections().All(), 2)
+	})
+}
+
+type discoveryErrorMock struct {
+	err error
+}
+
+func (d discoveryErrorMock) Close(ctx context.Context) error {
+	return nil
+}
+
+func (d discoveryErrorMock) Discover(ctx context.Context) ([]endpoint.Endpoint, error) {
+	return nil, d.err
+}
+
+func TestEndpointFilter(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.New(
+		config.WithBalancer(balancers.PreferLocationsWithFallback(balancers.Default(), "a").With(
+			balancerConfig.WithEndpointFilter(func(e endpoint.Info) bool {
+				return e.NodeID() != 2 && e.NodeID() != 3
+			}),
+		)),
+	)
+	b := &Balancer{
+		driverConfig: cfg,
+		config:       *cfg.Balancer(),
+		pool:         conn.NewPool(ctx, cfg),
+		discoveryClient: discoveryMock{endpoints: []endpoint.Endpoint{
+			&mock.Endpoint{AddrField: "a:1", LocationField: "a", NodeIDField: 1},
+			&mock.Endpoint{AddrField: "a:2", LocationField: "a", NodeIDField: 2},
+			&mock.Endpoint{AddrField: "b:3", LocationField: "b", NodeIDField: 3},
+			&mock.Endpoint{AddrField: "b:4", LocationField: "b", NodeIDField: 4},
+		}},
+	}
+
+	require.NoError(t, b.clusterDiscoveryAttempt(ctx))
+	require.Len(t, b.connections().All(), 2)
+
+	for _, c := range b.connections().all {
+		if c.Endpoint().NodeID() == 1 {
+			b.pool.Ban(ctx, c, errors.New("test"))
+		}
+	}
+
+	for i := 0; i < 100; i++ {
+		c, _ := b.connections().GetConnection(ctx)
+		require.NotNil(t, c)
+		require.NotContains(t, []uint32{2, 3}, c.Endpoint().NodeID())
+	}
+}
+
+type discoveryCacheMock struct {
+	endpoints []endpoint.Endpoint
+}
+
+func (c *discoveryCacheMock) Load() ([]endpoint.Endpoint, error) {
+	return c.endpoints, nil
+}
+
+func (c *discoveryCacheMock) Save(endpoints []endpoint.Endpoint) error {
+	c.endpoints = endpoints
+
+	return nil
+}
+
+func TestDiscoveryCache(t *testing.T) {
+	ctx := context.Background()
+	cache := &discoveryCacheMock{endpoints: []endpoint.Endpoint{
+		&mock.Endpoint{AddrField: "cached:123", NodeIDField: 1},
+	}}
+	cfg := config.New(
+		config.WithBalancer(balancers.Default().With(balancerConfig.WithDiscoveryCache(cache))),
+	)
+	b := &Balancer{
+		driverConfig: cfg,
+		config:       *cfg.Balancer(),
+		pool:         conn.NewPool(ctx, cfg),
+		discoveryClient: discoveryMock{endpoints: []endpoint.Endpoint{
+			&mock.Endpoint{AddrField: "fresh:123", NodeIDField: 1},
+			&mock.Endpoint{AddrField: "fresh:234", NodeIDField: 2},
+		}},
+	}
+
+	require.True(t, b.applyDiscoveryCache(ctx))
+	require.Len(t, b.connections().All(), 1)
+	require.Equal(t, "cached:123", b.connections().All()[0].Address())
+
+	require.NoError(t, b.clusterDiscoveryAttempt(ctx))
+	require.Len(t, b.connections().All(), 2)
+	require.Len(t, cache.endpoints, 2)
+	require.Equal(t, "fresh:123", cache.endpoints[0].Address())
+}
+
+func TestGetConnErrors(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.New()
+	t.Run("NoEndpoints", func(t *testing.T) {
+		b := &Balancer{
+			driverConfig: cfg,
+			pool:         conn.NewPool(ctx, cfg),
+		}
+		b.connectionsState.Store(newConnectionsState(nil, nil, balancerConfig.Info{}, false))
+		_, err := b.getConn(ctx)
+		require.ErrorIs(t, err, ErrNoEndpoints)
+		require.NotErrorIs(t, err, ErrAllEndpointsBanned)
+		require.False(t, xerrors.IsRetryableError(err))
+	})
+	t.Run("AllEndpointsBanned", func(t *testing.T) {
+		b := &Balancer{
+			driverConfig: cfg,
+			pool:         conn.NewPool(ctx, cfg),
+		}
+		b.connectionsState.Store(newConnectionsState([]conn.Conn{
+			&mock.Conn{AddrField: "1", State: conn.Destroyed},
+			&mock.Conn{AddrField: "2", State: conn.Destroyed},
+		}, nil, balancerConfig.Info{}, false))
+		_, err := b.getConn(ctx)
+		require.ErrorIs(t, err, ErrNoEndpoints)
+		require.ErrorIs(t, err, ErrAllEndpointsBanned)
+		require.True(t, xerrors.IsRetryableError(err))
+	})
+	t.Run("AllEndpointsBannedByPool", func(t *testing.T) {
+		b := &Balancer{
+			driverConfig: cfg,
+			pool:         conn.NewPool(ctx, cfg),
+		}
+		b.connectionsState.Store(newConnectionsState([]conn.Conn{
+			&mock.Conn{AddrField: "1", State: conn.Banned},
+			&mock.Conn{AddrField: "2", State: conn.Banned},
+		}, nil, balancerConfig.Info{}, false))
+		_, err := b.getConn(ctx)
+		require.ErrorIs(t, err, ErrNoEndpoints)
+		require.ErrorIs(t, err, ErrAllEndpointsBanned)
+		require.ErrorIs(t, err, balancers.ErrAllEndpointsBanned)
+		require.True(t, xerrors.IsRetryableError(err))
+	})
+	t.Run("OneEndpointNotBanned", func(t *testing.T) {
+		b := &Balancer{
+			driverConfig: cfg,
+			pool:         conn.NewPool(ctx, cfg),
+		}
+		b.connectionsState.Store(newConnectionsState([]conn.Conn{
+			&mock.Conn{AddrField: "1", State: conn.Banned},
+			&mock.Conn{AddrField: "2", State: conn.Online},
+		}, nil, balancerConfig.Info{}, false))
+		c, err := b.getConn(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "2", c.Endpoint().Address())
+	})
+}
+
+func TestGetConnTouchesConnection(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.New()
+	b := &Balancer{
+		driverConfig: cfg,
+		pool:         conn.NewPool(ctx, cfg),
+	}
+	c := &mock.Conn{AddrField: "1", State: conn.Online}
+	b.connectionsState.Store(newConnectionsState([]conn.Conn{c}, nil, balancerConfig.Info{}, false))
+
+	for i := 0; i < 3; i++ {
+		_, err := b.getConn(ctx)
+		require.NoError(t, err)
+	}
+	require.Equal(t, 3, c.Touched())
+}
+
+func TestStats(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.New(
+		config.WithBalancer(balancers.PreferNearestDCWithFallBack(balancers.Default())),
+	)
+	b := &Balancer{
+		driverConfig: cfg,
+		config:       *cfg.Balancer(),
+		pool:         conn.NewPool(ctx, cfg),
+		discoveryClient: discoveryMock{endpoints: []endpoint.Endpoint{
+			&mock.Endpoint{AddrField: "a:1", LocationField: "a"},
+			&mock.Endpoint{AddrField: "b:2", LocationField: "b"},
+			&mock.Endpoint{AddrField: "b:3", LocationField: "b"},
+		}},
+		localDCDetector: func(ctx context.Context, endpoints []endpoint.Endpoint) (string, error) {
+			return "b", nil
+		},
+	}
+
+	require.Equal(t, BalancerStats{}, b.Stats())
+
+	before := time.Now()
+	require.NoError(t, b.clusterDiscoveryAttempt(ctx))
+
+	b.pool.Ban(ctx, b.connections().fallback[0],
+		xerrors.Transport(grpcStatus.Error(grpcCodes.Unavailable, "")),
+	)
+
+	stats := b.Stats()
+	require.Equal(t, 2, stats.PreferredConnections)
+	require.Equal(t, 1, stats.FallbackConnections)
+	require.Equal(t, 1, stats.BannedConnections)
+	require.Equal(t, "b", stats.LocalDC)
+	require.False(t, stats.LastDiscovery.Before(before))
+}
+
+func TestBannedEndpoints(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.New()
+	b := &Balancer{
+		driverConfig: cfg,
+		pool:         conn.NewPool(ctx, cfg),
+		discoveryClient: discoveryMock{endpoints: []endpoint.Endpoint{
+			&mock.Endpoint{AddrField: "a:1", NodeIDField: 1},
+			&mock.Endpoint{AddrField: "b:2", NodeIDField: 2},
+		}},
+	}
+	require.Empty(t, b.BannedEndpoints())
+
+	require.NoError(t, b.clusterDiscoveryAttempt(ctx))
+	require.Empty(t, b.BannedEndpoints())
+
+	before := time.Now()
+	cause := xerrors.Transport(grpcStatus.Error(grpcCodes.Unavailable, ""))
+	cc := b.connections().all[1]
+	b.pool.Ban(ctx, cc, cause)
+
+	banned := b.BannedEndpoints()
+	require.Len(t, banned, 1)
+	require.Equal(t, "b:2", banned[0].Endpoint.Address())
+	require.Equal(t, uint32(2), banned[0].Endpoint.NodeID())
+	require.False(t, banned[0].BannedAt.Before(before))
+	require.ErrorIs(t, banned[0].Cause, cause)
+
+	b.pool.Allow(ctx, cc)
+	require.Empty(t, b.BannedEndpoints())
+}
+
+func TestWaitReady(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.New()
+	t.Run("Discovery", func(t *testing.T) {
+		b := &Balancer{
+			driverConfig: cfg,
+			pool:         conn.NewPool(ctx, cfg),
+			discoveryClient: discoveryMock{endpoints: []endpoint.Endpoint{
+				&mock.Endpoint{AddrField: "a:1"},
+			}},
+		}
+		ready := make(chan error, 1)
+		go func() {
+			ready <- b.WaitReady(ctx)
+		}()
+		select {
+		case err := <-ready:
+			require.Fail(t, "unexpected ready", err)
+		case <-time.After(10 * time.Millisecond):
+		}
+		require.NoError(t, b.clusterDiscoveryAttempt(ctx))
+		require.NoError(t, <-ready)
+	})
+	t.Run("Unban", func(t *testing.T) {
+		b := &Balancer{
+			driverConfig: cfg,
+			pool:         conn.NewPool(ctx, cfg),
+		}
+		c := b.pool.Get(&mock.Endpoint{AddrField: "a:1"})
+		b.pool.Ban(ctx, c, xerrors.Transport(grpcStatus.Error(grpcCodes.Unavailable, "")))
+		b.connectionsState.Store(newConnectionsState([]conn.Conn{c}, nil, balancerConfig.Info{}, false))
+		ready := make(chan error, 1)
+		go func() {
+			ready <- b.WaitReady(ctx)
+		}()
+		b.pool.Allow(ctx, c)
+		require.NoError(t, <-ready)
+	})
+	t.Run("ContextDone", func(t *testing.T) {
+		b := &Balancer{
+			driverConfig: cfg,
+			pool:         conn.NewPool(ctx, cfg),
+		}
+		childCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+		err := b.WaitReady(childCtx)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.ErrorIs(t, err, ErrNoEndpoints)
+	})
+}
+
+func TestSameDiscoveryResult(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.New()
+	b := &Balancer{
+		driverConfig: cfg,
+		pool:         conn.NewPool(ctx, cfg),
+		discoveryClient: discoveryMock{endpoints: []endpoint.Endpoint{
+			&mock.Endpoint{AddrField: "a:123", NodeIDField: 1, LoadFactorField: 0.5},
+			&mock.Endpoint{AddrField: "b:234", NodeIDField: 2, LoadFactorField: 0.5},
+		}},
+	}
+	var updates int
+	b.OnUpdate(func(ctx context.Context, endpoints []endpoint.Info) {
+		updates++
+	})
+
+	require.NoError(t, b.clusterDiscoveryAttempt(ctx))
+	state := b.connections()
+	require.Equal(t, 1, updates)
+
+	b.discoveryClient = discoveryMock{endpoints: []endpoint.Endpoint{
+		&mock.Endpoint{AddrField: "b:234", NodeIDField: 2, LoadFactorField: 0.55},
+		&mock.Endpoint{AddrField: "a:123", NodeIDField: 1, LoadFactorField: 0.5},
+	}}
+	require.NoError(t, b.clusterDiscoveryAttempt(ctx))
+	require.Same(t, state, b.connections())
+	require.Equal(t, 1, updates)
+
+	t.Run("LoadFactorChanged", func(t *testing.T) {
+		b.discoveryClient = discoveryMock{endpoints: []endpoint.Endpoint{
+			&mock.Endpoint{AddrField: "a:123", NodeIDField: 1, LoadFactorField: 0.5},
+			&mock.Endpoint{AddrField: "b:234", NodeIDField: 2, LoadFactorField: 0.9},
+		}}
+		require.NoError(t, b.clusterDiscoveryAttempt(ctx))
+		require.NotSame(t, state, b.connections())
+		require.Equal(t, 2, updates)
+	})
+}
+
+func TestOnBan(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.New()
+	b := &Balancer{
+		driverConfig: cfg,
+		pool:         conn.NewPool(ctx, cfg),
+		discoveryClient: discoveryMock{endpoints: []endpoint.Endpoint{
+			&mock.Endpoint{AddrField: "a:123", NodeIDField: 1},
+		}},
+	}
+	require.NoError(t, b.clusterDiscoveryAttempt(ctx))
+
+	var (
+		banned []endpoint.Info
+		errs   []error
+	)
+	b.OnBan(func(ctx context.Context, e trace.EndpointInfo, err error) {
+		banned = append(banned, e)
+		errs = append(errs, err)
+	})
+
+	callErr := xerrors.Transport(grpcStatus.Error(grpcCodes.Unavailable, ""))
+	_, err := b.wrapCall(ctx, "", func(ctx context.Context, cc conn.Conn) error {
+		return callErr
+	})
+	require.ErrorIs(t, err, callErr)
+	require.Len(t, banned, 1)
+	require.Equal(t, "a:123", banned[0].Address())
+	require.ErrorIs(t, errs[0], callErr)
+
+	t.Run("NotPessimized", func(t *testing.T) {
+		_, err := b.wrapCall(ctx, "", func(ctx context.Context, cc conn.Conn) error {
+			return errors.New("test")
+		})
+		require.Error(t, err)
+		require.Len(t, banned, 1)
+	})
+}
+
+func TestAddressRewriter(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.New(
+		config.WithBalancer(balancers.Default().With(
+			balancers.WithAddressRewriter(func(e balancers.Endpoint) string {
+				return strings.Replace(e.Address(), "10.0.0.", "proxy-", 1)
+			}),
+		)),
+	)
+	b := &Balancer{
+		driverConfig: cfg,
+		config:       *cfg.Balancer(),
+		pool:         conn.NewPool(ctx, cfg),
+		discoveryClient: discoveryMock{endpoints: []endpoint.Endpoint{
+			endpoint.New("10.0.0.1:2135", endpoint.WithID(1), endpoint.WithLocation("a")),
+			endpoint.New("10.0.0.2:2135", endpoint.WithID(2), endpoint.WithLocation("b")),
+		}},
+	}
+
+	require.NoError(t, b.clusterDiscoveryAttempt(ctx))
+	all := b.connections().All()
+	require.Len(t, all, 2)
+	for _, e := range all {
+		switch e.NodeID() {
+		case 1:
+			require.Equal(t, "proxy-1:2135", e.Address())
+			require.Equal(t, "a", e.Location())
+		case 2:
+			require.Equal(t, "proxy-2:2135", e.Address())
+			require.Equal(t, "b", e.Location())
+		default:
+			t.Fatalf("unexpected node ID %d", e.NodeID())
+		}
+	}
+}
+
+func TestGetConnDeadline(t *testing.T) {
+	const timeout = 10 * time.Millisecond
+
+	cfg := config.New()
+	conns := make([]conn.Conn, 1000000)
+	for i := range conns {
+		conns[i] = &mock.Conn{AddrField: strconv.Itoa(i), State: conn.Destroyed}
+	}
+	b := &Balancer{
+		driverConfig: cfg,
+		pool:         conn.NewPool(context.Background(), cfg),
+	}
+	b.connectionsState.Store(newConnectionsState(conns, nil, balancerConfig.Info{}, false))
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	start := time.Now()
+	_, err := b.getConn(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Less(t, time.Since(start), timeout+100*time.Millisecond)
+}
+
+func TestGetConnAcquireTimeout(t *testing.T) {
+	const timeout = 10 * time.Millisecond
+
+	cfg := config.New()
+	conns := make([]conn.Conn, 1000000)
+	for i := range conns {
+		conns[i] = &mock.Conn{AddrField: strconv.Itoa(i), State: conn.Destroyed}
+	}
+	b := &Balancer{
+		driverConfig: cfg,
+		pool:         conn.NewPool(context.Background(), cfg),
+	}
+	b.connectionsState.Store(newConnectionsState(conns, nil, balancerConfig.Info{}, false))
+
+	ctx := conn.WithAcquireTimeout(context.Background(), timeout)
+
+	start := time.Now()
+	_, err := b.getConn(ctx)
+	require.ErrorIs(t, err, conn.ErrAcquireTimeout)
+	require.NotErrorIs(t, err, context.DeadlineExceeded)
+	require.True(t, xerrors.IsRetryableError(err))
+	require.Less(t, time.Since(start), timeout+100*time.Millisecond)
+}
+
+func TestClock(t *testing.T) {
+	ctx := context.Background()
+	clock := clockwork.NewFakeClockAt(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	cfg := config.New(
+		config.WithBalancer(balancers.Default().With(balancerConfig.WithClock(clock))),
+	)
+	b := &Balancer{
+		driverConfig: cfg,
+		config:       *cfg.Balancer(),
+		pool:         conn.NewPool(ctx, cfg),
+		discoveryClient: discoveryMock{endpoints: []endpoint.Endpoint{
+			endpoint.New("a:1", endpoint.WithID(1)),
+		}},
+	}
+
+	require.NoError(t, b.clusterDiscoveryAttempt(ctx))
+	require.Equal(t, clock.Now(), b.Stats().LastDiscovery)
+	require.Equal(t, clock.Now(), b.connections().All()[0].LastUpdated())
+
+	clock.Advance(time.Minute)
+	require.NoError(t, b.clusterDiscoveryAttempt(ctx))
+	require.Equal(t, clock.Now(), b.Stats().LastDiscovery)
+	require.Equal(t, clock.Now(), b.connections().All()[0].LastUpdated())
+}
+
+func TestInvokeWithInfo(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.New()
+	newConn := func(address string, nodeID uint32, err error) *hedgingConn {
+		return &hedgingConn{
+			Conn: &mock.Conn{AddrField: address, NodeIDField: nodeID, LocationField: "dc", State: conn.Online},
+			invoke: func(ctx context.Context, reply *wrapperspb.StringValue) error {
+				reply.Value = address
+
+				return err
+			},
+		}
+	}
+	b := &Balancer{
+		driverConfig: cfg,
+		pool:         conn.NewPool(ctx, cfg),
+	}
+	b.connectionsState.Store(newConnectionsState([]conn.Conn{
+		newConn("a:123", 1, nil),
+		newConn("b:234", 2, errors.New("test")),
+	}, nil, balancerConfig.Info{}, false))
+
+	reply := &wrapperspb.StringValue{}
+	info, err := b.InvokeWithInfo(endpoint.WithNodeID(ctx, 1), "test", nil, reply)
+	require.NoError(t, err)
+	require.Equal(t, uint32(1), info.NodeID())
+	require.Equal(t, "dc", info.Location())
+	require.Equal(t, "a:123", reply.GetValue())
+
+	t.Run("Error", func(t *testing.T) {
+		info, err := b.InvokeWithInfo(endpoint.WithNodeID(ctx, 2), "test", nil, &wrapperspb.StringValue{})
+		require.Error(t, err)
+		require.Equal(t, uint32(2), info.NodeID())
+	})
+
+	t.Run("NoConnection", func(t *testing.T) {
+		b := &Balancer{driverConfig: cfg}
+		b.connectionsState.Store(newConnectionsState(nil, nil, balancerConfig.Info{}, false))
+		info, err := b.InvokeWithInfo(ctx, "test", nil, &wrapperspb.StringValue{})
+		require.ErrorIs(t, err, ErrNoEndpoints)
+		require.Nil(t, info)
+	})
+}
+
+func TestStartupFallbackSingleConn(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.New(
+		config.WithEndpoint("initial:2135"),
+		config.WithBalancer(balancers.Default().With(balancerConfig.WithStartupFallbackSingleConn(true))),
+	)
+	b := &Balancer{
+		driverConfig:    cfg,
+		config:          *cfg.Balancer(),
+		pool:            conn.NewPool(ctx, cfg),
+		discoveryClient: discoveryErrorMock{err: errors.New("discovery unavailable")},
+	}
+
+	require.True(t, b.startupDiscoveryWithFallback(ctx))
+	c, err := b.getConn(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "initial:2135", c.Endpoint().Address())
+
+	b.discoveryClient = discoveryMock{endpoints: []endpoint.Endpoint{
+		&mock.Endpoint{AddrField: "a:123"},
+		&mock.Endpoint{AddrField: "b:234"},
+	}}
+	require.NoError(t, b.clusterDiscoveryAttempt(ctx))
+	require.Equal(t, []string{"a:123", "b:234"}, xslices.Transform(b.connections().All(),
+		func(e endpoint.Endpoint) string { return e.Address() },
+	))
+
+	t.Run("DiscoverySucceeded", func(t *testing.T) {
+		require.False(t, b.startupDiscoveryWithFallback(ctx))
+		require.Len(t, b.connections().All(), 2)
+	})
+}
+
+type discoveryAttemptsMock struct {
+	attempts int
+	err      error
+}
+
+func (d *discoveryAttemptsMock) Close(ctx context.Context) error {
+	return nil
+}
+
+func (d *discoveryAttemptsMock) Discover(ctx context.Context) ([]endpoint.Endpoint, error) {
+	d.attempts++
+
+	return nil, d.err
+}
+
+func TestClusterDiscoveryRetryOptions(t *testing.T) {
+	ctx := xtest.Context(t)
+	cfg := config.New()
+	client := &discoveryAttemptsMock{err: xerrors.Retryable(errors.New("test"))}
+	b := &Balancer{
+		driverConfig:    cfg,
+		pool:            conn.NewPool(ctx, cfg),
+		discoveryClient: client,
+		discoveryRetryOptions: []retry.Option{
+			retry.WithRetryableChecker(func(err error) retry.RetryType {
+				if client.attempts < 3 {
+					return retry.Retryable
+				}
+
+				return retry.NonRetryable
+			}),
+		},
+	}
+
+	err := b.clusterDiscovery(ctx)
+	require.ErrorIs(t, err, client.err)
+	require.Equal(t, 3, client.attempts)
+}
+
+func TestNewWithDiscoveryClient(t *testing.T) {
+	ctx := context.Background()
+	newConfig := func(client func(ctx context.Context) (balancerConfig.DiscoveryClient, error)) *config.Config {
+		return config.New(
+			config.WithEndpoint("initial:2135"),
+			config.WithBalancer(balancers.Default().With(balancerConfig.WithDiscoveryClient(client))),
+		)
+	}
+
+	cfg := newConfig(func(ctx context.Context) (balancerConfig.DiscoveryClient, error) {
+		return discoveryMock{endpoints: []endpoint.Endpoint{
+			&mock.Endpoint{AddrField: "a:123"},
+			&mock.Endpoint{AddrField: "b:234"},
+		}}, nil
+	})
+	b, err := New(ctx, cfg, conn.NewPool(ctx, cfg))
+	require.NoError(t, err)
+	defer func() {
+		_ = b.Close(ctx)
+	}()
+	require.Len(t, b.connections().All(), 2)
+
+	t.Run("FactoryError", func(t *testing.T) {
+		factoryErr := errors.New("test")
+		cfg := newConfig(func(ctx context.Context) (balancerConfig.DiscoveryClient, error) {
+			return nil, factoryErr
+		})
+		_, err := New(ctx, cfg, conn.NewPool(ctx, cfg))
+		require.ErrorIs(t, err, factoryErr)
+	})
+
+	t.Run("DiscoveryError", func(t *testing.T) {
+		discoveryErr := errors.New("test")
+		cfg := newConfig(func(ctx context.Context) (balancerConfig.DiscoveryClient, error) {
+			return discoveryErrorMock{err: discoveryErr}, nil
+		})
+		_, err := New(ctx, cfg, conn.NewPool(ctx, cfg))
+		require.ErrorIs(t, err, discoveryErr)
+	})
+}
+
+func TestMaxEndpointAttempts(t *testing.T) {
+	cfg := config.New()
+	b := &Balancer{driverConfig: cfg}
+	b.connectionsState.Store(newConnectionsState([]conn.Conn{
+		&mock.Conn{AddrField: "1", NodeIDField: 1, State: conn.Online},
+		&mock.Conn{AddrField: "2", NodeIDField: 2, State: conn.Online},
+		&mock.Conn{AddrField: "3", NodeIDField: 3, State: conn.Online},
+	}, nil, balancerConfig.Info{}, false))
+
+	ctx := conn.WithMaxEndpointAttempts(context.Background(), 2)
+	for _, nodeID := range []uint32{1, 2, 1, 2} {
+		c, err := b.getConn(endpoint.WithNodeID(ctx, nodeID))
+		require.NoError(t, err)
+		require.Equal(t, nodeID, c.Endpoint().NodeID())
+	}
+	_, err := b.getConn(endpoint.WithNodeID(ctx, 3))
+	require.ErrorIs(t, err, conn.ErrEndpointAttemptsExhausted)
+	require.NotErrorIs(t, err, ErrNoEndpoints)
+
+	t.Run("NewOperation", func(t *testing.T) {
+		ctx := conn.WithMaxEndpointAttempts(context.Background(), 2)
+		c, err := b.getConn(endpoint.WithNodeID(ctx, 3))
+		require.NoError(t, err)
+		require.Equal(t, uint32(3), c.Endpoint().NodeID())
+	})
+
+	t.Run("Random", func(t *testing.T) {
+		ctx := conn.WithMaxEndpointAttempts(context.Background(), 1)
+		tried := make(map[string]struct{})
+		for i := 0; i < 100; i++ {
+			if c, err := b.getConn(ctx); err == nil {
+				tried[c.Endpoint().Address()] = struct{}{}
+			} else {
+				require.ErrorIs(t, err, conn.ErrEndpointAttemptsExhausted)
+			}
+		}
+		require.Len(t, tried, 1)
+	})
+}
+
+func TestClusterInfo(t *testing.T) {
+	ctx := context.Background()
+	clock := clockwork.NewFakeClock()
+	cfg := config.New(
+		config.WithEndpoint("initial:2135"),
+		config.WithDatabase("/local"),
+		config.WithBalancer(balancers.PreferNearestDC(balancers.Default()).With(balancerConfig.WithClock(clock))),
+	)
+	b := &Balancer{
+		driverConfig: cfg,
+		config:       *cfg.Balancer(),
+		pool:         conn.NewPool(ctx, cfg),
+		discoveryClient: discoveryMock{endpoints: []endpoint.Endpoint{
+			&mock.Endpoint{AddrField: "a:123", LocationField: "a"},
+			&mock.Endpoint{AddrField: "b:234", LocationField: "b"},
+		}},
+		localDCDetector: func(ctx context.Context, endpoints []endpoint.Endpoint) (string, error) {
+			return "b", nil
+		},
+	}
+	require.Zero(t, b.ClusterInfo())
+
+	require.NoError(t, b.clusterDiscoveryAttempt(ctx))
+	require.Equal(t, ClusterInfo{
+		Endpoint:   "initial:2135",
+		Database:   "/local",
+		LocalDC:    "b",
+		Endpoints:  2,
+		Discovered: clock.Now(),
+	}, b.ClusterInfo())
+}
+
+func TestMaxEndpoints(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.New(
+		config.WithEndpoint("initial:2135"),
+		config.WithBalancer(balancers.Default().With(balancerConfig.WithDiscoveryClient(
+			func(ctx context.Context) (balancerConfig.DiscoveryClient, error) {
+				endpoints := make([]endpoint.Endpoint, 1000)
+				for i := range endpoints {
+					endpoints[i] = &mock.Endpoint{AddrField: "node-" + strconv.Itoa(i) + ":2135"}
+				}
+
+				return discoveryMock{endpoints: endpoints}, nil
+			},
+		))),
+	)
+
+	_, err := New(ctx, cfg, conn.NewPool(ctx, cfg), discoveryConfig.WithMaxEndpoints(100))
+	require.ErrorIs(t, err, ErrTooManyEndpoints)
+
+	t.Run("WithinLimit", func(t *testing.T) {
+		b, err := New(ctx, cfg, conn.NewPool(ctx, cfg), discoveryConfig.WithMaxEndpoints(1000))
+		require.NoError(t, err)
+		defer func() {
+			_ = b.Close(ctx)
+		}()
+		require.Len(t, b.connections().All(), 1000)
+	})
+}
+
+func TestMethodPolicy(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.New(
+		config.WithBalancer(balancers.Default().With(
+			balancerConfig.WithMethodPolicy("/test.Service", balancerConfig.StickyPolicy()),
+			balancerConfig.WithMethodPolicy("/test.Service/Random", balancerConfig.RandomPolicy()),
+		)),
+	)
+	endpoints := make([]endpoint.Endpoint, 10)
+	for i := range endpoints {
+		endpoints[i] = endpoint.New("a:"+strconv.Itoa(i), endpoint.WithID(uint32(i)))
+	}
+	b := &Balancer{
+		driverConfig:    cfg,
+		config:          *cfg.Balancer(),
+		pool:            conn.NewPool(ctx, cfg),
+		discoveryClient: discoveryMock{endpoints: endpoints},
+	}
+	require.NoError(t, b.clusterDiscoveryAttempt(ctx))
+
+	addresses := func(method string) map[string]struct{} {
+		res := make(map[string]struct{})
+		for i := 0; i < 100; i++ {
+			e, err := b.wrapCall(ctx, method, func(ctx context.Context, cc conn.Conn) error {
+				return nil
+			})
+			require.NoError(t, err)
+			res[e.Address()] = struct{}{}
+		}
+
+		return res
+	}
+
+	t.Run("Sticky", func(t *testing.T) {
+		require.Len(t, addresses("/test.Service/Session"), 1)
+	})
+	t.Run("Random", func(t *testing.T) {
+		require.Greater(t, len(addresses("/test.Service/Random")), 1)
+	})
+	t.Run("Default", func(t *testing.T) {
+		require.Greater(t, len(addresses("/other.Service/Method")), 1)
+	})
+}
+
+func TestPessimizationFunc(t *testing.T) {
+	ctx := context.Background()
+	const customCode = grpcCodes.Code(100)
+	cfg := config.New(
+		config.WithPessimizationFunc(func(err error, excludeCodes ...grpcCodes.Code) bool {
+			return xerrors.IsTransportError(err, customCode)
+		}),
+	)
+	b := &Balancer{
+		driverConfig: cfg,
+		pool:         conn.NewPool(ctx, cfg),
+		discoveryClient: discoveryMock{endpoints: []endpoint.Endpoint{
+			&mock.Endpoint{AddrField: "a:123", NodeIDField: 1},
+		}},
+	}
+	require.NoError(t, b.clusterDiscoveryAttempt(ctx))
+
+	var banned []endpoint.Info
+	b.OnBan(func(ctx context.Context, e trace.EndpointInfo, err error) {
+		banned = append(banned, e)
+	})
+	call := func(ctx context.Context, code grpcCodes.Code) {
+		_, err := b.wrapCall(ctx, "", func(ctx context.Context, cc conn.Conn) error {
+			return xerrors.Transport(grpcStatus.Error(code, ""))
+		})
+		require.Error(t, err)
+	}
+
+	call(ctx, grpcCodes.Unavailable)
+	require.Empty(t, banned)
+
+	call(ctx, customCode)
+	require.Len(t, banned, 1)
+	require.Equal(t, conn.Banned, b.connections().all[0].GetState())
+
+	t.Run("PessimizationCodesFromContext", func(t *testing.T) {
+		b.pool.Allow(ctx, b.connections().all[0])
+		call(conn.WithPessimizationCodes(ctx), customCode)
+		require.Len(t, banned, 1)
+		require.NotEqual(t, conn.Banned, b.connections().all[0].GetState())
+	})
+}
+
+func TestUnban(t *testing.T) {
+	ctx := context.Background()
+	var allowed []string
+	cfg := config.New(
+		config.WithTrace(trace.Driver{
+			OnConnAllow: func(info trace.DriverConnAllowStartInfo) func(trace.DriverConnAllowDoneInfo) {
+				allowed = append(allowed, info.Endpoint.Address())
+
+				return nil
+			},
+		}),
+	)
+	b := &Balancer{
+		driverConfig: cfg,
+		pool:         conn.NewPool(ctx, cfg),
+		discoveryClient: discoveryMock{endpoints: []endpoint.Endpoint{
+			endpoint.New("a:1", endpoint.WithID(1)),
+			endpoint.New("b:2", endpoint.WithID(2)),
+			endpoint.New("c:3", endpoint.WithID(3)),
+		}},
+	}
+	require.NoError(t, b.clusterDiscoveryAttempt(ctx))
+
+	ban := func(address string) {
+		for _, cc := range b.connections().all {
+			if cc.Endpoint().Address() == address {
+				b.pool.Ban(ctx, cc, xerrors.Transport(grpcStatus.Error(grpcCodes.Unavailable, "")))
+				require.Equal(t, conn.Banned, cc.GetState())
+			}
+		}
+	}
+	banned := func() (addresses []string) {
+		for _, cc := range b.connections().all {
+			if cc.GetState() == conn.Banned {
+				addresses = append(addresses, cc.Endpoint().Address())
+			}
+		}
+
+		return addresses
+	}
+
+	ban("a:1")
+	ban("b:2")
+	allowed = nil
+
+	t.Run("Unban", func(t *testing.T) {
+		require.True(t, b.Unban(ctx, endpoint.New("a:1", endpoint.WithID(1))))
+		require.Equal(t, []string{"b:2"}, banned())
+		require.Equal(t, []string{"a:1"}, allowed)
+
+		require.False(t, b.Unban(ctx, endpoint.New("c:3", endpoint.WithID(3))))
+		require.Equal(t, []string{"a:1"}, allowed)
+	})
+	t.Run("UnbanAll", func(t *testing.T) {
+		allowed = nil
+		ban("c:3")
+		allowed = nil
+		require.Equal(t, 2, b.UnbanAll(ctx))
+		require.Empty(t, banned())
+		require.ElementsMatch(t, []string{"b:2", "c:3"}, allowed)
+		require.Equal(t, 0, b.UnbanAll(ctx))
+	})
+}
+
+func TestCallTrace(t *testing.T) {
+	ctx := context.Background()
+	var (
+		starts []trace.DriverBalancerCallStartInfo
+		dones  []trace.DriverBalancerCallDoneInfo
+	)
+	cfg := config.New(
+		config.WithDatabase("/local"),
+		config.WithTrace(trace.Driver{
+			OnBalancerCall: func(info trace.DriverBalancerCallStartInfo) func(trace.DriverBalancerCallDoneInfo) {
+				starts = append(starts, info)
+				*info.Context = metadata.AppendToOutgoingContext(*info.Context, "traceparent", "test")
+
+				return func(info trace.DriverBalancerCallDoneInfo) {
+					dones = append(dones, info)
+				}
+			},
+		}),
+	)
+	b := &Balancer{
+		driverConfig: cfg,
+		pool:         conn.NewPool(ctx, cfg),
+		discoveryClient: discoveryMock{endpoints: []endpoint.Endpoint{
+			endpoint.New("a:1", endpoint.WithID(1)),
+		}},
+	}
+	require.NoError(t, b.clusterDiscoveryAttempt(ctx))
+
+	callErr := errors.New("test")
+	err := retry.Retry(ctx, func(ctx context.Context) error {
+		_, err := b.wrapCall(ctx, "/test.Service/Method", func(ctx context.Context, cc conn.Conn) error {
+			md, has := metadata.FromOutgoingContext(ctx)
+			require.True(t, has)
+			require.Equal(t, []string{"test"}, md.Get("traceparent"))
+			require.Equal(t, []string{"/local"}, md.Get(meta.HeaderDatabase))
+
+			if len(starts) == 1 {
+				return xerrors.Retryable(callErr)
+			}
+
+			return nil
+		})
+
+		return err
+	}, retry.WithFastBackoff(nil))
+	require.NoError(t, err)
+
+	require.Len(t, starts, 2)
+	require.Equal(t, "/test.Service/Method", starts[0].Method)
+	require.Equal(t, 1, starts[0].Attempt)
+	require.Equal(t, 2, starts[1].Attempt)
+	require.Len(t, dones, 2)
+	require.ErrorIs(t, dones[0].Error, callErr)
+	require.Equal(t, "a:1", dones[0].Endpoint.Address())
+	require.NoError(t, dones[1].Error)
+	require.Equal(t, "a:1", dones[1].Endpoint.Address())
+
+	t.Run("NoEndpoints", func(t *testing.T) {
+		b := &Balancer{driverConfig: cfg}
+		b.connectionsState.Store(newConnectionsState(nil, nil, balancerConfig.Info{}, false))
+		_, err := b.wrapCall(ctx, "/test.Service/Method", func(ctx context.Context, cc conn.Conn) error {
+			return nil
+		})
+		require.ErrorIs(t, err, ErrNoEndpoints)
+		require.Nil(t, dones[2].Endpoint)
+		require.ErrorIs(t, dones[2].Error, ErrNoEndpoints)
+	})
+}
+
+func TestInFlightCalls(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.New()
+	b := &Balancer{
+		driverConfig: cfg,
+		pool:         conn.NewPool(ctx, cfg),
+		discoveryClient: discoveryMock{endpoints: []endpoint.Endpoint{
+			&mock.Endpoint{AddrField: "a:123", NodeIDField: 1},
+		}},
+	}
+	require.NoError(t, b.clusterDiscoveryAttempt(ctx))
+	require.Equal(t, map[string]int{"a:123": 0}, b.Stats().InFlightCalls)
+
+	_, err := b.wrapCall(ctx, "", func(ctx context.Context, cc conn.Conn) error {
+		require.Equal(t, 1, cc.InFlight())
+		_, err := b.wrapCall(ctx, "", func(ctx context.Context, cc conn.Conn) error {
+			require.Equal(t, map[string]int{"a:123": 2}, b.Stats().InFlightCalls)
+
+			return nil
+		})
+
+		return err
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{"a:123": 0}, b.Stats().InFlightCalls)
+
+	t.Run("Panic", func(t *testing.T) {
+		require.Panics(t, func() {
+			_, _ = b.wrapCall(ctx, "", func(ctx context.Context, cc conn.Conn) error {
+				panic("test")
+			})
+		})
+		require.Equal(t, map[string]int{"a:123": 0}, b.Stats().InFlightCalls)
+	})
+}
+
+func TestRecycleEndpoint(t *testing.T) {
+	ctx := context.Background()
+	r := trace.Recorder()
+	cfg := config.New(config.WithTrace(r.Driver))
+	b := &Balancer{
+		driverConfig: cfg,
+		pool:         conn.NewPool(ctx, cfg),
+		discoveryClient: discoveryMock{endpoints: []endpoint.Endpoint{
+			&mock.Endpoint{AddrField: "a:123", NodeIDField: 1},
+		}},
+	}
+	require.NoError(t, b.clusterDiscoveryAttempt(ctx))
+
+	t.Run("Known", func(t *testing.T) {
+		r.Reset()
+		require.NoError(t, b.RecycleEndpoint(ctx, &mock.Endpoint{AddrField: "a:123", NodeIDField: 1}))
+		events := r.Events("OnBalancerRecycleEndpoint")
+		require.Len(t, events, 1)
+		require.Equal(t, "a:123", events[0].Info.(trace.DriverBalancerRecycleEndpointStartInfo).Endpoint.Address())
+		require.NoError(t, events[0].Done.(trace.DriverBalancerRecycleEndpointDoneInfo).Error)
+
+		cc, err := b.getConn(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "a:123", cc.Endpoint().Address())
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		r.Reset()
+		err := b.RecycleEndpoint(ctx, &mock.Endpoint{AddrField: "b:123", NodeIDField: 2})
+		require.ErrorIs(t, err, ErrUnknownEndpoint)
+		events := r.Events("OnBalancerRecycleEndpoint")
+		require.Len(t, events, 1)
+		require.ErrorIs(t, events[0].Done.(trace.DriverBalancerRecycleEndpointDoneInfo).Error, ErrUnknownEndpoint)
+	})
+}
+
+type discoveryConnMock struct {
+	invokes int
+	closed  bool
+}
+
+func (cc *discoveryConnMock) Invoke(
+	ctx context.Context, method string, args interface{}, reply interface{}, opts ...grpc.CallOption,
+) error {
+	cc.invokes++
+	reply.(*Ydb_Discovery.ListEndpointsResponse).Operation = &Ydb_Operations.Operation{
+		Ready:  true,
+		Status: Ydb.StatusIds_SUCCESS,
+		Result: xtest.Must(anypb.New(&Ydb_Discovery.ListEndpointsResult{
+			Endpoints: []*Ydb_Discovery.EndpointInfo{
+				{Address: "a", Port: 123},
+				{Address: "b", Port: 234},
+			},
+		})),
+	}
+
+	return nil
+}
+
+func (cc *discoveryConnMock) NewStream(
+	ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption,
+) (grpc.ClientStream, error) {
+	panic("not implemented in mock")
+}
+
+func (cc *discoveryConnMock) Close() error {
+	cc.closed = true
+
+	return nil
+}
+
+func TestNewWithDiscoveryConn(t *testing.T) {
+	ctx := context.Background()
+	cc := &discoveryConnMock{}
+	cfg := config.New(
+		config.WithEndpoint("initial:2135"),
+		config.WithBalancer(balancers.Default().With(balancerConfig.WithDiscoveryConn(cc))),
+	)
+	b, err := New(ctx, cfg, conn.NewPool(ctx, cfg))
+	require.NoError(t, err)
+	require.Len(t, b.connections().All(), 2)
+	require.Equal(t, 1, cc.invokes)
+
+	_, err = b.ForceDiscovery(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 2, cc.invokes)
+
+	require.NoError(t, b.Close(ctx))
+	require.False(t, cc.closed)
+}
+
+func TestDialGuard(t *testing.T) {
+	ctx := context.Background()
+	r := trace.Recorder()
+	errForbidden := errors.New("forbidden")
+	var checked []string
+	cfg := config.New(
+		config.WithTrace(r.Driver),
+		config.WithBalancer(balancers.Default().With(
+			balancerConfig.WithDialGuard(func(ctx context.Context, e endpoint.Endpoint) error {
+				checked = append(checked, e.Address())
+
+				return errForbidden
+			}),
+		)),
+	)
+	b := &Balancer{
+		driverConfig: cfg,
+		config:       *cfg.Balancer(),
+		pool:         conn.NewPool(ctx, cfg),
+		discoveryClient: discoveryMock{endpoints: []endpoint.Endpoint{
+			&mock.Endpoint{AddrField: "a:123", NodeIDField: 1},
+		}},
+	}
+	require.NoError(t, b.clusterDiscoveryAttempt(ctx))
+
+	cc, err := b.getConn(ctx)
+	require.NoError(t, err)
+
+	r.Reset()
+	err = cc.Invoke(ctx, "/Ydb.Test/Method", nil, nil)
+	require.ErrorIs(t, err, conn.ErrDialRefused)
+	require.ErrorIs(t, err, errForbidden)
+	require.Equal(t, []string{"a:123"}, checked)
+	require.Equal(t, conn.Banned, cc.GetState())
+
+	dials := r.Events("OnConnDial")
+	require.Len(t, dials, 1)
+	require.ErrorIs(t, dials[0].Done.(trace.DriverConnDialDoneInfo).Error, errForbidden)
+	bans := r.Events("OnConnBan")
+	require.Len(t, bans, 1)
+	require.ErrorIs(t, bans[0].Info.(trace.DriverConnBanStartInfo).Cause, errForbidden)
+
+	t.Run("AllowedOnNextDiscovery", func(t *testing.T) {
+		require.NoError(t, b.clusterDiscoveryAttempt(ctx))
+		require.NotEqual(t, conn.Banned, cc.GetState())
+	})
+
+	t.Run("CheckedOnEveryDial", func(t *testing.T) {
+		err := cc.Invoke(ctx, "/Ydb.Test/Method", nil, nil)
+		require.ErrorIs(t, err, errForbidden)
+		require.Equal(t, []string{"a:123", "a:123"}, checked)
+	})
+}
+
+func TestRequestTags(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.New(config.WithDatabase("/local"))
+	b := &Balancer{
+		driverConfig: cfg,
+		pool:         conn.NewPool(ctx, cfg),
+		discoveryClient: discoveryMock{endpoints: []endpoint.Endpoint{
+			endpoint.New("a:1", endpoint.WithID(1)),
+		}},
+	}
+	require.NoError(t, b.clusterDiscoveryAttempt(ctx))
+
+	ctx = conn.WithRequestTag(ctx, "tenant", "a")
+	ctx = conn.WithRequestTag(ctx, "Feature", "b")
+	ctx = conn.WithRequestTag(ctx, "database", "c")
+	_, err := b.wrapCall(ctx, "/test.Service/Method", func(ctx context.Context, cc conn.Conn) error {
+		md, has := metadata.FromOutgoingContext(ctx)
+		require.True(t, has)
+		require.Equal(t, []string{"a"}, md.Get(meta.HeaderRequestTagPrefix+"tenant"))
+		require.Equal(t, []string{"b"}, md.Get(meta.HeaderRequestTagPrefix+"feature"))
+		require.Equal(t, []string{"c"}, md.Get(meta.HeaderRequestTagPrefix+"database"))
+		require.Equal(t, []string{"/local"}, md.Get(meta.HeaderDatabase))
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	t.Run("Override", func(t *testing.T) {
+		ctx := conn.WithRequestTag(ctx, "tenant", "d")
+		_, err := b.wrapCall(ctx, "/test.Service/Method", func(ctx context.Context, cc conn.Conn) error {
+			md, _ := metadata.FromOutgoingContext(ctx)
+			require.Equal(t, []string{"d"}, md.Get(meta.HeaderRequestTagPrefix+"tenant"))
+
+			return nil
+		})
+		require.NoError(t, err)
+	})
+}
+
+func TestMinEndpointsFraction(t *testing.T) {
+	ctx := context.Background()
+	r := trace.Recorder()
+	cfg := config.New(
+		config.WithTrace(r.Driver),
+		config.WithBalancer(balancers.Default().With(
+			balancerConfig.WithMinEndpointsFraction(0.5),
+		)),
+	)
+	all := []endpoint.Endpoint{
+		&mock.Endpoint{AddrField: "a:1", NodeIDField: 1},
+		&mock.Endpoint{AddrField: "b:1", NodeIDField: 2},
+		&mock.Endpoint{AddrField: "c:1", NodeIDField: 3},
+		&mock.Endpoint{AddrField: "d:1", NodeIDField: 4},
+	}
+	b := &Balancer{
+		driverConfig:    cfg,
+		config:          *cfg.Balancer(),
+		pool:            conn.NewPool(ctx, cfg),
+		discoveryClient: discoveryMock{endpoints: all},
+	}
+	require.NoError(t, b.clusterDiscoveryAttempt(ctx))
+	require.Len(t, b.connections().All(), 4)
+
+	partial := func(t *testing.T) []trace.DriverBalancerPartialDiscoveryInfo {
+		t.Helper()
+
+		return xslices.Transform(r.Events("OnBalancerPartialDiscovery"), func(e trace.DriverEvent) trace.DriverBalancerPartialDiscoveryInfo {
+			return e.Info.(trace.DriverBalancerPartialDiscoveryInfo)
+		})
+	}
+
+	t.Run("ShrinkWithinFraction", func(t *testing.T) {
+		r.Reset()
+		b.discoveryClient = discoveryMock{endpoints: all[:3]}
+		require.NoError(t, b.clusterDiscoveryAttempt(ctx))
+		require.Len(t, b.connections().All(), 3)
+		require.Empty(t, partial(t))
+
+		b.discoveryClient = discoveryMock{endpoints: all}
+		require.NoError(t, b.clusterDiscoveryAttempt(ctx))
+		require.Len(t, b.connections().All(), 4)
+	})
+
+	t.Run("ShrinkProtection", func(t *testing.T) {
+		r.Reset()
+		b.discoveryClient = discoveryMock{endpoints: all[:1]}
+		for i := 1; i <= balancerConfig.MaxPartialDiscoveries; i++ {
+			endpoints, err := b.clusterDiscoveryAttemptWithEndpoints(ctx)
+			require.NoError(t, err)
+			require.Len(t, endpoints, 4)
+			require.Len(t, b.connections().All(), 4)
+			events := partial(t)
+			require.Len(t, events, i)
+			require.Equal(t, 4, events[i-1].Previous)
+			require.Equal(t, 1, events[i-1].Discovered)
+			require.Equal(t, i, events[i-1].Attempts)
+			require.False(t, events[i-1].Applied)
+		}
+
+		require.NoError(t, b.clusterDiscoveryAttempt(ctx))
+		require.Len(t, b.connections().All(), 1)
+		events := partial(t)
+		require.Len(t, events, balancerConfig.MaxPartialDiscoveries+1)
+		require.True(t, events[len(events)-1].Applied)
+	})
+
+	t.Run("ResetOnFullDiscovery", func(t *testing.T) {
+		b.discoveryClient = discoveryMock{endpoints: all}
+		require.NoError(t, b.clusterDiscoveryAttempt(ctx))
+		require.Len(t, b.connections().All(), 4)
+
+		r.Reset()
+		b.discoveryClient = discoveryMock{endpoints: all[:1]}
+		require.NoError(t, b.clusterDiscoveryAttempt(ctx))
+		b.discoveryClient = discoveryMock{endpoints: all}
+		require.NoError(t, b.clusterDiscoveryAttempt(ctx))
+		b.discoveryClient = discoveryMock{endpoints: all[:1]}
+		require.NoError(t, b.clusterDiscoveryAttempt(ctx))
+		require.Len(t, b.connections().All(), 4)
+		events := partial(t)
+		require.Len(t, events, 2)
+		require.Equal(t, 1, events[1].Attempts)
+	})
+}
+
+func TestPreferNodeID(t *testing.T) {
+	r := trace.Recorder()
+	cfg := config.New(config.WithTrace(r.Driver))
+	b := &Balancer{driverConfig: cfg}
+	b.connectionsState.Store(newConnectionsState([]conn.Conn{
+		&mock.Conn{AddrField: "1", NodeIDField: 1, State: conn.Online},
+		&mock.Conn{AddrField: "2", NodeIDField: 2, State: conn.Online},
+		&mock.Conn{AddrField: "3", NodeIDField: 3, State: conn.Unknown},
+	}, nil, balancerConfig.Info{}, false))
+	chosen := func(t *testing.T) trace.DriverBalancerChooseEndpointDoneInfo {
+		t.Helper()
+
+		events := r.Events("OnBalancerChooseEndpoint")
+		require.NotEmpty(t, events)
+
+		return events[len(events)-1].Done.(trace.DriverBalancerChooseEndpointDoneInfo)
+	}
+
+	t.Run("Prefer", func(t *testing.T) {
+		c, err := b.getConn(conn.WithPreferNodeID(context.Background(), 2))
+		require.NoError(t, err)
+		require.Equal(t, uint32(2), c.Endpoint().NodeID())
+		require.Equal(t, uint32(2), chosen(t).PreferredNodeID)
+	})
+
+	t.Run("PreferUnavailable", func(t *testing.T) {
+		c, err := b.getConn(conn.WithPreferNodeID(context.Background(), 3))
+		require.NoError(t, err)
+		require.NotEqual(t, uint32(3), c.Endpoint().NodeID())
+		require.Zero(t, chosen(t).PreferredNodeID)
+	})
+
+	t.Run("Strict", func(t *testing.T) {
+		c, err := b.getConn(conn.WithStrictNodeID(context.Background(), 1))
+		require.NoError(t, err)
+		require.Equal(t, uint32(1), c.Endpoint().NodeID())
+		require.Equal(t, uint32(1), chosen(t).PreferredNodeID)
+	})
+
+	t.Run("StrictUnavailable", func(t *testing.T) {
+		for _, nodeID := range []uint32{3, 4} {
+			_, err := b.getConn(conn.WithStrictNodeID(context.Background(), nodeID))
+			require.ErrorIs(t, err, conn.ErrNodeUnavailable)
+			require.ErrorIs(t, chosen(t).Error, conn.ErrNodeUnavailable)
+		}
+	})
+}
+
+func TestDuplicateEndpoints(t *testing.T) {
+	ctx := context.Background()
+	var duplicates []string
+	cfg := config.New(config.WithTrace(trace.Driver{
+		OnBalancerDuplicateEndpoints: func(info trace.DriverBalancerDuplicateEndpointsInfo) {
+			for _, e := range info.Duplicates {
+				duplicates = append(duplicates, e.Address())
+			}
+		},
+	}))
+	b := &Balancer{
+		driverConfig: cfg,
+		config:       *cfg.Balancer(),
+		pool:         conn.NewPool(ctx, cfg),
+		discoveryClient: discoveryMock{endpoints: []endpoint.Endpoint{
+			&mock.Endpoint{AddrField: "a:1", LocationField: "a", NodeIDField: 1},
+			&mock.Endpoint{AddrField: "b:2", LocationField: "b", NodeIDField: 2},
+			&mock.Endpoint{AddrField: "a:1", LocationField: "c", NodeIDField: 1},
+			&mock.Endpoint{AddrField: "c:3", LocationField: "c", NodeIDField: 3},
+			&mock.Endpoint{AddrField: "b:2", LocationField: "b", NodeIDField: 2},
+		}},
+	}
+
+	require.NoError(t, b.clusterDiscoveryAttempt(ctx))
+	require.Equal(t, []string{"a:1", "b:2"}, duplicates)
+
+	locations := make(map[string]string)
+	for _, c := range b.connections().all {
+		_, has := locations[c.Endpoint().Address()]
+		require.False(t, has, c.Endpoint().Address())
+		locations[c.Endpoint().Address()] = c.Endpoint().Location()
+	}
+	require.Equal(t, map[string]string{"a:1": "a", "b:2": "b", "c:3": "c"}, locations)
+
+	t.Run("Unique", func(t *testing.T) {
+		endpoints := []endpoint.Endpoint{
+			&mock.Endpoint{AddrField: "a:1"},
+			&mock.Endpoint{AddrField: "b:2"},
+		}
+		unique, duplicates := uniqueEndpoints(endpoints)
+		require.Equal(t, endpoints, unique)
+		require.Empty(t, duplicates)
+	})
+}
+
+func TestDirectEndpoint(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.New()
+	b := &Balancer{
+		driverConfig: cfg,
+		config:       *cfg.Balancer(),
+		pool:         conn.NewPool(ctx, cfg),
+		discoveryClient: discoveryMock{endpoints: []endpoint.Endpoint{
+			&mock.Endpoint{AddrField: "a:1", NodeIDField: 1},
+			&mock.Endpoint{AddrField: "b:2", NodeIDField: 2},
+		}},
+	}
+	require.NoError(t, b.clusterDiscoveryAttempt(ctx))
+
+	var banned int
+	b.OnBan(func(ctx context.Context, e trace.EndpointInfo, err error) {
+		banned++
+	})
+	call := func(address string) (conn.Conn, error) {
+		var cc conn.Conn
+		_, err := b.wrapCall(conn.WithDirectEndpoint(ctx, address), "test",
+			func(ctx context.Context, c conn.Conn) error {
+				cc = c
+
+				return xerrors.Transport(grpcStatus.Error(grpcCodes.Unavailable, ""))
+			},
+		)
+
+		return cc, err
+	}
+
+	t.Run("NewConn", func(t *testing.T) {
+		cc, err := call("c:3")
+		require.Error(t, err)
+		require.Equal(t, "c:3", cc.Endpoint().Address())
+		require.NotEqual(t, conn.Banned, cc.GetState())
+		require.Len(t, b.connections().All(), 2)
+	})
+	t.Run("PooledConn", func(t *testing.T) {
+		cc, err := call("b:2")
+		require.Error(t, err)
+		require.Equal(t, "b:2", cc.Endpoint().Address())
+		require.Equal(t, uint32(2), cc.Endpoint().NodeID())
+		require.NotEqual(t, conn.Banned, cc.GetState())
+	})
+	require.Zero(t, banned)
+}
+
+func TestKeepStateOnEmptyDiscovery(t *testing.T) {
+	ctx := context.Background()
+	r := trace.Recorder()
+	cfg := config.New(
+		config.WithTrace(r.Driver),
+		config.WithBalancer(balancers.Default().With(
+			balancerConfig.WithKeepStateOnEmptyDiscovery(true),
+		)),
+	)
+	all := []endpoint.Endpoint{
+		&mock.Endpoint{AddrField: "a:1", NodeIDField: 1},
+		&mock.Endpoint{AddrField: "b:1", NodeIDField: 2},
+	}
+	b := &Balancer{
+		driverConfig:    cfg,
+		config:          *cfg.Balancer(),
+		pool:            conn.NewPool(ctx, cfg),
+		discoveryClient: discoveryMock{endpoints: all},
+	}
+	require.NoError(t, b.clusterDiscoveryAttempt(ctx))
+	require.Len(t, b.connections().All(), 2)
+
+	partial := func(t *testing.T) []trace.DriverBalancerPartialDiscoveryInfo {
+		t.Helper()
+
+		return xslices.Transform(r.Events("OnBalancerPartialDiscovery"), func(e trace.DriverEvent) trace.DriverBalancerPartialDiscoveryInfo {
+			return e.Info.(trace.DriverBalancerPartialDiscoveryInfo)
+		})
+	}
+
+	t.Run("ShrinkNotSkipped", func(t *testing.T) {
+		r.Reset()
+		b.discoveryClient = discoveryMock{endpoints: all[:1]}
+		require.NoError(t, b.clusterDiscoveryAttempt(ctx))
+		require.Len(t, b.connections().All(), 1)
+		require.Empty(t, partial(t))
+
+		b.discoveryClient = discoveryMock{endpoints: all}
+		require.NoError(t, b.clusterDiscoveryAttempt(ctx))
+		require.Len(t, b.connections().All(), 2)
+	})
+
+	t.Run("RetainThenClear", func(t *testing.T) {
+		r.Reset()
+		b.discoveryClient = discoveryMock{}
+		for i := 1; i <= balancerConfig.MaxEmptyDiscoveries; i++ {
+			require.NoError(t, b.clusterDiscoveryAttempt(ctx))
+			require.Len(t, b.connections().All(), 2)
+			c, err := b.getConn(ctx)
+			require.NoError(t, err)
+			require.NotNil(t, c)
+			events := partial(t)
+			require.Len(t, events, i)
+			require.Equal(t, 2, events[i-1].Previous)
+			require.Equal(t, 0, events[i-1].Discovered)
+			require.False(t, events[i-1].Applied)
+		}
+
+		require.NoError(t, b.clusterDiscoveryAttempt(ctx))
+		require.Empty(t, b.connections().All())
+		events := partial(t)
+		require.Len(t, events, balancerConfig.MaxEmptyDiscoveries+1)
+		require.True(t, events[len(events)-1].Applied)
+	})
+
+	t.Run("ResetOnNonEmptyDiscovery", func(t *testing.T) {
+		b.discoveryClient = discoveryMock{endpoints: all}
+		require.NoError(t, b.clusterDiscoveryAttempt(ctx))
+		require.Len(t, b.connections().All(), 2)
+
+		for i := 0; i < 2; i++ {
+			b.discoveryClient = discoveryMock{}
+			for j := 0; j < balancerConfig.MaxEmptyDiscoveries; j++ {
+				require.NoError(t, b.clusterDiscoveryAttempt(ctx))
+			}
+			b.discoveryClient = discoveryMock{endpoints: all}
+			require.NoError(t, b.clusterDiscoveryAttempt(ctx))
+			require.Len(t, b.connections().All(), 2)
+		}
+	})
+}
@@ -0,0 +1,128 @@
+package balancer
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/conn"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/stack"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xcontext"
+	"github.com/ydb-platform/ydb-go-sdk/v3/trace"
+)
+
+// banRecovery probes banned connections with exponential backoff and allows connection
+// after first successful probe. Probing stops if connection not banned anymore (such as allowed by discovery)
+type banRecovery struct {
+	minInterval time.Duration
+	maxInterval time.Duration
+	clock       clockwork.Clock
+	trace       *trace.Driver
+	allow       func(ctx context.Context, c conn.Conn)
+
+	ctx    context.Context //nolint:containedctx
+	cancel context.CancelFunc
+
+	mu      sync.Mutex
+	probing map[string]struct{}
+}
+
+func newBanRecovery(
+	ctx context.Context,
+	minInterval, maxInterval time.Duration,
+	clock clockwork.Clock,
+	t *trace.Driver,
+	allow func(ctx context.Context, c conn.Conn),
+) *banRecovery {
+	ctx, cancel := xcontext.WithCancel(xcontext.ValueOnly(ctx))
+
+	if maxInterval < minInterval {
+		maxInterval = minInterval
+	}
+
+	return &banRecovery{
+		minInterval: minInterval,
+		maxInterval: maxInterval,
+		clock:       clock,
+		trace:       t,
+		allow:       allow,
+		ctx:         ctx,
+		cancel:      cancel,
+		probing:     make(map[string]struct{}),
+	}
+}
+
+// probe starts probing of banned connection if connection not probing yet
+func (r *banRecovery) probe(c conn.Conn) {
+	if r == nil {
+		return
+	}
+
+	address := c.Endpoint().Address()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, has := r.probing[address]; has {
+		return
+	}
+	r.probing[address] = struct{}{}
+
+	go r.run(c)
+}
+
+func (r *banRecovery) run(c conn.Conn) {
+	defer func() {
+		r.mu.Lock()
+		delete(r.probing, c.Endpoint().Address())
+		r.mu.Unlock()
+	}()
+
+	interval := r.minInterval
+	for attempts := 1; ; attempts++ {
+		timer := r.clock.NewTimer(interval)
+		select {
+		case <-r.ctx.Done():
+			timer.Stop()
+
+			return
+		case <-timer.Chan():
+		}
+
+		if c.GetState() != conn.Banned {
+			return
+		}
+
+		if r.ping(c) == nil {
+			ctx := r.ctx
+			r.allow(ctx, c)
+			trace.DriverOnBalancerBanRecovery(r.trace, &ctx,
+				stack.FunctionID("github.com/ydb-platform/ydb-go-sdk/v3/internal/balancer.(*banRecovery).run"),
+				c.Endpoint(), attempts,
+			)
+
+			return
+		}
+
+		if interval *= 2; interval > r.maxInterval {
+			interval = r.maxInterval
+		}
+	}
+}
+
+func (r *banRecovery) ping(c conn.Conn) error {
+	ctx, cancel := xcontext.WithTimeout(r.ctx, r.maxInterval)
+	defer cancel()
+
+	return c.Ping(ctx)
+}
+
+func (r *banRecovery) stop() {
+	if r == nil {
+		return
+	}
+
+	r.cancel()
+}
@@ -0,0 +1,71 @@
+package balancer
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/conn"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/mock"
+	"github.com/ydb-platform/ydb-go-sdk/v3/trace"
+)
+
+func TestBanRecovery(t *testing.T) {
+	ctx := context.Background()
+	clock := clockwork.NewFakeClock()
+
+	var (
+		mu        sync.Mutex
+		allowed   = make(chan string, 2)
+		recovered = make(chan int, 1)
+	)
+	r := newBanRecovery(ctx, time.Second, 4*time.Second, clock, &trace.Driver{
+		OnBalancerBanRecovery: func(info trace.DriverBalancerBanRecoveryInfo) {
+			recovered <- info.Attempts
+		},
+	}, func(ctx context.Context, c conn.Conn) {
+		mu.Lock()
+		defer mu.Unlock()
+		c.SetState(ctx, conn.Online)
+		allowed <- c.Endpoint().Address()
+	})
+	defer r.stop()
+
+	t.Run("Recovered", func(t *testing.T) {
+		c := &mock.Conn{AddrField: "a:123", State: conn.Banned}
+		r.probe(c)
+		r.probe(c) // duplicate probe ignored
+		clock.BlockUntil(1)
+		clock.Advance(time.Second)
+		require.Equal(t, "a:123", <-allowed)
+		require.Equal(t, 1, <-recovered)
+	})
+
+	t.Run("ExponentialBackoff", func(t *testing.T) {
+		c := &mock.Conn{AddrField: "b:234", State: conn.Banned, PingErr: errors.New("unavailable")}
+		r.probe(c)
+		for _, interval := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 4 * time.Second} {
+			clock.BlockUntil(1)
+			clock.Advance(interval - time.Nanosecond)
+			require.Empty(t, allowed)
+			clock.Advance(time.Nanosecond)
+		}
+		clock.BlockUntil(1)
+		mu.Lock()
+		c.SetState(ctx, conn.Online)
+		mu.Unlock()
+		clock.Advance(4 * time.Second)
+		require.Eventually(t, func() bool {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+
+			return len(r.probing) == 0
+		}, time.Second, time.Millisecond)
+		require.Empty(t, allowed)
+	})
+}
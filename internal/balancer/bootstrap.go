@@ -0,0 +1,57 @@
+package balancer
+
+import (
+	"context"
+	"time"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/endpoint"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xcontext"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xerrors"
+)
+
+// bootstrapDiscoveryClients tries discovery clients of bootstrap endpoints in order
+// until one of them answers on discovery request. If context has deadline - every client tries
+// within equal part of remaining time, so unreachable endpoint not consumes deadline of other endpoints
+type bootstrapDiscoveryClients []discoveryClient
+
+func (clients bootstrapDiscoveryClients) Discover(ctx context.Context) ([]endpoint.Endpoint, error) {
+	errs := make([]error, 0, len(clients))
+	for i, client := range clients {
+		endpoints, err := discoverWithinPart(ctx, client, len(clients)-i)
+		if err == nil {
+			return endpoints, nil
+		}
+		errs = append(errs, err)
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	return nil, xerrors.WithStackTrace(xerrors.Join(errs...))
+}
+
+// discoverWithinPart calls discovery client within 1/parts of remaining time of context
+func discoverWithinPart(ctx context.Context, client discoveryClient, parts int) ([]endpoint.Endpoint, error) {
+	if deadline, has := ctx.Deadline(); has && parts > 1 {
+		var cancel context.CancelFunc
+		ctx, cancel = xcontext.WithTimeout(ctx, time.Until(deadline)/time.Duration(parts))
+		defer cancel()
+	}
+
+	return client.Discover(ctx)
+}
+
+func (clients bootstrapDiscoveryClients) Close(ctx context.Context) error {
+	errs := make([]error, 0, len(clients))
+	for _, client := range clients {
+		if err := client.Close(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return xerrors.WithStackTrace(xerrors.Join(errs...))
+	}
+
+	return nil
+}
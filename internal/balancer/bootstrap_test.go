@@ -0,0 +1,77 @@
+package balancer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/endpoint"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/mock"
+)
+
+func TestBootstrapDiscoveryClients(t *testing.T) {
+	ctx := context.Background()
+	var (
+		errFirst  = errors.New("first")
+		errSecond = errors.New("second")
+		endpoints = []endpoint.Endpoint{
+			&mock.Endpoint{AddrField: "a:123"},
+		}
+	)
+	t.Run("FirstOk", func(t *testing.T) {
+		clients := bootstrapDiscoveryClients{
+			discoveryMock{endpoints: endpoints},
+			discoveryErrorMock{err: errFirst},
+		}
+		res, err := clients.Discover(ctx)
+		require.NoError(t, err)
+		require.Equal(t, endpoints, res)
+	})
+	t.Run("SecondOk", func(t *testing.T) {
+		clients := bootstrapDiscoveryClients{
+			discoveryErrorMock{err: errFirst},
+			discoveryMock{endpoints: endpoints},
+		}
+		res, err := clients.Discover(ctx)
+		require.NoError(t, err)
+		require.Equal(t, endpoints, res)
+	})
+	t.Run("FirstHangs", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(ctx, time.Second)
+		defer cancel()
+		clients := bootstrapDiscoveryClients{
+			discoveryHangMock{},
+			discoveryMock{endpoints: endpoints},
+		}
+		res, err := clients.Discover(ctx)
+		require.NoError(t, err)
+		require.Equal(t, endpoints, res)
+		require.NoError(t, ctx.Err())
+	})
+	t.Run("AllFailed", func(t *testing.T) {
+		clients := bootstrapDiscoveryClients{
+			discoveryErrorMock{err: errFirst},
+			discoveryErrorMock{err: errSecond},
+		}
+		res, err := clients.Discover(ctx)
+		require.Nil(t, res)
+		require.ErrorIs(t, err, errFirst)
+		require.ErrorIs(t, err, errSecond)
+	})
+}
+
+// discoveryHangMock answers on discovery request only on done of context (such as unreachable endpoint)
+type discoveryHangMock struct{}
+
+func (d discoveryHangMock) Close(ctx context.Context) error {
+	return nil
+}
+
+func (d discoveryHangMock) Discover(ctx context.Context) ([]endpoint.Endpoint, error) {
+	<-ctx.Done()
+
+	return nil, ctx.Err()
+}
@@ -0,0 +1,192 @@
+package balancer
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+
+	balancerConfig "github.com/ydb-platform/ydb-go-sdk/v3/internal/balancer/config"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/conn"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/stack"
+	"github.com/ydb-platform/ydb-go-sdk/v3/trace"
+)
+
+type CircuitBreakerState int
+
+const (
+	CircuitBreakerClosed = CircuitBreakerState(iota)
+	CircuitBreakerOpen
+	CircuitBreakerHalfOpen
+)
+
+func (s CircuitBreakerState) String() string {
+	switch s {
+	case CircuitBreakerClosed:
+		return "closed"
+	case CircuitBreakerOpen:
+		return "open"
+	case CircuitBreakerHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+type circuitBreaker struct {
+	state    CircuitBreakerState
+	failures []time.Time
+	openedAt time.Time
+}
+
+// circuitBreakers contains per-endpoint circuit breakers.
+// Circuit breakers are keyed by endpoint address and survive discovery cycles
+type circuitBreakers struct {
+	config balancerConfig.CircuitBreaker
+	clock  clockwork.Clock
+	trace  *trace.Driver
+
+	mu       sync.Mutex
+	breakers map[string]*circuitBreaker
+}
+
+func newCircuitBreakers(
+	config balancerConfig.CircuitBreaker, clock clockwork.Clock, t *trace.Driver,
+) *circuitBreakers {
+	return &circuitBreakers{
+		config:   config,
+		clock:    clock,
+		trace:    t,
+		breakers: make(map[string]*circuitBreaker),
+	}
+}
+
+// available checks that connection is not refused by circuit breaker
+func (cb *circuitBreakers) available(c conn.Conn) bool {
+	if cb == nil {
+		return true
+	}
+
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	breaker, has := cb.breakers[c.Endpoint().Address()]
+	if !has {
+		return true
+	}
+
+	switch breaker.state {
+	case CircuitBreakerOpen:
+		return cb.clock.Since(breaker.openedAt) >= cb.config.Cooldown
+	case CircuitBreakerHalfOpen:
+		return false
+	default:
+		return true
+	}
+}
+
+// onCall switches open circuit breaker with elapsed cooldown to half-open state for single probe call
+func (cb *circuitBreakers) onCall(ctx context.Context, c conn.Conn) {
+	if cb == nil {
+		return
+	}
+
+	cb.transit(ctx, c, func(breaker *circuitBreaker) {
+		if breaker.state == CircuitBreakerOpen && cb.clock.Since(breaker.openedAt) >= cb.config.Cooldown {
+			breaker.state = CircuitBreakerHalfOpen
+		}
+	})
+}
+
+func (cb *circuitBreakers) onSuccess(ctx context.Context, c conn.Conn) {
+	if cb == nil {
+		return
+	}
+
+	cb.transit(ctx, c, func(breaker *circuitBreaker) {
+		if breaker.state == CircuitBreakerHalfOpen {
+			breaker.state = CircuitBreakerClosed
+			breaker.failures = breaker.failures[:0]
+		}
+	})
+}
+
+func (cb *circuitBreakers) onFailure(ctx context.Context, c conn.Conn) {
+	if cb == nil {
+		return
+	}
+
+	cb.transit(ctx, c, func(breaker *circuitBreaker) {
+		now := cb.clock.Now()
+		switch breaker.state {
+		case CircuitBreakerHalfOpen:
+			breaker.state = CircuitBreakerOpen
+			breaker.openedAt = now
+		case CircuitBreakerClosed:
+			failures := breaker.failures[:0]
+			for _, ts := range breaker.failures {
+				if now.Sub(ts) < cb.config.Window {
+					failures = append(failures, ts)
+				}
+			}
+			breaker.failures = append(failures, now)
+			if len(breaker.failures) >= cb.config.Threshold {
+				breaker.state = CircuitBreakerOpen
+				breaker.openedAt = now
+			}
+		}
+	})
+}
+
+// onCancel returns half-open circuit breaker to open state if probe call canceled (such as loser of hedged call),
+// so next call probes endpoint again
+func (cb *circuitBreakers) onCancel(ctx context.Context, c conn.Conn) {
+	if cb == nil {
+		return
+	}
+
+	cb.transit(ctx, c, func(breaker *circuitBreaker) {
+		if breaker.state == CircuitBreakerHalfOpen {
+			breaker.state = CircuitBreakerOpen
+		}
+	})
+}
+
+func (cb *circuitBreakers) transit(ctx context.Context, c conn.Conn, f func(breaker *circuitBreaker)) {
+	address := c.Endpoint().Address()
+
+	cb.mu.Lock()
+	breaker, has := cb.breakers[address]
+	if !has {
+		breaker = &circuitBreaker{}
+		cb.breakers[address] = breaker
+	}
+	from := breaker.state
+	f(breaker)
+	to, failures := breaker.state, len(breaker.failures)
+	cb.mu.Unlock()
+
+	if from != to {
+		trace.DriverOnBalancerCircuitBreakerStateChange(cb.trace, &ctx,
+			stack.FunctionID("github.com/ydb-platform/ydb-go-sdk/v3/internal/balancer.(*circuitBreakers).transit"),
+			c.Endpoint(), from.String(), to.String(), failures,
+		)
+	}
+}
+
+func (cb *circuitBreakers) states() map[string]CircuitBreakerState {
+	if cb == nil {
+		return nil
+	}
+
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	states := make(map[string]CircuitBreakerState, len(cb.breakers))
+	for address, breaker := range cb.breakers {
+		states[address] = breaker.state
+	}
+
+	return states
+}
@@ -0,0 +1,112 @@
+package balancer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"github.com/stretchr/testify/require"
+
+	balancerConfig "github.com/ydb-platform/ydb-go-sdk/v3/internal/balancer/config"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/conn"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/mock"
+	"github.com/ydb-platform/ydb-go-sdk/v3/trace"
+)
+
+func TestCircuitBreakers(t *testing.T) {
+	var (
+		ctx         = context.Background()
+		clock       = clockwork.NewFakeClock()
+		transitions []string
+		cb          = newCircuitBreakers(balancerConfig.CircuitBreaker{
+			Threshold: 3,
+			Window:    time.Minute,
+			Cooldown:  10 * time.Second,
+		}, clock, &trace.Driver{
+			OnBalancerCircuitBreakerStateChange: func(info trace.DriverBalancerCircuitBreakerStateChangeInfo) {
+				transitions = append(transitions, info.From+"->"+info.To)
+			},
+		})
+		c = &mock.Conn{AddrField: "1", State: conn.Online}
+	)
+
+	require.True(t, cb.available(c))
+
+	// failures out of window are not counted
+	cb.onFailure(ctx, c)
+	clock.Advance(2 * time.Minute)
+	cb.onFailure(ctx, c)
+	cb.onFailure(ctx, c)
+	require.True(t, cb.available(c))
+	require.Equal(t, CircuitBreakerClosed, cb.states()["1"])
+
+	cb.onFailure(ctx, c)
+	require.False(t, cb.available(c))
+	require.Equal(t, CircuitBreakerOpen, cb.states()["1"])
+
+	clock.Advance(10 * time.Second)
+	require.True(t, cb.available(c))
+	cb.onCall(ctx, c)
+	require.Equal(t, CircuitBreakerHalfOpen, cb.states()["1"])
+	require.False(t, cb.available(c), "single probe only")
+
+	cb.onFailure(ctx, c)
+	require.Equal(t, CircuitBreakerOpen, cb.states()["1"])
+	require.False(t, cb.available(c))
+
+	clock.Advance(10 * time.Second)
+	cb.onCall(ctx, c)
+	cb.onCancel(ctx, c)
+	require.Equal(t, CircuitBreakerOpen, cb.states()["1"])
+	require.True(t, cb.available(c), "canceled probe not restarts cooldown")
+
+	cb.onCall(ctx, c)
+	cb.onSuccess(ctx, c)
+	require.Equal(t, CircuitBreakerClosed, cb.states()["1"])
+	require.True(t, cb.available(c))
+
+	require.Equal(t, []string{
+		"closed->open",
+		"open->half-open",
+		"half-open->open",
+		"open->half-open",
+		"half-open->open",
+		"open->half-open",
+		"half-open->closed",
+	}, transitions)
+}
+
+func TestCircuitBreakersInConnectionsState(t *testing.T) {
+	var (
+		ctx   = context.Background()
+		clock = clockwork.NewFakeClock()
+		cb    = newCircuitBreakers(balancerConfig.CircuitBreaker{
+			Threshold: 1,
+			Window:    time.Minute,
+			Cooldown:  time.Minute,
+		}, clock, &trace.Driver{})
+		conns = []conn.Conn{
+			&mock.Conn{AddrField: "1", State: conn.Online, NodeIDField: 1},
+			&mock.Conn{AddrField: "2", State: conn.Online, NodeIDField: 2},
+		}
+	)
+	cb.onFailure(ctx, conns[0])
+
+	s := newConnectionsState(conns, nil, balancerConfig.Info{}, false, withCircuitBreakers(cb))
+	for i := 0; i < 100; i++ {
+		c, _ := s.GetConnection(ctx)
+		require.Equal(t, "2", c.Endpoint().Address())
+	}
+}
+
+func TestNilCircuitBreakers(t *testing.T) {
+	var cb *circuitBreakers
+	c := &mock.Conn{AddrField: "1", State: conn.Online}
+	require.True(t, cb.available(c))
+	cb.onCall(context.Background(), c)
+	cb.onFailure(context.Background(), c)
+	cb.onSuccess(context.Background(), c)
+	cb.onCancel(context.Background(), c)
+	require.Nil(t, cb.states())
+}
@@ -0,0 +1,35 @@
+package balancer
+
+import (
+	"time"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xsync"
+)
+
+// ClusterInfo describes cluster by result of last successful discovery.
+// Discovery protocol not reports name, identifier and version of cluster, so ClusterInfo
+// contains only attributes which client resolves on discovery
+type ClusterInfo struct {
+	// Endpoint is an address of discovery endpoint
+	Endpoint string
+
+	// Database is a path of database which endpoints discovered
+	Database string
+
+	// LocalDC is a detected local DC. LocalDC is empty if detection of nearest DC disabled
+	LocalDC string
+
+	// Endpoints is a count of discovered endpoints
+	Endpoints int
+
+	// Discovered is a time of last successful discovery
+	Discovered time.Time
+}
+
+// ClusterInfo returns info of cluster from last successful discovery.
+// ClusterInfo is zero if no one discovery completed
+func (b *Balancer) ClusterInfo() ClusterInfo {
+	return xsync.WithRLock(&b.mu, func() ClusterInfo {
+		return b.clusterInfo
+	})
+}
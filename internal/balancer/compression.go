@@ -0,0 +1,27 @@
+package balancer
+
+import (
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+// withCompression appends compressor call option for request message with size not less than minMessageSize.
+// Compressor from call options takes precedence over compressor of driver config
+func withCompression(algorithm string, minMessageSize int, args interface{}, opts []grpc.CallOption) []grpc.CallOption {
+	if algorithm == "" {
+		return opts
+	}
+
+	for _, opt := range opts {
+		if _, has := opt.(grpc.CompressorCallOption); has {
+			return opts
+		}
+	}
+
+	msg, ok := args.(proto.Message)
+	if !ok || proto.Size(msg) < minMessageSize {
+		return opts
+	}
+
+	return append(opts[:len(opts):len(opts)], grpc.UseCompressor(algorithm))
+}
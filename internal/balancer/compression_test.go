@@ -0,0 +1,67 @@
+package balancer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestWithCompression(t *testing.T) {
+	var (
+		small = wrapperspb.Bytes(make([]byte, 10))
+		large = wrapperspb.Bytes(make([]byte, 1024))
+	)
+	require.Less(t, proto.Size(small), 100)
+	require.GreaterOrEqual(t, proto.Size(large), 100)
+
+	for _, tt := range []struct {
+		name      string
+		algorithm string
+		args      interface{}
+		opts      []grpc.CallOption
+		expected  []grpc.CallOption
+	}{
+		{
+			name:      "Disabled",
+			algorithm: "",
+			args:      large,
+		},
+		{
+			name:      "BelowThreshold",
+			algorithm: "gzip",
+			args:      small,
+		},
+		{
+			name:      "AboveThreshold",
+			algorithm: "gzip",
+			args:      large,
+			expected:  []grpc.CallOption{grpc.UseCompressor("gzip")},
+		},
+		{
+			name:      "NotProtoMessage",
+			algorithm: "gzip",
+			args:      make([]byte, 1024),
+		},
+		{
+			name:      "PerCallCompressor",
+			algorithm: "gzip",
+			args:      large,
+			opts:      []grpc.CallOption{grpc.UseCompressor("identity")},
+			expected:  []grpc.CallOption{grpc.UseCompressor("identity")},
+		},
+		{
+			name:      "OtherCallOptions",
+			algorithm: "gzip",
+			args:      large,
+			opts:      []grpc.CallOption{grpc.WaitForReady(true)},
+			expected:  []grpc.CallOption{grpc.WaitForReady(true), grpc.UseCompressor("gzip")},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, withCompression(tt.algorithm, 100, tt.args, tt.opts))
+		})
+	}
+}
@@ -1,8 +1,16 @@
 package config
 
 import (
+	"context"
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/jonboulle/clockwork"
+	"google.golang.org/grpc"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/conn"
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/endpoint"
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xstring"
 )
@@ -12,8 +20,616 @@ import (
 type Config struct {
 	Filter          Filter
 	AllowFallback   bool
+	StrictLocalDC   bool
+	DCPriority      []string
+	AddressFamily   AddressFamily
 	SingleConn      bool
 	DetectNearestDC bool
+
+	ConnectionPicker ConnectionPicker
+	LoadWeighting    bool
+	SelectionMode    SelectionMode
+	EndpointFilter   func(e endpoint.Info) bool
+	// EndpointComparator defines total order of endpoints for deterministic choice of connection
+	EndpointComparator func(a, b endpoint.Info) int
+	// NewEndpointBias defines order of connections of new and previously discovered endpoints
+	// for NewEndpointBiasWindow after discovery
+	NewEndpointBias       NewEndpointBias
+	NewEndpointBiasWindow time.Duration
+
+	LocalDCDetectionMode LocalDCDetectionMode
+	LatencyProbeCount    int
+	LatencyProbeTimeout  time.Duration
+	LocalDCDetectTimeout time.Duration
+	// LocalDCProbeConcurrency is a maximum count of simultaneous probes of endpoints on local DC detection
+	LocalDCProbeConcurrency int
+	// LocalDCProbeEndpointsPerDC is a count of sampled endpoints of every DC for probes on local DC detection
+	LocalDCProbeEndpointsPerDC int
+	// ForcedLocalDC is a local DC which balancer uses instead of detection of nearest DC
+	ForcedLocalDC string
+	// Locality returns locality path of endpoint from DC to rack (such as [dc, rack])
+	Locality func(e endpoint.Info) []string
+
+	DiscoveryCache DiscoveryCache
+	// InitialState is a snapshot of balancer state (exported from other process) for seed of balancer state
+	InitialState []byte
+
+	// DiscoveryClient is a factory of discovery client. Balancer dials discovery service if DiscoveryClient is nil
+	DiscoveryClient func(ctx context.Context) (DiscoveryClient, error)
+	// DiscoveryConn is an established connection for discovery. Balancer never closes DiscoveryConn
+	DiscoveryConn grpc.ClientConnInterface
+
+	CircuitBreaker *CircuitBreaker
+
+	DrainTimeout time.Duration
+	IdleTimeout  time.Duration
+
+	// MaxStreamsPerConn is a maximum count of open streams through connection. Zero value means no limit
+	MaxStreamsPerConn int
+
+	BanRecoveryProbe *BanRecoveryProbe
+
+	ReadReplicaFilter func(e endpoint.Info) bool
+
+	ConnectionWarmup bool
+	ReadinessCheck   bool
+
+	AddressRewriter func(e endpoint.Endpoint) endpoint.Endpoint
+	// DialGuard checks endpoint before every dial of connection, non-nil error refuses dial
+	DialGuard func(ctx context.Context, e endpoint.Endpoint) error
+
+	// MethodPolicies contains routing policies by full gRPC method name or by gRPC service name
+	MethodPolicies map[string]Policy
+
+	MaxConnections          int
+	RemoteDCConnectionLimit int
+
+	// MinEndpointsFraction is a minimum fraction of previously discovered endpoints in discovery result.
+	// Smaller discovery result treats as partial and skips (up to MaxPartialDiscoveries consecutive results)
+	MinEndpointsFraction float64
+	// KeepStateOnEmptyDiscovery skips empty discovery result (up to MaxEmptyDiscoveries consecutive results)
+	KeepStateOnEmptyDiscovery bool
+
+	// StartupFallbackSingleConn enables start of balancer with single connection on failed initial discovery
+	StartupFallbackSingleConn bool
+
+	// DiscoveryHistory is a count of last applied discovery results which balancer keeps in memory
+	DiscoveryHistory int
+
+	// Clock is a source of time for balancer and discovery repeater. Real clock uses if Clock is nil
+	Clock clockwork.Clock
+}
+
+// CircuitBreaker defines per-endpoint circuit breaker parameters
+type CircuitBreaker struct {
+	// Threshold is a count of failures within Window which opens circuit breaker
+	Threshold int
+	// Window is a sliding time window for counting failures
+	Window time.Duration
+	// Cooldown is a duration of refusing calls to endpoint before single probe call allowed
+	Cooldown time.Duration
+}
+
+// BanRecoveryProbe defines intervals of probes of banned connections
+type BanRecoveryProbe struct {
+	// MinInterval is an interval before first probe
+	MinInterval time.Duration
+	// MaxInterval is a maximum interval between probes, intervals grows exponentially from MinInterval
+	MaxInterval time.Duration
+}
+
+// DiscoveryCache persists last successful discovery result for fast cold start
+type DiscoveryCache interface {
+	Load() ([]endpoint.Endpoint, error)
+	Save(endpoints []endpoint.Endpoint) error
+}
+
+// DiscoveryClient lists endpoints of cluster
+type DiscoveryClient interface {
+	Discover(ctx context.Context) ([]endpoint.Endpoint, error)
+	Close(ctx context.Context) error
+}
+
+// MaxPartialDiscoveries is a maximum count of consecutive partial discovery results which balancer skips
+// with MinEndpointsFraction. Next partial discovery result applies as is
+const MaxPartialDiscoveries = 2
+
+// MaxEmptyDiscoveries is a maximum count of consecutive empty discovery results which balancer skips
+// with KeepStateOnEmptyDiscovery. Next empty discovery result applies as is
+const MaxEmptyDiscoveries = 3
+
+// LocalDCDetectionMode defines algorithm of detection nearest DC
+type LocalDCDetectionMode int
+
+const (
+	// FastestDial detects nearest DC as location of first endpoint which accepts tcp connection
+	FastestDial = LocalDCDetectionMode(iota)
+
+	// LatencyProbe detects nearest DC as location with lowest median round-trip time of probes.
+	// Probe is a raw tcp connect to endpoint (not a gRPC call), so probe not uses dial options of driver
+	// (such as TLS or proxy dialer) and not measures TLS handshake
+	LatencyProbe
+)
+
+func (m LocalDCDetectionMode) String() string {
+	switch m {
+	case FastestDial:
+		return "FastestDial"
+	case LatencyProbe:
+		return "LatencyProbe"
+	default:
+		return fmt.Sprintf("Unknown(%d)", int(m))
+	}
+}
+
+// AddressFamily defines preferred address family of endpoints
+type AddressFamily int
+
+const (
+	// DualStack uses endpoints of all address families equally
+	DualStack = AddressFamily(iota)
+
+	// IPv4 prefers endpoints with IPv4 addresses
+	IPv4
+
+	// IPv6 prefers endpoints with IPv6 addresses
+	IPv6
+)
+
+func (f AddressFamily) String() string {
+	switch f {
+	case DualStack:
+		return "DualStack"
+	case IPv4:
+		return "IPv4"
+	case IPv6:
+		return "IPv6"
+	default:
+		return fmt.Sprintf("Unknown(%d)", int(f))
+	}
+}
+
+// SelectionMode defines algorithm of choice of connection among connections of same priority
+type SelectionMode int
+
+const (
+	// Random chooses random connection
+	Random = SelectionMode(iota)
+
+	// RoundRobin rotates through connections deterministically
+	RoundRobin
+)
+
+func (m SelectionMode) String() string {
+	switch m {
+	case Random:
+		return "Random"
+	case RoundRobin:
+		return "RoundRobin"
+	default:
+		return fmt.Sprintf("Unknown(%d)", int(m))
+	}
+}
+
+// DefaultNewEndpointBiasWindow is a default duration of bias of connections after discovery of new endpoints
+const DefaultNewEndpointBiasWindow = 30 * time.Second
+
+// NewEndpointBias defines order of connections of newly discovered endpoints (cold connections which not dialed yet)
+// and connections of previously discovered endpoints (warm connections) for a short window after discovery
+type NewEndpointBias int
+
+const (
+	// NoNewEndpointBias chooses connections of new and previously discovered endpoints equally
+	NoNewEndpointBias = NewEndpointBias(iota)
+
+	// WarmFirst prefers connections of previously discovered endpoints for avoid latency of first calls to new nodes
+	WarmFirst
+
+	// ColdFirst prefers connections of newly discovered endpoints for fast balance of load onto new nodes
+	ColdFirst
+)
+
+func (b NewEndpointBias) String() string {
+	switch b {
+	case NoNewEndpointBias:
+		return "None"
+	case WarmFirst:
+		return "WarmFirst"
+	case ColdFirst:
+		return "ColdFirst"
+	default:
+		return fmt.Sprintf("Unknown(%d)", int(b))
+	}
+}
+
+// ConnectionPicker selects connection for next call from current balancer connections
+//
+// Returned nil means that picker cannot choose connection and balancer must use default algorithm
+type ConnectionPicker func(ctx context.Context, conns []conn.Conn) conn.Conn
+
+// Policy defines routing of calls of gRPC method with routing hints in context of call
+// (such as affinity key or preference of read replicas)
+type Policy func(ctx context.Context, method string) context.Context
+
+// RandomPolicy routes calls with default choice of balancer
+func RandomPolicy() Policy {
+	return func(ctx context.Context, method string) context.Context {
+		return ctx
+	}
+}
+
+// StickyPolicy routes all calls of method to same connection while connection alive.
+// Affinity key from context of call has priority over sticky policy
+func StickyPolicy() Policy {
+	return func(ctx context.Context, method string) context.Context {
+		if _, has := conn.EndpointAffinity(ctx); has {
+			return ctx
+		}
+
+		return conn.WithEndpointAffinity(ctx, method)
+	}
+}
+
+// MethodPolicy returns routing policy for full gRPC method name (such as /Ydb.Table.V1.TableService/ExecuteDataQuery).
+// Policy for exact method name has priority over policy for gRPC service name (such as /Ydb.Table.V1.TableService)
+func (c *Config) MethodPolicy(method string) (policy Policy, has bool) {
+	if len(c.MethodPolicies) == 0 {
+		return nil, false
+	}
+
+	if policy, has = c.MethodPolicies[method]; has {
+		return policy, true
+	}
+
+	if i := strings.LastIndexByte(method, '/'); i > 0 {
+		policy, has = c.MethodPolicies[method[:i]]
+	}
+
+	return policy, has
+}
+
+type Option func(c *Config)
+
+// WithConnectionPicker defines custom algorithm for choose connection
+func WithConnectionPicker(picker ConnectionPicker) Option {
+	return func(c *Config) {
+		c.ConnectionPicker = picker
+	}
+}
+
+// WithSelectionMode defines algorithm of choice of connection among connections of same priority.
+// Load weighting overrides selection mode
+func WithSelectionMode(mode SelectionMode) Option {
+	return func(c *Config) {
+		c.SelectionMode = mode
+	}
+}
+
+// WithLoadWeighting enables weighting of random choice of connection by endpoint load factor
+func WithLoadWeighting(loadWeighting bool) Option {
+	return func(c *Config) {
+		c.LoadWeighting = loadWeighting
+	}
+}
+
+// WithEndpointComparator defines total order of endpoints. Balancer chooses connections among connections
+// of same priority by deterministic round-robin over sorted connections. With load weighting comparator
+// orders connections only within groups of connections with equal load weight
+func WithEndpointComparator(cmp func(a, b endpoint.Info) int) Option {
+	return func(c *Config) {
+		c.EndpointComparator = cmp
+	}
+}
+
+// WithNewEndpointBias defines order of connections of newly discovered and previously discovered endpoints
+// for a short window after discovery which adds new endpoints. Bias orders connections only within groups
+// of connections of same priority (such as local DC)
+func WithNewEndpointBias(bias NewEndpointBias) Option {
+	return func(c *Config) {
+		c.NewEndpointBias = bias
+	}
+}
+
+// WithNewEndpointBiasWindow defines duration of bias of connections after discovery of new endpoints.
+// Non-positive window means DefaultNewEndpointBiasWindow
+func WithNewEndpointBiasWindow(window time.Duration) Option {
+	return func(c *Config) {
+		c.NewEndpointBiasWindow = window
+	}
+}
+
+// WithEndpointFilter excludes endpoints from balancing on each discovery if filter returns false
+func WithEndpointFilter(filter func(e endpoint.Info) bool) Option {
+	return func(c *Config) {
+		c.EndpointFilter = filter
+	}
+}
+
+// WithLocalDCDetectionMode defines algorithm of detection nearest DC
+func WithLocalDCDetectionMode(mode LocalDCDetectionMode) Option {
+	return func(c *Config) {
+		c.LocalDCDetectionMode = mode
+	}
+}
+
+// WithLatencyProbeCount defines count of probes for each sampled endpoint in LatencyProbe mode
+func WithLatencyProbeCount(count int) Option {
+	return func(c *Config) {
+		c.LatencyProbeCount = count
+	}
+}
+
+// WithLocalDCProbeConcurrency defines maximum count of simultaneous probes of endpoints on local DC detection
+// (such as for avoid of spike of connections in large clusters). Zero value means no limit
+func WithLocalDCProbeConcurrency(concurrency int) Option {
+	return func(c *Config) {
+		c.LocalDCProbeConcurrency = concurrency
+	}
+}
+
+// WithLocalDCProbeEndpointsPerDC defines count of random endpoints of every DC for probes on local DC detection.
+// Zero value means default count of sampled endpoints
+func WithLocalDCProbeEndpointsPerDC(count int) Option {
+	return func(c *Config) {
+		c.LocalDCProbeEndpointsPerDC = count
+	}
+}
+
+// WithLocalDCDetectTimeout defines timeout of local DC detection separately from dial timeout of discovery.
+// If local DC detection with defined timeout timed out - balancer uses discovered endpoints without local DC
+func WithLocalDCDetectTimeout(timeout time.Duration) Option {
+	return func(c *Config) {
+		c.LocalDCDetectTimeout = timeout
+	}
+}
+
+// WithStrictLocalDC allows only connections in local DC (such as for data residency).
+// Strict local DC takes precedence over AllowFallback and DCPriority: balancer never uses connections outside
+// local DC and returns error on exhaustion of local DC connections. If local DC not detected (or not forced) -
+// balancer has no one usable connection
+func WithStrictLocalDC(strict bool) Option {
+	return func(c *Config) {
+		c.StrictLocalDC = strict
+	}
+}
+
+// WithForcedLocalDC defines local DC of client instead of detection of nearest DC (such as DC from environment).
+// Balancer uses forced local DC without probes of endpoints
+func WithForcedLocalDC(name string) Option {
+	return func(c *Config) {
+		c.ForcedLocalDC = name
+	}
+}
+
+// WithLocality defines locality path of endpoint from DC to rack (such as parsed from location of endpoint).
+// Balancer detects locality of client with local DC detection and prefers endpoints with deepest
+// matched locality (such as same rack)
+func WithLocality(locality func(e endpoint.Info) []string) Option {
+	return func(c *Config) {
+		c.Locality = locality
+	}
+}
+
+// WithLatencyProbeTimeout defines timeout of single probe (tcp connect) in LatencyProbe mode
+func WithLatencyProbeTimeout(timeout time.Duration) Option {
+	return func(c *Config) {
+		c.LatencyProbeTimeout = timeout
+	}
+}
+
+// WithDiscoveryCache defines storage of last successful discovery result
+func WithDiscoveryCache(cache DiscoveryCache) Option {
+	return func(c *Config) {
+		c.DiscoveryCache = cache
+	}
+}
+
+// WithInitialState defines snapshot of balancer state (such as exported by balancer of other process) for seed
+// of balancer state without initial discovery. Incompatible snapshot ignores and balancer makes initial discovery
+func WithInitialState(state []byte) Option {
+	return func(c *Config) {
+		c.InitialState = state
+	}
+}
+
+// WithCircuitBreaker enables per-endpoint circuit breaker which refuses endpoint for cooldown
+// after threshold failures within window
+func WithCircuitBreaker(threshold int, window, cooldown time.Duration) Option {
+	return func(c *Config) {
+		c.CircuitBreaker = &CircuitBreaker{
+			Threshold: threshold,
+			Window:    window,
+			Cooldown:  cooldown,
+		}
+	}
+}
+
+// WithDrainTimeout defines grace period for connections to endpoints removed from discovery response.
+// After grace period connections closes by pool. Zero timeout disables draining
+func WithDrainTimeout(timeout time.Duration) Option {
+	return func(c *Config) {
+		c.DrainTimeout = timeout
+	}
+}
+
+// WithIdleTimeout defines duration after which connections pool parks connections not chosen by balancer
+// and not used for calls (see config.WithConnectionTTL). Parked connections stay in balancer and lazily
+// re-dials on next selection. Zero timeout disables idle eviction
+func WithIdleTimeout(timeout time.Duration) Option {
+	return func(c *Config) {
+		c.IdleTimeout = timeout
+	}
+}
+
+// WithMaxStreamsPerConn defines maximum count of open streams through connection.
+// Balancer opens new stream through less loaded connection if chosen connection reached maximum
+func WithMaxStreamsPerConn(n int) Option {
+	return func(c *Config) {
+		c.MaxStreamsPerConn = n
+	}
+}
+
+// WithConnectionWarmup enables dial of new connections in background after discovery
+func WithConnectionWarmup(warmup bool) Option {
+	return func(c *Config) {
+		c.ConnectionWarmup = warmup
+	}
+}
+
+// WithReadinessCheck enables check of new connections with lightweight request before use of connection for calls
+func WithReadinessCheck(check bool) Option {
+	return func(c *Config) {
+		c.ReadinessCheck = check
+	}
+}
+
+// WithAddressRewriter defines rewrite of discovered endpoint address before dial (such as for NAT or proxy).
+// Balancer uses only address of rewritten endpoint, node ID and location of endpoint preserves
+func WithAddressRewriter(rewriter func(e endpoint.Endpoint) endpoint.Endpoint) Option {
+	return func(c *Config) {
+		c.AddressRewriter = rewriter
+	}
+}
+
+// WithDialGuard defines check of endpoint before every dial of connection (such as check of endpoint with
+// dynamic allowlist). Non-nil error of guard refuses dial and bans connection until next discovery
+func WithDialGuard(guard func(ctx context.Context, e endpoint.Endpoint) error) Option {
+	return func(c *Config) {
+		c.DialGuard = guard
+	}
+}
+
+// WithMaxConnections defines maximum count of endpoints with materialized connections.
+// Preferred endpoints (such as endpoints in local DC) selects first. Selection rotates across discovery cycles.
+// Zero value means no limit
+func WithMaxConnections(maxConnections int) Option {
+	return func(c *Config) {
+		c.MaxConnections = maxConnections
+	}
+}
+
+// WithRemoteDCConnectionLimit defines maximum count of endpoints outside local DC with materialized connections.
+// Endpoints in local DC not limited. Selection rotates across discovery cycles. Zero value means no limit
+func WithRemoteDCConnectionLimit(limit int) Option {
+	return func(c *Config) {
+		c.RemoteDCConnectionLimit = limit
+	}
+}
+
+// WithMinEndpointsFraction defines minimum fraction of previously discovered endpoints in discovery result
+// (such as 0.5 for half of endpoints). Smaller discovery result (such as on partition of control plane)
+// treats as partial, so balancer keeps previous endpoints. MaxPartialDiscoveries consecutive partial
+// results applies as is. Zero value disables check
+func WithMinEndpointsFraction(fraction float64) Option {
+	return func(c *Config) {
+		c.MinEndpointsFraction = fraction
+	}
+}
+
+// WithKeepStateOnEmptyDiscovery defines skip of empty discovery result (such as on brief reconfiguration
+// of cluster), so balancer keeps previous endpoints. MaxEmptyDiscoveries consecutive empty results
+// applies as is
+func WithKeepStateOnEmptyDiscovery(keep bool) Option {
+	return func(c *Config) {
+		c.KeepStateOnEmptyDiscovery = keep
+	}
+}
+
+// WithDCPriority defines order of DCs for choose of fallback connections (such as by geographical distance).
+// DCs not listed in priority consults last
+func WithDCPriority(dcPriority []string) Option {
+	return func(c *Config) {
+		c.DCPriority = dcPriority
+	}
+}
+
+// WithAddressFamilyPreference defines preferred address family of endpoints.
+// Endpoints of other address family uses if no one endpoint of preferred address family available
+func WithAddressFamilyPreference(family AddressFamily) Option {
+	return func(c *Config) {
+		c.AddressFamily = family
+	}
+}
+
+// WithMethodPolicy defines routing policy for calls of gRPC method. Method is a full gRPC method name
+// (such as /Ydb.Coordination.V1.CoordinationService/Session) or gRPC service name
+// (such as /Ydb.Coordination.V1.CoordinationService). Calls of other methods routes with default choice of balancer
+func WithMethodPolicy(method string, policy Policy) Option {
+	return func(c *Config) {
+		if c.MethodPolicies == nil {
+			c.MethodPolicies = make(map[string]Policy)
+		}
+		c.MethodPolicies[method] = policy
+	}
+}
+
+// WithStartupFallbackSingleConn enables start of balancer with single connection to initial endpoint
+// if initial discovery failed. Background discovering replaces single connection with discovered endpoints.
+// Option works only with background discovering (discovery interval greater than zero)
+func WithStartupFallbackSingleConn(fallback bool) Option {
+	return func(c *Config) {
+		c.StartupFallbackSingleConn = fallback
+	}
+}
+
+// WithBanRecoveryProbe enables probes of banned connections with exponentially growing intervals
+// from minInterval to maxInterval. Connection allows after first successful probe without waiting of discovery
+func WithBanRecoveryProbe(minInterval, maxInterval time.Duration) Option {
+	return func(c *Config) {
+		c.BanRecoveryProbe = &BanRecoveryProbe{
+			MinInterval: minInterval,
+			MaxInterval: maxInterval,
+		}
+	}
+}
+
+// WithDiscoveryHistory defines count of last applied discovery results which balancer keeps in memory
+// for debugging. Zero value disables discovery history
+func WithDiscoveryHistory(size int) Option {
+	return func(c *Config) {
+		c.DiscoveryHistory = size
+	}
+}
+
+// WithDiscoveryClient defines factory of discovery client instead of discovery service client
+// (such as discovery client with synthetic topology in tests)
+func WithDiscoveryClient(discoveryClient func(ctx context.Context) (DiscoveryClient, error)) Option {
+	return func(c *Config) {
+		c.DiscoveryClient = discoveryClient
+	}
+}
+
+// WithDiscoveryConn defines established connection (such as *grpc.ClientConn shared with other client)
+// for discovery instead of dial of discovery endpoint. Balancer never closes connection
+func WithDiscoveryConn(cc grpc.ClientConnInterface) Option {
+	return func(c *Config) {
+		c.DiscoveryConn = cc
+	}
+}
+
+// WithClock defines source of time for balancer and discovery repeater (such as fake clock in tests)
+func WithClock(clock clockwork.Clock) Option {
+	return func(c *Config) {
+		c.Clock = clock
+	}
+}
+
+// WithReadReplicaFilter defines which endpoints are read replicas for calls which prefer read replicas
+func WithReadReplicaFilter(filter func(e endpoint.Info) bool) Option {
+	return func(c *Config) {
+		c.ReadReplicaFilter = filter
+	}
+}
+
+// With applies options to balancer config
+func (c *Config) With(opts ...Option) *Config {
+	for _, opt := range opts {
+		if opt != nil {
+			opt(c)
+		}
+	}
+
+	return c
 }
 
 func (c Config) String() string {
@@ -29,14 +645,163 @@ func (c Config) String() string {
 	buffer.WriteString("DetectNearestDC=")
 	fmt.Fprintf(buffer, "%t", c.DetectNearestDC)
 
+	if c.LocalDCDetectionMode != FastestDial {
+		buffer.WriteString(",LocalDCDetectionMode=")
+		buffer.WriteString(c.LocalDCDetectionMode.String())
+	}
+
+	if c.LocalDCDetectTimeout > 0 {
+		fmt.Fprintf(buffer, ",LocalDCDetectTimeout=%v", c.LocalDCDetectTimeout)
+	}
+
+	if c.LocalDCProbeConcurrency > 0 {
+		fmt.Fprintf(buffer, ",LocalDCProbeConcurrency=%d", c.LocalDCProbeConcurrency)
+	}
+
+	if c.LocalDCProbeEndpointsPerDC > 0 {
+		fmt.Fprintf(buffer, ",LocalDCProbeEndpointsPerDC=%d", c.LocalDCProbeEndpointsPerDC)
+	}
+
+	if c.ForcedLocalDC != "" {
+		buffer.WriteString(",ForcedLocalDC=")
+		buffer.WriteString(c.ForcedLocalDC)
+	}
+
+	if c.Locality != nil {
+		buffer.WriteString(",Locality=true")
+	}
+
 	buffer.WriteString(",AllowFallback=")
 	fmt.Fprintf(buffer, "%t", c.AllowFallback)
 
+	if c.StrictLocalDC {
+		buffer.WriteString(",StrictLocalDC")
+	}
+
+	if c.AddressFamily != DualStack {
+		buffer.WriteString(",AddressFamily=")
+		buffer.WriteString(c.AddressFamily.String())
+	}
+
+	if len(c.DCPriority) > 0 {
+		fmt.Fprintf(buffer, ",DCPriority=%v", c.DCPriority)
+	}
+
 	if c.Filter != nil {
 		buffer.WriteString(",Filter=")
 		fmt.Fprint(buffer, c.Filter.String())
 	}
 
+	if c.LoadWeighting {
+		buffer.WriteString(",LoadWeighting=true")
+	}
+
+	if c.SelectionMode != Random {
+		buffer.WriteString(",SelectionMode=")
+		buffer.WriteString(c.SelectionMode.String())
+	}
+
+	if c.NewEndpointBias != NoNewEndpointBias {
+		buffer.WriteString(",NewEndpointBias=")
+		buffer.WriteString(c.NewEndpointBias.String())
+		if c.NewEndpointBiasWindow > 0 {
+			fmt.Fprintf(buffer, ",NewEndpointBiasWindow=%v", c.NewEndpointBiasWindow)
+		}
+	}
+
+	if c.CircuitBreaker != nil {
+		fmt.Fprintf(buffer, ",CircuitBreaker={Threshold=%d,Window=%v,Cooldown=%v}",
+			c.CircuitBreaker.Threshold, c.CircuitBreaker.Window, c.CircuitBreaker.Cooldown,
+		)
+	}
+
+	if c.DrainTimeout > 0 {
+		fmt.Fprintf(buffer, ",DrainTimeout=%v", c.DrainTimeout)
+	}
+
+	if c.IdleTimeout > 0 {
+		fmt.Fprintf(buffer, ",IdleTimeout=%v", c.IdleTimeout)
+	}
+
+	if c.MaxStreamsPerConn > 0 {
+		fmt.Fprintf(buffer, ",MaxStreamsPerConn=%d", c.MaxStreamsPerConn)
+	}
+
+	if c.BanRecoveryProbe != nil {
+		fmt.Fprintf(buffer, ",BanRecoveryProbe={MinInterval=%v,MaxInterval=%v}",
+			c.BanRecoveryProbe.MinInterval, c.BanRecoveryProbe.MaxInterval,
+		)
+	}
+
+	if c.ConnectionWarmup {
+		buffer.WriteString(",ConnectionWarmup")
+	}
+
+	if c.ReadinessCheck {
+		buffer.WriteString(",ReadinessCheck")
+	}
+
+	if c.MaxConnections > 0 {
+		fmt.Fprintf(buffer, ",MaxConnections=%d", c.MaxConnections)
+	}
+
+	if c.RemoteDCConnectionLimit > 0 {
+		fmt.Fprintf(buffer, ",RemoteDCConnectionLimit=%d", c.RemoteDCConnectionLimit)
+	}
+
+	if c.MinEndpointsFraction > 0 {
+		fmt.Fprintf(buffer, ",MinEndpointsFraction=%v", c.MinEndpointsFraction)
+	}
+
+	if c.KeepStateOnEmptyDiscovery {
+		buffer.WriteString(",KeepStateOnEmptyDiscovery")
+	}
+
+	if c.StartupFallbackSingleConn {
+		buffer.WriteString(",StartupFallbackSingleConn")
+	}
+
+	if len(c.InitialState) > 0 {
+		buffer.WriteString(",InitialState")
+	}
+
+	if c.DiscoveryHistory > 0 {
+		fmt.Fprintf(buffer, ",DiscoveryHistory=%d", c.DiscoveryHistory)
+	}
+
+	if c.AddressRewriter != nil {
+		buffer.WriteString(",AddressRewriter=Custom")
+	}
+
+	if c.DialGuard != nil {
+		buffer.WriteString(",DialGuard=Custom")
+	}
+
+	if len(c.MethodPolicies) > 0 {
+		methods := make([]string, 0, len(c.MethodPolicies))
+		for method := range c.MethodPolicies {
+			methods = append(methods, method)
+		}
+		sort.Strings(methods)
+		fmt.Fprintf(buffer, ",MethodPolicies=%v", methods)
+	}
+
+	if c.ReadReplicaFilter != nil {
+		buffer.WriteString(",ReadReplicaFilter=Custom")
+	}
+
+	if c.EndpointFilter != nil {
+		buffer.WriteString(",EndpointFilter=Custom")
+	}
+
+	if c.ConnectionPicker != nil {
+		buffer.WriteString(",ConnectionPicker=Custom")
+	}
+
+	if c.EndpointComparator != nil {
+		buffer.WriteString(",EndpointComparator=Custom")
+	}
+
 	buffer.WriteByte('}')
 
 	return buffer.String()
@@ -44,6 +809,8 @@ func (c Config) String() string {
 
 type Info struct {
 	SelfLocation string
+	// SelfLocality is a locality path of client from DC to rack (nil if locality not defined)
+	SelfLocality []string
 }
 
 type Filter interface {
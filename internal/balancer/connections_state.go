@@ -2,44 +2,328 @@ package balancer
 
 import (
 	"context"
+	"hash/fnv"
+	"net"
+	"sync/atomic"
+	"time"
+
+	"github.com/jonboulle/clockwork"
 
 	balancerConfig "github.com/ydb-platform/ydb-go-sdk/v3/internal/balancer/config"
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/conn"
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/endpoint"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/stack"
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xrand"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xslices"
+	"github.com/ydb-platform/ydb-go-sdk/v3/trace"
 )
 
+// ctxCheckPeriod is a count of checked connections between checks of context in selection loops
+const ctxCheckPeriod = 64
+
 type connectionsState struct {
 	connByNodeID map[uint32]conn.Conn
 
+	// endpoints contains discovered endpoints for compare with next discovery result
+	endpoints []endpoint.Endpoint
+	// discovered contains endpoints of discovery result before rewrite and filter of endpoints for export of state
+	discovered []endpoint.Endpoint
+
 	prefer   []conn.Conn
 	fallback []conn.Conn
 	all      []conn.Conn
 
+	// prefers contains preferred connections grouped by address family (nil if address family not defined)
+	prefers [][]conn.Conn
+	// fallbacks contains fallback connections grouped by priority of DC and address family
+	// (nil if DC priority and address family not defined)
+	fallbacks     [][]conn.Conn
+	dcPriority    []string
+	addressFamily balancerConfig.AddressFamily
+
+	// locality returns locality path of endpoint from DC to rack, selfLocality is a locality path of client
+	locality     func(e endpoint.Info) []string
+	selfLocality []string
+
+	// replicas contains read replica connections for calls which prefer read replicas
+	replicas          []conn.Conn
+	readReplicaFilter func(e endpoint.Info) bool
+
+	picker        balancerConfig.ConnectionPicker
+	loadWeighting bool
+	selectionMode balancerConfig.SelectionMode
+	// comparator defines total order of connections for deterministic choice of connection
+	comparator func(a, b endpoint.Info) int
+	// rotation is a counter of choices of connection in RoundRobin selection mode
+	rotation atomic.Uint64
+
+	// addedAt contains times of entry of connections into state by address. Times carries over from previous
+	// state, so connections of newly discovered endpoints have time of build of state
+	addedAt map[string]time.Time
+	// biasedPrefers and biasedFallbacks contains groups of connections split into warm and cold connections
+	// by new endpoint bias, which uses instead of prefers and fallbacks until biasUntil
+	newEndpointBias balancerConfig.NewEndpointBias
+	biasWindow      time.Duration
+	biasedPrefers   [][]conn.Conn
+	biasedFallbacks [][]conn.Conn
+	biasUntil       time.Time
+	clock           clockwork.Clock
+
+	circuitBreakers *circuitBreakers
+	readiness       *readinessGate
+
+	// inFallback shared between states for tracing transitions between preferred and fallback connections
+	inFallback *atomic.Bool
+	trace      *trace.Driver
+	localDC    string
+
 	rand xrand.Rand
 }
 
+type connectionsStateOption func(s *connectionsState)
+
+func withConnectionPicker(picker balancerConfig.ConnectionPicker) connectionsStateOption {
+	return func(s *connectionsState) {
+		s.picker = picker
+	}
+}
+
+func withLoadWeighting(loadWeighting bool) connectionsStateOption {
+	return func(s *connectionsState) {
+		s.loadWeighting = loadWeighting
+	}
+}
+
+func withSelectionMode(mode balancerConfig.SelectionMode) connectionsStateOption {
+	return func(s *connectionsState) {
+		s.selectionMode = mode
+	}
+}
+
+func withEndpointComparator(cmp func(a, b endpoint.Info) int) connectionsStateOption {
+	return func(s *connectionsState) {
+		s.comparator = cmp
+	}
+}
+
+// withEntryTimes carries over times of entry of connections from previous state
+func withEntryTimes(previous map[string]time.Time, clock clockwork.Clock) connectionsStateOption {
+	return func(s *connectionsState) {
+		s.addedAt = previous
+		s.clock = clock
+	}
+}
+
+func withNewEndpointBias(bias balancerConfig.NewEndpointBias, window time.Duration) connectionsStateOption {
+	return func(s *connectionsState) {
+		if window <= 0 {
+			window = balancerConfig.DefaultNewEndpointBiasWindow
+		}
+		s.newEndpointBias = bias
+		s.biasWindow = window
+	}
+}
+
+func withCircuitBreakers(cb *circuitBreakers) connectionsStateOption {
+	return func(s *connectionsState) {
+		s.circuitBreakers = cb
+	}
+}
+
+func withReadinessGate(g *readinessGate) connectionsStateOption {
+	return func(s *connectionsState) {
+		s.readiness = g
+	}
+}
+
+func withReadReplicaFilter(filter func(e endpoint.Info) bool) connectionsStateOption {
+	return func(s *connectionsState) {
+		s.readReplicaFilter = filter
+	}
+}
+
+func withDCPriority(dcPriority []string) connectionsStateOption {
+	return func(s *connectionsState) {
+		s.dcPriority = dcPriority
+	}
+}
+
+func withAddressFamily(family balancerConfig.AddressFamily) connectionsStateOption {
+	return func(s *connectionsState) {
+		s.addressFamily = family
+	}
+}
+
+func withLocality(locality func(e endpoint.Info) []string) connectionsStateOption {
+	return func(s *connectionsState) {
+		s.locality = locality
+	}
+}
+
+func withEndpoints(endpoints, discovered []endpoint.Endpoint) connectionsStateOption {
+	return func(s *connectionsState) {
+		s.endpoints = endpoints
+		s.discovered = discovered
+	}
+}
+
+func withFallbackTrace(t *trace.Driver, localDC string, inFallback *atomic.Bool) connectionsStateOption {
+	return func(s *connectionsState) {
+		s.trace = t
+		s.localDC = localDC
+		s.inFallback = inFallback
+	}
+}
+
 func newConnectionsState(
 	conns []conn.Conn,
 	filter balancerConfig.Filter,
 	info balancerConfig.Info,
 	allowFallback bool,
+	opts ...connectionsStateOption,
 ) *connectionsState {
 	res := &connectionsState{
 		connByNodeID: connsToNodeIDMap(conns),
 		rand:         xrand.New(xrand.WithLock()),
 	}
 
+	for _, opt := range opts {
+		if opt != nil {
+			opt(res)
+		}
+	}
+
+	if res.comparator != nil {
+		conns = sortConnections(conns, res.comparator, res.loadWeighting)
+	}
+
 	res.prefer, res.fallback = sortPreferConnections(conns, filter, info, allowFallback)
+	if len(res.dcPriority) > 0 {
+		res.fallbacks = groupByDCPriority(res.fallback, res.dcPriority)
+	}
+	if res.locality != nil && len(info.SelfLocality) > 0 {
+		res.selfLocality = info.SelfLocality
+		res.prefers = groupByLocality([][]conn.Conn{res.prefer}, res.locality, res.selfLocality)
+		if res.fallbacks == nil {
+			res.fallbacks = [][]conn.Conn{res.fallback}
+		}
+		res.fallbacks = groupByLocality(res.fallbacks, res.locality, res.selfLocality)
+	}
+	if res.addressFamily != balancerConfig.DualStack {
+		if res.prefers == nil {
+			res.prefers = [][]conn.Conn{res.prefer}
+		}
+		res.prefers = groupByAddressFamily(res.prefers, res.addressFamily)
+		if res.fallbacks == nil {
+			res.fallbacks = [][]conn.Conn{res.fallback}
+		}
+		res.fallbacks = groupByAddressFamily(res.fallbacks, res.addressFamily)
+	}
 	if allowFallback {
 		res.all = conns
 	} else {
 		res.all = res.prefer
 	}
+	if _, strict := filter.(strictLocalDCFilter); strict {
+		// node ID from context must not choose connection outside local DC
+		res.connByNodeID = connsToNodeIDMap(res.all)
+	}
+
+	if res.clock != nil {
+		res.trackEntryTimes(conns)
+	}
+
+	if res.readReplicaFilter != nil {
+		for _, c := range res.all {
+			if res.readReplicaFilter(c.Endpoint()) {
+				res.replicas = append(res.replicas, c)
+			}
+		}
+	}
 
 	return res
 }
 
+// trackEntryTimes records times of entry of connections into state and splits groups of connections
+// into warm and cold connections if new endpoint bias defined
+func (s *connectionsState) trackEntryTimes(conns []conn.Conn) {
+	now := s.clock.Now()
+	previous := s.addedAt
+	s.addedAt = make(map[string]time.Time, len(conns))
+	var hasWarm, hasCold bool
+	for _, c := range conns {
+		address := c.Endpoint().Address()
+		if addedAt, has := previous[address]; has {
+			s.addedAt[address] = addedAt
+			hasWarm = true
+		} else {
+			s.addedAt[address] = now
+			hasCold = true
+		}
+	}
+
+	// bias has no sense on first discovery (all connections are cold) and without new endpoints
+	if s.newEndpointBias == balancerConfig.NoNewEndpointBias || !hasWarm || !hasCold {
+		return
+	}
+
+	isCold := func(c conn.Conn) bool {
+		return !s.addedAt[c.Endpoint().Address()].Before(now)
+	}
+	prefers, fallbacks := s.prefers, s.fallbacks
+	if prefers == nil {
+		prefers = [][]conn.Conn{s.prefer}
+	}
+	if fallbacks == nil {
+		fallbacks = [][]conn.Conn{s.fallback}
+	}
+	s.biasedPrefers = groupByAge(prefers, isCold, s.newEndpointBias)
+	s.biasedFallbacks = groupByAge(fallbacks, isCold, s.newEndpointBias)
+	s.biasUntil = now.Add(s.biasWindow)
+}
+
+// groupByAge splits every group of connections into warm and cold connections in order of bias
+func groupByAge(groups [][]conn.Conn, isCold func(c conn.Conn) bool, bias balancerConfig.NewEndpointBias) [][]conn.Conn {
+	res := make([][]conn.Conn, 0, len(groups)*2)
+	for _, group := range groups {
+		var warm, cold []conn.Conn
+		for _, c := range group {
+			if isCold(c) {
+				cold = append(cold, c)
+			} else {
+				warm = append(warm, c)
+			}
+		}
+		if bias == balancerConfig.ColdFirst {
+			res = append(res, cold, warm)
+		} else {
+			res = append(res, warm, cold)
+		}
+	}
+
+	return xslices.Filter(res, func(group []conn.Conn) bool {
+		return len(group) > 0
+	})
+}
+
+// entryTimes returns times of entry of connections into state by address
+func (s *connectionsState) entryTimes() map[string]time.Time {
+	if s == nil {
+		return nil
+	}
+
+	return s.addedAt
+}
+
+// groups returns groups of preferred and fallback connections with new endpoint bias during bias window
+func (s *connectionsState) groups() (prefers, fallbacks [][]conn.Conn) {
+	if s.biasedPrefers != nil && s.clock.Now().Before(s.biasUntil) {
+		return s.biasedPrefers, s.biasedFallbacks
+	}
+
+	return s.prefers, s.fallbacks
+}
+
 func (s *connectionsState) PreferredCount() int {
 	return len(s.prefer)
 }
@@ -57,6 +341,15 @@ func (s *connectionsState) All() (all []endpoint.Endpoint) {
 	return all
 }
 
+// conns returns all connections of state
+func (s *connectionsState) conns() []conn.Conn {
+	if s == nil {
+		return nil
+	}
+
+	return s.all
+}
+
 func (s *connectionsState) GetConnection(ctx context.Context) (_ conn.Conn, failedCount int) {
 	if err := ctx.Err(); err != nil {
 		return nil, 0
@@ -66,30 +359,217 @@ func (s *connectionsState) GetConnection(ctx context.Context) (_ conn.Conn, fail
 		return c, 0
 	}
 
+	if c := s.replicaConnection(ctx); c != nil {
+		return c, 0
+	}
+
+	if c := s.affinityConnection(ctx); c != nil {
+		return c, 0
+	}
+
+	if c := s.shardConnection(ctx); c != nil {
+		return c, 0
+	}
+
+	if c := s.pickConnection(ctx); c != nil {
+		return c, 0
+	}
+
 	try := func(conns []conn.Conn) conn.Conn {
-		c, tryFailed := s.selectRandomConnection(conns, false)
+		c, tryFailed := s.selectConnection(ctx, conns, false)
 		failedCount += tryFailed
 
 		return c
 	}
 
-	if c := try(s.prefer); c != nil {
+	prefers, fallbacks := s.groups()
+
+	if c := tryGroups(s.prefer, prefers, try); c != nil {
+		s.onPreferred(ctx)
+
 		return c, failedCount
 	}
 
-	if c := try(s.fallback); c != nil {
+	if c := tryGroups(s.fallback, fallbacks, try); c != nil {
+		s.onFallback(ctx)
+
 		return c, failedCount
 	}
 
-	c, _ := s.selectRandomConnection(s.all, true)
+	if ctx.Err() != nil {
+		return nil, failedCount
+	}
+
+	c, _ := s.selectConnection(ctx, s.all, true)
 
 	return c, failedCount
 }
 
+// tryGroups selects connection from groups of connections in order of groups.
+// If groups not defined - connection selects from conns
+func tryGroups(conns []conn.Conn, groups [][]conn.Conn, try func(conns []conn.Conn) conn.Conn) conn.Conn {
+	if groups == nil {
+		return try(conns)
+	}
+
+	for _, group := range groups {
+		if c := try(group); c != nil {
+			return c
+		}
+	}
+
+	return nil
+}
+
+// groupByAddressFamily splits every group of connections into connections with preferred address family
+// and other connections. Connections with host name instead of IP address are not of preferred address family
+func groupByAddressFamily(groups [][]conn.Conn, family balancerConfig.AddressFamily) [][]conn.Conn {
+	res := make([][]conn.Conn, 0, len(groups)*2)
+	for _, group := range groups {
+		var preferred, others []conn.Conn
+		for _, c := range group {
+			if addressFamily(c.Endpoint().Address()) == family {
+				preferred = append(preferred, c)
+			} else {
+				others = append(others, c)
+			}
+		}
+		res = append(res, preferred, others)
+	}
+
+	return xslices.Filter(res, func(group []conn.Conn) bool {
+		return len(group) > 0
+	})
+}
+
+// addressFamily returns address family of IP address from host:port address.
+// Returns DualStack if host is not an IP address (such as host name)
+func addressFamily(address string) balancerConfig.AddressFamily {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		host = address
+	}
+
+	ip := net.ParseIP(host)
+	switch {
+	case ip == nil:
+		return balancerConfig.DualStack
+	case ip.To4() != nil:
+		return balancerConfig.IPv4
+	default:
+		return balancerConfig.IPv6
+	}
+}
+
+// preferredAddressFamily returns address family of preferred connections or
+// other address family if no one connection of preferred address family exists
+func preferredAddressFamily(endpoints []endpoint.Endpoint, family balancerConfig.AddressFamily) string {
+	if family == balancerConfig.DualStack {
+		return ""
+	}
+
+	other := balancerConfig.IPv4
+	if family == balancerConfig.IPv4 {
+		other = balancerConfig.IPv6
+	}
+
+	hasOther := false
+	for _, e := range endpoints {
+		switch addressFamily(e.Address()) {
+		case family:
+			return family.String()
+		case other:
+			hasOther = true
+		}
+	}
+
+	if hasOther {
+		return other.String()
+	}
+
+	return balancerConfig.DualStack.String()
+}
+
+// groupByLocality splits every group of connections into groups by count of matched levels of locality
+// with self locality. Groups with deeper matched locality (such as same rack) go first
+func groupByLocality(
+	groups [][]conn.Conn, locality func(e endpoint.Info) []string, self []string,
+) [][]conn.Conn {
+	res := make([][]conn.Conn, 0, len(groups)*(len(self)+1))
+	for _, group := range groups {
+		levels := make([][]conn.Conn, len(self)+1)
+		for _, c := range group {
+			level := localityLevel(locality(c.Endpoint()), self)
+			levels[len(self)-level] = append(levels[len(self)-level], c)
+		}
+		res = append(res, levels...)
+	}
+
+	return xslices.Filter(res, func(group []conn.Conn) bool {
+		return len(group) > 0
+	})
+}
+
+// localityLevel returns count of matched levels of locality path from top level (DC)
+func localityLevel(path, self []string) int {
+	level := 0
+	for level < len(path) && level < len(self) && path[level] == self[level] {
+		level++
+	}
+
+	return level
+}
+
+// groupByDCPriority groups connections by location in order of DC priority.
+// Connections from DC not listed in priority groups into last group
+func groupByDCPriority(conns []conn.Conn, dcPriority []string) [][]conn.Conn {
+	index := make(map[string]int, len(dcPriority))
+	for i, dc := range dcPriority {
+		if _, has := index[dc]; !has {
+			index[dc] = i
+		}
+	}
+
+	groups := make([][]conn.Conn, len(dcPriority)+1)
+	for _, c := range conns {
+		i, has := index[c.Endpoint().Location()]
+		if !has {
+			i = len(dcPriority)
+		}
+		groups[i] = append(groups[i], c)
+	}
+
+	return xslices.Filter(groups, func(group []conn.Conn) bool {
+		return len(group) > 0
+	})
+}
+
+func (s *connectionsState) onFallback(ctx context.Context) {
+	if s.inFallback == nil || !s.inFallback.CompareAndSwap(false, true) {
+		return
+	}
+
+	trace.DriverOnBalancerFallback(s.trace, &ctx,
+		stack.FunctionID("github.com/ydb-platform/ydb-go-sdk/v3/internal/balancer.(*connectionsState).onFallback"),
+		s.localDC, len(s.prefer), len(s.fallback),
+	)
+}
+
+func (s *connectionsState) onPreferred(ctx context.Context) {
+	if s.inFallback == nil || !s.inFallback.CompareAndSwap(true, false) {
+		return
+	}
+
+	trace.DriverOnBalancerFallbackRecover(s.trace, &ctx,
+		stack.FunctionID("github.com/ydb-platform/ydb-go-sdk/v3/internal/balancer.(*connectionsState).onPreferred"),
+		s.localDC, len(s.prefer), len(s.fallback),
+	)
+}
+
 func (s *connectionsState) preferConnection(ctx context.Context) conn.Conn {
 	if nodeID, hasPreferEndpoint := endpoint.ContextNodeID(ctx); hasPreferEndpoint {
 		c := s.connByNodeID[nodeID]
-		if c != nil && isOkConnection(c, true) {
+		if c != nil && s.isOkConnection(c, true) {
 			return c
 		}
 	}
@@ -97,7 +577,283 @@ func (s *connectionsState) preferConnection(ctx context.Context) conn.Conn {
 	return nil
 }
 
-func (s *connectionsState) selectRandomConnection(conns []conn.Conn, allowBanned bool) (c conn.Conn, failedConns int) {
+// replicaConnection selects read replica connection if context prefers read replicas
+func (s *connectionsState) replicaConnection(ctx context.Context) conn.Conn {
+	if len(s.replicas) == 0 || !conn.PreferReadReplica(ctx) {
+		return nil
+	}
+
+	c, _ := s.selectConnection(ctx, s.replicas, false)
+
+	return c
+}
+
+// otherConnection selects connection with other endpoint than except connection (such as for hedged calls)
+func (s *connectionsState) otherConnection(ctx context.Context, except conn.Conn) conn.Conn {
+	isOther := func(c conn.Conn) bool {
+		return c.Endpoint().Address() != except.Endpoint().Address()
+	}
+
+	if c, _ := s.selectConnection(ctx, xslices.Filter(s.prefer, isOther), false); c != nil {
+		return c
+	}
+
+	c, _ := s.selectConnection(ctx, xslices.Filter(s.all, isOther), false)
+
+	return c
+}
+
+// role returns role of connection for tracing. Role is empty if read replicas not defined
+func (s *connectionsState) role(c conn.Conn) string {
+	switch {
+	case s == nil || s.readReplicaFilter == nil:
+		return ""
+	case s.readReplicaFilter(c.Endpoint()):
+		return "replica"
+	default:
+		return "primary"
+	}
+}
+
+// localityLevel returns count of matched levels of locality of connection for tracing.
+// Level is zero if locality not defined
+func (s *connectionsState) localityLevel(c conn.Conn) int {
+	if s == nil || s.locality == nil {
+		return 0
+	}
+
+	return localityLevel(s.locality(c.Endpoint()), s.selfLocality)
+}
+
+// affinityConnection selects connection for affinity key from context with rendezvous hashing.
+// Rendezvous hashing keeps choice of connection for key while connection exists in state
+func (s *connectionsState) affinityConnection(ctx context.Context) conn.Conn {
+	key, has := conn.EndpointAffinity(ctx)
+	if !has {
+		return nil
+	}
+
+	return s.rendezvousConnection([]byte(key))
+}
+
+// shardConnection selects connection for sharding key from context with rendezvous (HRW) hashing
+func (s *connectionsState) shardConnection(ctx context.Context) conn.Conn {
+	key, has := conn.ShardKey(ctx)
+	if !has {
+		return nil
+	}
+
+	return s.rendezvousConnection(key)
+}
+
+// rendezvousConnection selects connection with highest rendezvous (HRW) score of key among
+// ok preferred connections (or all connections if no one preferred).
+// Not ok connections are skipped, so key falls back to connection with next score while
+// its connection is banned and moves to other connection only if connection removed from discovery
+func (s *connectionsState) rendezvousConnection(key []byte) conn.Conn {
+	conns := s.prefer
+	if len(conns) == 0 {
+		conns = s.all
+	}
+
+	var (
+		best  conn.Conn
+		score uint64
+	)
+	for _, c := range conns {
+		if !s.isOkConnection(c, false) {
+			continue
+		}
+		if h := rendezvousHash(key, c.Endpoint().Address()); best == nil || h > score {
+			best, score = c, h
+		}
+	}
+
+	return best
+}
+
+func rendezvousHash(key []byte, address string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write(key)
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write([]byte(address))
+
+	return h.Sum64()
+}
+
+func (s *connectionsState) pickConnection(ctx context.Context) conn.Conn {
+	if s.picker == nil || len(s.all) == 0 {
+		return nil
+	}
+
+	return s.picker(ctx, s.all)
+}
+
+func (s *connectionsState) selectConnection(
+	ctx context.Context, conns []conn.Conn, allowBanned bool,
+) (c conn.Conn, failedConns int) {
+	if s.comparator != nil {
+		return s.selectSortedConnection(ctx, conns, allowBanned)
+	}
+
+	if s.loadWeighting {
+		return s.selectWeightedConnection(ctx, conns, allowBanned)
+	}
+
+	if s.selectionMode == balancerConfig.RoundRobin {
+		return s.selectRoundRobinConnection(ctx, conns, allowBanned)
+	}
+
+	return s.selectRandomConnection(ctx, conns, allowBanned)
+}
+
+// selectWeightedConnection selects random connection with probability inversely proportional to endpoint load
+func (s *connectionsState) selectWeightedConnection(
+	ctx context.Context, conns []conn.Conn, allowBanned bool,
+) (c conn.Conn, failedConns int) {
+	var (
+		candidates = make([]conn.Conn, 0, len(conns))
+		weights    = make([]float64, 0, len(conns))
+		total      float64
+	)
+	for i, c := range conns {
+		if i%ctxCheckPeriod == 0 && ctx.Err() != nil {
+			return nil, failedConns
+		}
+		if !s.isOkConnection(c, allowBanned) {
+			if s.isFailedConnection(c, allowBanned) {
+				failedConns++
+			}
+
+			continue
+		}
+		w := loadWeight(c.Endpoint())
+		candidates = append(candidates, c)
+		weights = append(weights, w)
+		total += w
+	}
+
+	if len(candidates) == 0 {
+		return nil, failedConns
+	}
+
+	point := s.rand.Float64() * total
+	for i, w := range weights {
+		if point < w {
+			return candidates[i], 0
+		}
+		point -= w
+	}
+
+	return candidates[len(candidates)-1], 0
+}
+
+// sortConnections sorts connections by comparator. With load weighting connections sorts by load weight
+// and comparator orders connections only within groups of connections with equal load weight
+func sortConnections(conns []conn.Conn, cmp func(a, b endpoint.Info) int, loadWeighting bool) []conn.Conn {
+	return xslices.SortCopy(conns, func(lhs, rhs conn.Conn) int {
+		if loadWeighting {
+			if lw, rw := loadWeight(lhs.Endpoint()), loadWeight(rhs.Endpoint()); lw != rw {
+				if lw > rw {
+					return -1
+				}
+
+				return 1
+			}
+		}
+
+		return cmp(lhs.Endpoint(), rhs.Endpoint())
+	})
+}
+
+// selectSortedConnection selects connection by round-robin over sorted connections.
+// With load weighting selectSortedConnection chooses group of connections with equal load weight
+// by weighted random choice and selects connection by round-robin inside group
+func (s *connectionsState) selectSortedConnection(
+	ctx context.Context, conns []conn.Conn, allowBanned bool,
+) (c conn.Conn, failedConns int) {
+	if !s.loadWeighting {
+		return s.selectRoundRobinConnection(ctx, conns, allowBanned)
+	}
+
+	var (
+		groups  [][]conn.Conn
+		weights []float64
+		total   float64
+	)
+	// connections sorted by load weight, so connections with equal load weight are adjacent
+	for start, i := 0, 1; i <= len(conns); i++ {
+		if i < len(conns) && loadWeight(conns[i].Endpoint()) == loadWeight(conns[start].Endpoint()) {
+			continue
+		}
+		var w float64
+		for _, c := range conns[start:i] {
+			if s.isOkConnection(c, allowBanned) {
+				w += loadWeight(c.Endpoint())
+			} else if s.isFailedConnection(c, allowBanned) {
+				failedConns++
+			}
+		}
+		if w > 0 {
+			groups = append(groups, conns[start:i])
+			weights = append(weights, w)
+			total += w
+		}
+		start = i
+	}
+
+	if len(groups) == 0 {
+		return nil, failedConns
+	}
+
+	point := s.rand.Float64() * total
+	for i, w := range weights {
+		if point < w || i == len(weights)-1 {
+			return s.selectRoundRobinConnection(ctx, groups[i], allowBanned)
+		}
+		point -= w
+	}
+
+	return nil, failedConns
+}
+
+// selectRoundRobinConnection selects next connection in rotation order with skip of failed connections
+func (s *connectionsState) selectRoundRobinConnection(
+	ctx context.Context, conns []conn.Conn, allowBanned bool,
+) (c conn.Conn, failedConns int) {
+	connCount := len(conns)
+	if connCount == 0 {
+		return nil, 0
+	}
+
+	start := int((s.rotation.Add(1) - 1) % uint64(connCount))
+	for i := 0; i < connCount; i++ {
+		if i%ctxCheckPeriod == 0 && ctx.Err() != nil {
+			return nil, failedConns
+		}
+		c := conns[(start+i)%connCount]
+		if s.isOkConnection(c, allowBanned) {
+			return c, 0
+		}
+		if s.isFailedConnection(c, allowBanned) {
+			failedConns++
+		}
+	}
+
+	return nil, failedConns
+}
+
+func loadWeight(e endpoint.Info) float64 {
+	if loadFactor := e.LoadFactor(); loadFactor > 0 {
+		return 1 / (1 + float64(loadFactor))
+	}
+
+	return 1
+}
+
+func (s *connectionsState) selectRandomConnection(
+	ctx context.Context, conns []conn.Conn, allowBanned bool,
+) (c conn.Conn, failedConns int) {
 	connCount := len(conns)
 	if connCount == 0 {
 		// return for empty list need for prevent panic in fast path
@@ -105,25 +861,30 @@ func (s *connectionsState) selectRandomConnection(conns []conn.Conn, allowBanned
 	}
 
 	// fast path
-	if c := conns[s.rand.Int(connCount)]; isOkConnection(c, allowBanned) {
+	if c := conns[s.rand.Int(connCount)]; s.isOkConnection(c, allowBanned) {
 		return c, 0
 	}
 
-	// shuffled indexes slices need for guarantee about every connection will check
+	// shuffled indexes slices need for guarantee about every connection will check.
+	// Indexes shuffles lazily (step by step of Fisher-Yates shuffle) for prompt return on context cancellation
 	indexes := make([]int, connCount)
 	for index := range indexes {
 		indexes[index] = index
 	}
-	s.rand.Shuffle(connCount, func(i, j int) {
-		indexes[i], indexes[j] = indexes[j], indexes[i]
-	})
 
-	for _, index := range indexes {
-		c := conns[index]
-		if isOkConnection(c, allowBanned) {
+	for i := range indexes {
+		if i%ctxCheckPeriod == 0 && ctx.Err() != nil {
+			return nil, failedConns
+		}
+		j := i + s.rand.Int(connCount-i)
+		indexes[i], indexes[j] = indexes[j], indexes[i]
+		c := conns[indexes[i]]
+		if s.isOkConnection(c, allowBanned) {
 			return c, 0
 		}
-		failedConns++
+		if s.isFailedConnection(c, allowBanned) {
+			failedConns++
+		}
 	}
 
 	return nil, failedConns
@@ -167,6 +928,40 @@ func sortPreferConnections(
 	return prefer, fallback
 }
 
+// strictLocalDCFilter allows only endpoints located in local DC in addition to filter of balancer.
+// Filter allows no one endpoint if local DC not detected
+type strictLocalDCFilter struct {
+	filter balancerConfig.Filter
+}
+
+func (f strictLocalDCFilter) Allow(info balancerConfig.Info, e endpoint.Info) bool {
+	if info.SelfLocation == "" || e.Location() != info.SelfLocation {
+		return false
+	}
+
+	return f.filter == nil || f.filter.Allow(info, e)
+}
+
+func (f strictLocalDCFilter) String() string {
+	if f.filter == nil {
+		return "StrictLocalDC"
+	}
+
+	return "StrictLocalDC(" + f.filter.String() + ")"
+}
+
+// isOkConnection checks state of connection, circuit breaker and readiness of connection.
+// Not ready connections are ok only if banned connections are ok (such as on exhaustion of ready connections)
+func (s *connectionsState) isOkConnection(c conn.Conn, bannedIsOk bool) bool {
+	return isOkConnection(c, bannedIsOk) && s.circuitBreakers.available(c) && (bannedIsOk || s.readiness.isReady(c))
+}
+
+// isFailedConnection checks that not ok connection is failed. Connections which not ok only due to pending
+// readiness check are not failed, so they not force rediscovery
+func (s *connectionsState) isFailedConnection(c conn.Conn, bannedIsOk bool) bool {
+	return !isOkConnection(c, bannedIsOk) || !s.circuitBreakers.available(c)
+}
+
 func isOkConnection(c conn.Conn, bannedIsOk bool) bool {
 	switch c.GetState() {
 	case conn.Online, conn.Created, conn.Offline:
@@ -177,3 +972,15 @@ func isOkConnection(c conn.Conn, bannedIsOk bool) bool {
 		return false
 	}
 }
+
+// allBanned checks that all connections banned. Balancer refuses calls with ErrAllEndpointsBanned
+// instead of fallback to banned connections in this case
+func allBanned(conns []conn.Conn) bool {
+	for _, c := range conns {
+		if c.GetState() != conn.Banned {
+			return false
+		}
+	}
+
+	return len(conns) > 0
+}
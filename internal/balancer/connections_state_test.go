@@ -2,15 +2,20 @@ package balancer
 
 import (
 	"context"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/jonboulle/clockwork"
 	"github.com/stretchr/testify/require"
 
 	balancerConfig "github.com/ydb-platform/ydb-go-sdk/v3/internal/balancer/config"
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/conn"
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/endpoint"
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/mock"
+	"github.com/ydb-platform/ydb-go-sdk/v3/trace"
 )
 
 func TestConnsToNodeIDMap(t *testing.T) {
@@ -158,27 +163,28 @@ func TestSortPreferConnections(t *testing.T) {
 }
 
 func TestSelectRandomConnection(t *testing.T) {
+	ctx := context.Background()
 	s := newConnectionsState(nil, nil, balancerConfig.Info{}, false)
 
 	t.Run("Empty", func(t *testing.T) {
-		c, failedCount := s.selectRandomConnection(nil, false)
+		c, failedCount := s.selectRandomConnection(ctx, nil, false)
 		require.Nil(t, c)
 		require.Equal(t, 0, failedCount)
 	})
 
 	t.Run("One", func(t *testing.T) {
 		for _, goodState := range []conn.State{conn.Online, conn.Offline, conn.Created} {
-			c, failedCount := s.selectRandomConnection([]conn.Conn{&mock.Conn{AddrField: "asd", State: goodState}}, false)
+			c, failedCount := s.selectRandomConnection(ctx, []conn.Conn{&mock.Conn{AddrField: "asd", State: goodState}}, false)
 			require.Equal(t, &mock.Conn{AddrField: "asd", State: goodState}, c)
 			require.Equal(t, 0, failedCount)
 		}
 	})
 	t.Run("OneBanned", func(t *testing.T) {
-		c, failedCount := s.selectRandomConnection([]conn.Conn{&mock.Conn{AddrField: "asd", State: conn.Banned}}, false)
+		c, failedCount := s.selectRandomConnection(ctx, []conn.Conn{&mock.Conn{AddrField: "asd", State: conn.Banned}}, false)
 		require.Nil(t, c)
 		require.Equal(t, 1, failedCount)
 
-		c, failedCount = s.selectRandomConnection([]conn.Conn{&mock.Conn{AddrField: "asd", State: conn.Banned}}, true)
+		c, failedCount = s.selectRandomConnection(ctx, []conn.Conn{&mock.Conn{AddrField: "asd", State: conn.Banned}}, true)
 		require.Equal(t, &mock.Conn{AddrField: "asd", State: conn.Banned}, c)
 		require.Equal(t, 0, failedCount)
 	})
@@ -190,7 +196,7 @@ func TestSelectRandomConnection(t *testing.T) {
 		first := 0
 		second := 0
 		for i := 0; i < 100; i++ {
-			c, _ := s.selectRandomConnection(conns, false)
+			c, _ := s.selectRandomConnection(ctx, conns, false)
 			if c.Endpoint().Address() == "1" {
 				first++
 			} else {
@@ -208,7 +214,7 @@ func TestSelectRandomConnection(t *testing.T) {
 		}
 		totalFailed := 0
 		for i := 0; i < 100; i++ {
-			c, failed := s.selectRandomConnection(conns, false)
+			c, failed := s.selectRandomConnection(ctx, conns, false)
 			require.Nil(t, c)
 			totalFailed += failed
 		}
@@ -224,7 +230,7 @@ func TestSelectRandomConnection(t *testing.T) {
 		second := 0
 		failed := 0
 		for i := 0; i < 100; i++ {
-			c, checkFailed := s.selectRandomConnection(conns, false)
+			c, checkFailed := s.selectRandomConnection(ctx, conns, false)
 			failed += checkFailed
 			switch c.Endpoint().Address() {
 			case "1":
@@ -462,3 +468,638 @@ func TestConnection(t *testing.T) {
 		require.Equal(t, 0, failed)
 	})
 }
+
+func TestConnectionPicker(t *testing.T) {
+	conns := []conn.Conn{
+		&mock.Conn{AddrField: "1", State: conn.Online, NodeIDField: 1},
+		&mock.Conn{AddrField: "2", State: conn.Online, NodeIDField: 2},
+	}
+	t.Run("Picked", func(t *testing.T) {
+		s := newConnectionsState(conns, nil, balancerConfig.Info{}, false,
+			withConnectionPicker(func(ctx context.Context, conns []conn.Conn) conn.Conn {
+				require.Len(t, conns, 2)
+
+				return conns[1]
+			}),
+		)
+		for i := 0; i < 100; i++ {
+			c, failed := s.GetConnection(context.Background())
+			require.Equal(t, &mock.Conn{AddrField: "2", State: conn.Online, NodeIDField: 2}, c)
+			require.Equal(t, 0, failed)
+		}
+	})
+	t.Run("FallThrough", func(t *testing.T) {
+		s := newConnectionsState(conns, nil, balancerConfig.Info{}, false,
+			withConnectionPicker(func(ctx context.Context, conns []conn.Conn) conn.Conn {
+				return nil
+			}),
+		)
+		c, failed := s.GetConnection(context.Background())
+		require.NotNil(t, c)
+		require.Equal(t, 0, failed)
+	})
+	t.Run("PreferNodeID", func(t *testing.T) {
+		s := newConnectionsState(conns, nil, balancerConfig.Info{}, false,
+			withConnectionPicker(func(ctx context.Context, conns []conn.Conn) conn.Conn {
+				return conns[1]
+			}),
+		)
+		c, failed := s.GetConnection(endpoint.WithNodeID(context.Background(), 1))
+		require.Equal(t, &mock.Conn{AddrField: "1", State: conn.Online, NodeIDField: 1}, c)
+		require.Equal(t, 0, failed)
+	})
+}
+
+func TestLoadWeighting(t *testing.T) {
+	const total = 10000
+	s := newConnectionsState([]conn.Conn{
+		&mock.Conn{AddrField: "1", State: conn.Online, LoadFactorField: 0},
+		&mock.Conn{AddrField: "2", State: conn.Online, LoadFactorField: 1},
+		&mock.Conn{AddrField: "3", State: conn.Online, LoadFactorField: 3},
+		&mock.Conn{AddrField: "4", State: conn.Online, LoadFactorField: -1},
+		&mock.Conn{AddrField: "5", State: conn.Banned, LoadFactorField: 0},
+	}, nil, balancerConfig.Info{}, false, withLoadWeighting(true))
+	// weights: 1, 1/2, 1/4, 1 (banned connection excluded)
+	expected := map[string]float64{
+		"1": 1 / 2.75,
+		"2": 0.5 / 2.75,
+		"3": 0.25 / 2.75,
+		"4": 1 / 2.75,
+	}
+	counts := make(map[string]int, len(expected))
+	for i := 0; i < total; i++ {
+		c, failed := s.GetConnection(context.Background())
+		require.NotNil(t, c)
+		require.Equal(t, 0, failed)
+		counts[c.Endpoint().Address()]++
+	}
+	require.Len(t, counts, len(expected))
+	for address, probability := range expected {
+		require.InDelta(t, probability, float64(counts[address])/total, 0.03, address)
+	}
+}
+
+func TestRoundRobin(t *testing.T) {
+	ctx := context.Background()
+	conns := []conn.Conn{
+		&mock.Conn{AddrField: "1", State: conn.Online},
+		&mock.Conn{AddrField: "2", State: conn.Online},
+		&mock.Conn{AddrField: "3", State: conn.Banned},
+		&mock.Conn{AddrField: "4", State: conn.Online},
+	}
+	s := newConnectionsState(conns, nil, balancerConfig.Info{}, false, withSelectionMode(balancerConfig.RoundRobin))
+
+	counts := make(map[string]int, len(conns))
+	for i := 0; i < 3*len(conns); i++ {
+		c, failed := s.GetConnection(ctx)
+		require.NotNil(t, c)
+		require.Equal(t, 0, failed)
+		counts[c.Endpoint().Address()]++
+	}
+	// banned connection skips to next connection in rotation
+	require.Equal(t, map[string]int{"1": 3, "2": 3, "4": 6}, counts)
+
+	conns[2].SetState(ctx, conn.Online)
+	defer conns[2].SetState(ctx, conn.Banned)
+	counts = make(map[string]int, len(conns))
+	for i := 0; i < 5*len(conns); i++ {
+		c, _ := s.GetConnection(ctx)
+		require.NotNil(t, c)
+		counts[c.Endpoint().Address()]++
+	}
+	require.Equal(t, map[string]int{"1": 5, "2": 5, "3": 5, "4": 5}, counts)
+}
+
+func TestEndpointComparator(t *testing.T) {
+	ctx := context.Background()
+	byNodeID := func(a, b endpoint.Info) int {
+		return int(a.NodeID()) - int(b.NodeID())
+	}
+
+	t.Run("RoundRobin", func(t *testing.T) {
+		conns := []conn.Conn{
+			&mock.Conn{AddrField: "3", NodeIDField: 3, State: conn.Online},
+			&mock.Conn{AddrField: "1", NodeIDField: 1, State: conn.Online},
+			&mock.Conn{AddrField: "4", NodeIDField: 4, State: conn.Banned},
+			&mock.Conn{AddrField: "2", NodeIDField: 2, State: conn.Online},
+		}
+		s := newConnectionsState(conns, nil, balancerConfig.Info{}, false, withEndpointComparator(byNodeID))
+
+		var chosen []string
+		for i := 0; i < 2*len(conns); i++ {
+			c, failed := s.GetConnection(ctx)
+			require.NotNil(t, c)
+			require.Equal(t, 0, failed)
+			chosen = append(chosen, c.Endpoint().Address())
+		}
+		// banned connection skips to next connection in sorted order
+		require.Equal(t, []string{"1", "2", "3", "1", "1", "2", "3", "1"}, chosen)
+	})
+
+	t.Run("LoadWeighting", func(t *testing.T) {
+		conns := []conn.Conn{
+			&mock.Conn{AddrField: "4", NodeIDField: 4, State: conn.Online, LoadFactorField: 1},
+			&mock.Conn{AddrField: "2", NodeIDField: 2, State: conn.Online},
+			&mock.Conn{AddrField: "3", NodeIDField: 3, State: conn.Online, LoadFactorField: 1},
+			&mock.Conn{AddrField: "1", NodeIDField: 1, State: conn.Online},
+		}
+		s := newConnectionsState(conns, nil, balancerConfig.Info{}, false,
+			withEndpointComparator(byNodeID),
+			withLoadWeighting(true),
+		)
+		sorted := make([]string, 0, len(s.all))
+		for _, c := range s.all {
+			sorted = append(sorted, c.Endpoint().Address())
+		}
+		require.Equal(t, []string{"1", "2", "3", "4"}, sorted)
+
+		const total = 10000
+		counts := make(map[string]int, len(conns))
+		for i := 0; i < total; i++ {
+			c, failed := s.GetConnection(ctx)
+			require.NotNil(t, c)
+			require.Equal(t, 0, failed)
+			counts[c.Endpoint().Address()]++
+		}
+		// group of unloaded connections has weight 2, group of loaded connections has weight 1
+		require.InDelta(t, 2.0/3, float64(counts["1"]+counts["2"])/total, 0.03)
+		require.InDelta(t, 1.0/3, float64(counts["3"]+counts["4"])/total, 0.03)
+	})
+}
+
+func TestLoadWeight(t *testing.T) {
+	require.InDelta(t, 1, loadWeight(&mock.Endpoint{LoadFactorField: 0}), 1e-9)
+	require.InDelta(t, 1, loadWeight(&mock.Endpoint{LoadFactorField: -5}), 1e-9)
+	require.InDelta(t, 0.5, loadWeight(&mock.Endpoint{LoadFactorField: 1}), 1e-9)
+}
+
+func TestFallbackTrace(t *testing.T) {
+	var (
+		inFallback atomic.Bool
+		events     []string
+		conns      = []conn.Conn{
+			&mock.Conn{AddrField: "t1", State: conn.Banned, LocationField: "t"},
+			&mock.Conn{AddrField: "f1", State: conn.Online, LocationField: "f"},
+		}
+		tr = &trace.Driver{
+			OnBalancerFallback: func(info trace.DriverBalancerFallbackInfo) {
+				require.Equal(t, "t", info.LocalDC)
+				require.Equal(t, 1, info.PreferredConn)
+				require.Equal(t, 1, info.FallbackConn)
+				events = append(events, "fallback")
+			},
+			OnBalancerFallbackRecover: func(info trace.DriverBalancerFallbackRecoverInfo) {
+				require.Equal(t, "t", info.LocalDC)
+				events = append(events, "recover")
+			},
+		}
+		filter = filterFunc(func(info balancerConfig.Info, e endpoint.Info) bool {
+			return e.Location() == info.SelfLocation
+		})
+	)
+	s := newConnectionsState(conns, filter, balancerConfig.Info{SelfLocation: "t"}, true,
+		withFallbackTrace(tr, "t", &inFallback),
+	)
+	for i := 0; i < 10; i++ {
+		c, _ := s.GetConnection(context.Background())
+		require.Equal(t, "f1", c.Endpoint().Address())
+	}
+	require.Equal(t, []string{"fallback"}, events)
+
+	conns[0].SetState(context.Background(), conn.Online)
+	for i := 0; i < 10; i++ {
+		c, _ := s.GetConnection(context.Background())
+		require.Equal(t, "t1", c.Endpoint().Address())
+	}
+	require.Equal(t, []string{"fallback", "recover"}, events)
+}
+
+func TestEndpointAffinity(t *testing.T) {
+	conns := []conn.Conn{
+		&mock.Conn{AddrField: "1", State: conn.Online},
+		&mock.Conn{AddrField: "2", State: conn.Online},
+		&mock.Conn{AddrField: "3", State: conn.Online},
+		&mock.Conn{AddrField: "4", State: conn.Online},
+	}
+	ctx := conn.WithEndpointAffinity(context.Background(), "tx-1")
+
+	s := newConnectionsState(conns, nil, balancerConfig.Info{}, false)
+	affinity, _ := s.GetConnection(ctx)
+	require.NotNil(t, affinity)
+	for i := 0; i < 100; i++ {
+		c, _ := s.GetConnection(ctx)
+		require.Equal(t, affinity.Endpoint().Address(), c.Endpoint().Address())
+	}
+
+	t.Run("SurviveDiscovery", func(t *testing.T) {
+		var others []conn.Conn
+		for _, c := range conns {
+			if c.Endpoint().Address() != affinity.Endpoint().Address() {
+				others = append(others, c)
+			}
+		}
+		s := newConnectionsState(append(others[1:], affinity, &mock.Conn{AddrField: "5", State: conn.Online}),
+			nil, balancerConfig.Info{}, false,
+		)
+		c, _ := s.GetConnection(ctx)
+		require.Equal(t, affinity.Endpoint().Address(), c.Endpoint().Address())
+	})
+
+	t.Run("Banned", func(t *testing.T) {
+		affinity.SetState(ctx, conn.Banned)
+		defer affinity.SetState(ctx, conn.Online)
+		fallback, _ := s.GetConnection(ctx)
+		require.NotEqual(t, affinity.Endpoint().Address(), fallback.Endpoint().Address())
+		for i := 0; i < 100; i++ {
+			c, _ := s.GetConnection(ctx)
+			require.Equal(t, fallback.Endpoint().Address(), c.Endpoint().Address())
+		}
+	})
+}
+
+func TestPreferReadReplica(t *testing.T) {
+	conns := []conn.Conn{
+		&mock.Conn{AddrField: "1", LocationField: "primary", State: conn.Online},
+		&mock.Conn{AddrField: "2", LocationField: "replica", State: conn.Online},
+		&mock.Conn{AddrField: "3", LocationField: "replica", State: conn.Online},
+	}
+	isReplica := func(e endpoint.Info) bool {
+		return e.Location() == "replica"
+	}
+	s := newConnectionsState(conns, nil, balancerConfig.Info{}, false, withReadReplicaFilter(isReplica))
+	require.Len(t, s.replicas, 2)
+	require.Equal(t, "primary", s.role(conns[0]))
+	require.Equal(t, "replica", s.role(conns[1]))
+	require.Empty(t, newConnectionsState(conns, nil, balancerConfig.Info{}, false).role(conns[1]))
+
+	ctx := conn.WithPreferReadReplica(context.Background())
+	for i := 0; i < 100; i++ {
+		c, _ := s.GetConnection(ctx)
+		require.Equal(t, "replica", c.Endpoint().Location())
+	}
+
+	t.Run("ReplicasBanned", func(t *testing.T) {
+		conns[1].SetState(ctx, conn.Banned)
+		conns[2].SetState(ctx, conn.Banned)
+		defer conns[1].SetState(ctx, conn.Online)
+		defer conns[2].SetState(ctx, conn.Online)
+		for i := 0; i < 100; i++ {
+			c, _ := s.GetConnection(ctx)
+			require.Equal(t, "1", c.Endpoint().Address())
+		}
+	})
+}
+
+func TestShardKey(t *testing.T) {
+	conns := make([]conn.Conn, 10)
+	for i := range conns {
+		conns[i] = &mock.Conn{AddrField: strconv.Itoa(i), State: conn.Online}
+	}
+	route := func(s *connectionsState, key []byte) string {
+		c, _ := s.GetConnection(conn.WithShardKey(context.Background(), key))
+		require.NotNil(t, c)
+
+		return c.Endpoint().Address()
+	}
+
+	s := newConnectionsState(conns, nil, balancerConfig.Info{}, false)
+	routes := make(map[string]string, 1000)
+	for i := 0; i < 1000; i++ {
+		key := "key-" + strconv.Itoa(i)
+		routes[key] = route(s, []byte(key))
+		require.Equal(t, routes[key], route(s, []byte(key)))
+	}
+
+	t.Run("MinimalRemapping", func(t *testing.T) {
+		removed := conns[3].Endpoint().Address()
+		s := newConnectionsState(append(append([]conn.Conn{}, conns[:3]...), conns[4:]...),
+			nil, balancerConfig.Info{}, false,
+		)
+		var remapped int
+		for key, address := range routes {
+			if address == removed {
+				remapped++
+				require.NotEqual(t, removed, route(s, []byte(key)))
+			} else {
+				require.Equal(t, address, route(s, []byte(key)))
+			}
+		}
+		require.NotZero(t, remapped)
+	})
+
+	t.Run("Banned", func(t *testing.T) {
+		ctx := context.Background()
+		conns[5].SetState(ctx, conn.Banned)
+		defer conns[5].SetState(ctx, conn.Online)
+		for key, address := range routes {
+			if address == conns[5].Endpoint().Address() {
+				require.NotEqual(t, address, route(s, []byte(key)))
+			} else {
+				require.Equal(t, address, route(s, []byte(key)))
+			}
+		}
+	})
+}
+
+func TestDCPriority(t *testing.T) {
+	ctx := context.Background()
+	conns := []conn.Conn{
+		&mock.Conn{AddrField: "a1", LocationField: "a", State: conn.Online},
+		&mock.Conn{AddrField: "b1", LocationField: "b", State: conn.Online},
+		&mock.Conn{AddrField: "b2", LocationField: "b", State: conn.Online},
+		&mock.Conn{AddrField: "c1", LocationField: "c", State: conn.Online},
+		&mock.Conn{AddrField: "d1", LocationField: "d", State: conn.Online},
+		&mock.Conn{AddrField: "e1", LocationField: "e", State: conn.Online},
+	}
+	local := filterFunc(func(info balancerConfig.Info, e endpoint.Info) bool {
+		return info.SelfLocation == e.Location()
+	})
+	s := newConnectionsState(conns, local, balancerConfig.Info{SelfLocation: "a"}, true,
+		withDCPriority([]string{"c", "b"}),
+	)
+	locations := func() map[string]bool {
+		res := make(map[string]bool)
+		for i := 0; i < 100; i++ {
+			c, _ := s.GetConnection(ctx)
+			require.NotNil(t, c)
+			res[c.Endpoint().Location()] = true
+		}
+
+		return res
+	}
+	setState := func(state conn.State, conns ...conn.Conn) {
+		for _, c := range conns {
+			c.SetState(ctx, state)
+		}
+	}
+	defer setState(conn.Online, conns...)
+
+	require.Equal(t, map[string]bool{"a": true}, locations())
+
+	setState(conn.Banned, conns[0])
+	require.Equal(t, map[string]bool{"c": true}, locations())
+
+	setState(conn.Banned, conns[3])
+	require.Equal(t, map[string]bool{"b": true}, locations())
+
+	setState(conn.Banned, conns[1])
+	require.Equal(t, map[string]bool{"b": true}, locations())
+
+	setState(conn.Banned, conns[2])
+	require.Equal(t, map[string]bool{"d": true, "e": true}, locations())
+
+	setState(conn.Online, conns[3])
+	require.Equal(t, map[string]bool{"c": true}, locations())
+
+	t.Run("NoPriority", func(t *testing.T) {
+		setState(conn.Banned, conns...)
+		setState(conn.Online, conns[1], conns[3], conns[4])
+		s = newConnectionsState(conns, local, balancerConfig.Info{SelfLocation: "a"}, true)
+		require.Equal(t, map[string]bool{"b": true, "c": true, "d": true}, locations())
+	})
+}
+
+func TestGroupByDCPriority(t *testing.T) {
+	conns := []conn.Conn{
+		&mock.Conn{AddrField: "a1", LocationField: "a"},
+		&mock.Conn{AddrField: "b1", LocationField: "b"},
+		&mock.Conn{AddrField: "c1", LocationField: "c"},
+		&mock.Conn{AddrField: "a2", LocationField: "a"},
+	}
+	require.Equal(t, [][]conn.Conn{
+		{conns[2]},
+		{conns[0], conns[3]},
+		{conns[1]},
+	}, groupByDCPriority(conns, []string{"c", "x", "a", "c"}))
+}
+
+func TestAddressFamily(t *testing.T) {
+	ctx := context.Background()
+	conns := []conn.Conn{
+		&mock.Conn{AddrField: "[::1]:2135", State: conn.Online},
+		&mock.Conn{AddrField: "[fe80::2]:2135", State: conn.Online},
+		&mock.Conn{AddrField: "10.0.0.1:2135", State: conn.Online},
+		&mock.Conn{AddrField: "host:2135", State: conn.Online},
+	}
+	addresses := func(s *connectionsState) map[string]bool {
+		res := make(map[string]bool)
+		for i := 0; i < 100; i++ {
+			c, _ := s.GetConnection(ctx)
+			require.NotNil(t, c)
+			res[c.Endpoint().Address()] = true
+		}
+
+		return res
+	}
+
+	s := newConnectionsState(conns, nil, balancerConfig.Info{}, false, withAddressFamily(balancerConfig.IPv6))
+	require.Equal(t, map[string]bool{"[::1]:2135": true, "[fe80::2]:2135": true}, addresses(s))
+
+	t.Run("PreferredUnavailable", func(t *testing.T) {
+		conns[0].SetState(ctx, conn.Banned)
+		conns[1].SetState(ctx, conn.Banned)
+		defer conns[0].SetState(ctx, conn.Online)
+		defer conns[1].SetState(ctx, conn.Online)
+		require.Equal(t, map[string]bool{"10.0.0.1:2135": true, "host:2135": true}, addresses(s))
+	})
+
+	t.Run("IPv4", func(t *testing.T) {
+		s := newConnectionsState(conns, nil, balancerConfig.Info{}, false, withAddressFamily(balancerConfig.IPv4))
+		require.Equal(t, map[string]bool{"10.0.0.1:2135": true}, addresses(s))
+	})
+
+	t.Run("WithDCPriority", func(t *testing.T) {
+		conns := []conn.Conn{
+			&mock.Conn{AddrField: "[::1]:2135", LocationField: "a", State: conn.Banned},
+			&mock.Conn{AddrField: "10.0.0.1:2135", LocationField: "a", State: conn.Online},
+			&mock.Conn{AddrField: "[::2]:2135", LocationField: "b", State: conn.Online},
+			&mock.Conn{AddrField: "[::3]:2135", LocationField: "c", State: conn.Online},
+		}
+		local := filterFunc(func(info balancerConfig.Info, e endpoint.Info) bool {
+			return info.SelfLocation == e.Location()
+		})
+		s := newConnectionsState(conns, local, balancerConfig.Info{SelfLocation: "a"}, true,
+			withAddressFamily(balancerConfig.IPv6), withDCPriority([]string{"c", "b"}),
+		)
+		require.Equal(t, map[string]bool{"10.0.0.1:2135": true}, addresses(s))
+		conns[1].SetState(ctx, conn.Banned)
+		require.Equal(t, map[string]bool{"[::3]:2135": true}, addresses(s))
+	})
+}
+
+func TestPreferredAddressFamily(t *testing.T) {
+	ipv4 := &mock.Endpoint{AddrField: "10.0.0.1:2135"}
+	ipv6 := &mock.Endpoint{AddrField: "[::1]:2135"}
+	host := &mock.Endpoint{AddrField: "host:2135"}
+	for _, tt := range []struct {
+		endpoints []endpoint.Endpoint
+		family    balancerConfig.AddressFamily
+		exp       string
+	}{
+		{[]endpoint.Endpoint{ipv4, ipv6}, balancerConfig.DualStack, ""},
+		{[]endpoint.Endpoint{ipv4, ipv6}, balancerConfig.IPv6, "IPv6"},
+		{[]endpoint.Endpoint{ipv4, host}, balancerConfig.IPv6, "IPv4"},
+		{[]endpoint.Endpoint{ipv6, host}, balancerConfig.IPv4, "IPv6"},
+		{[]endpoint.Endpoint{host}, balancerConfig.IPv4, "DualStack"},
+	} {
+		t.Run("", func(t *testing.T) {
+			require.Equal(t, tt.exp, preferredAddressFamily(tt.endpoints, tt.family))
+		})
+	}
+}
+
+func TestLocality(t *testing.T) {
+	ctx := context.Background()
+	conns := []conn.Conn{
+		&mock.Conn{AddrField: "a/r1/1", LocationField: "a", State: conn.Online},
+		&mock.Conn{AddrField: "a/r2/1", LocationField: "a", State: conn.Online},
+		&mock.Conn{AddrField: "a/r2/2", LocationField: "a", State: conn.Online},
+		&mock.Conn{AddrField: "b/r1/1", LocationField: "b", State: conn.Online},
+		&mock.Conn{AddrField: "b/r2/1", LocationField: "b", State: conn.Online},
+	}
+	locality := func(e endpoint.Info) []string {
+		return strings.Split(e.Address(), "/")[:2]
+	}
+	local := filterFunc(func(info balancerConfig.Info, e endpoint.Info) bool {
+		return info.SelfLocation == e.Location()
+	})
+	info := balancerConfig.Info{SelfLocation: "a", SelfLocality: []string{"a", "r2"}}
+	s := newConnectionsState(conns, local, info, true, withLocality(locality))
+	addresses := func() map[string]bool {
+		res := make(map[string]bool)
+		for i := 0; i < 100; i++ {
+			c, _ := s.GetConnection(ctx)
+			require.NotNil(t, c)
+			res[c.Endpoint().Address()] = true
+		}
+
+		return res
+	}
+	defer func() {
+		for _, c := range conns {
+			c.SetState(ctx, conn.Online)
+		}
+	}()
+
+	require.Equal(t, map[string]bool{"a/r2/1": true, "a/r2/2": true}, addresses())
+	require.Equal(t, 2, s.localityLevel(conns[1]))
+	require.Equal(t, 1, s.localityLevel(conns[0]))
+	require.Equal(t, 0, s.localityLevel(conns[4]))
+
+	conns[1].SetState(ctx, conn.Banned)
+	conns[2].SetState(ctx, conn.Banned)
+	require.Equal(t, map[string]bool{"a/r1/1": true}, addresses())
+
+	conns[0].SetState(ctx, conn.Banned)
+	require.Equal(t, map[string]bool{"b/r1/1": true, "b/r2/1": true}, addresses())
+
+	t.Run("WithoutSelfLocality", func(t *testing.T) {
+		s := newConnectionsState(conns, local, balancerConfig.Info{SelfLocation: "a"}, true, withLocality(locality))
+		require.Nil(t, s.prefers)
+		require.Nil(t, s.fallbacks)
+	})
+}
+
+func TestGroupByLocality(t *testing.T) {
+	conns := []conn.Conn{
+		&mock.Conn{AddrField: "a/r1/1"},
+		&mock.Conn{AddrField: "b/r2/1"},
+		&mock.Conn{AddrField: "a/r2/1"},
+		&mock.Conn{AddrField: "a/r2/2"},
+		&mock.Conn{AddrField: "b/r1/1"},
+	}
+	locality := func(e endpoint.Info) []string {
+		return strings.Split(e.Address(), "/")[:2]
+	}
+	require.Equal(t, [][]conn.Conn{
+		{conns[2], conns[3]},
+		{conns[0]},
+		{conns[1], conns[4]},
+	}, groupByLocality([][]conn.Conn{conns}, locality, []string{"a", "r2"}))
+	require.Equal(t, [][]conn.Conn{
+		{conns[0]},
+		{conns[2]},
+		{conns[1]},
+	}, groupByLocality([][]conn.Conn{conns[:3]}, locality, []string{"a", "r1"}))
+}
+
+func TestLocalityLevel(t *testing.T) {
+	for _, tt := range []struct {
+		path []string
+		self []string
+		exp  int
+	}{
+		{nil, []string{"a", "r1"}, 0},
+		{[]string{"a", "r1"}, nil, 0},
+		{[]string{"b", "r1"}, []string{"a", "r1"}, 0},
+		{[]string{"a", "r2"}, []string{"a", "r1"}, 1},
+		{[]string{"a", "r1"}, []string{"a", "r1"}, 2},
+		{[]string{"a"}, []string{"a", "r1"}, 1},
+	} {
+		t.Run("", func(t *testing.T) {
+			require.Equal(t, tt.exp, localityLevel(tt.path, tt.self))
+		})
+	}
+}
+
+func TestNewEndpointBias(t *testing.T) {
+	ctx := context.Background()
+	warm := []conn.Conn{
+		&mock.Conn{AddrField: "1", NodeIDField: 1, State: conn.Online},
+		&mock.Conn{AddrField: "2", NodeIDField: 2, State: conn.Online},
+	}
+	cold := &mock.Conn{AddrField: "3", NodeIDField: 3, State: conn.Created}
+	newState := func(clock clockwork.FakeClock, bias balancerConfig.NewEndpointBias) *connectionsState {
+		previous := newConnectionsState(warm, nil, balancerConfig.Info{}, false,
+			withEntryTimes(nil, clock),
+			withNewEndpointBias(bias, time.Minute),
+		)
+		require.Nil(t, previous.biasedPrefers)
+		clock.Advance(time.Second)
+
+		return newConnectionsState(append([]conn.Conn{cold}, warm...), nil, balancerConfig.Info{}, false,
+			withEntryTimes(previous.entryTimes(), clock),
+			withNewEndpointBias(bias, time.Minute),
+		)
+	}
+	chosen := func(s *connectionsState) map[string]int {
+		counts := make(map[string]int)
+		for i := 0; i < 100; i++ {
+			c, _ := s.GetConnection(ctx)
+			require.NotNil(t, c)
+			counts[c.Endpoint().Address()]++
+		}
+
+		return counts
+	}
+
+	t.Run("EntryTimes", func(t *testing.T) {
+		clock := clockwork.NewFakeClock()
+		start := clock.Now()
+		s := newState(clock, balancerConfig.NoNewEndpointBias)
+		require.Equal(t, map[string]time.Time{
+			"1": start,
+			"2": start,
+			"3": start.Add(time.Second),
+		}, s.entryTimes())
+		require.Nil(t, s.biasedPrefers)
+	})
+
+	t.Run("WarmFirst", func(t *testing.T) {
+		clock := clockwork.NewFakeClock()
+		s := newState(clock, balancerConfig.WarmFirst)
+		require.Zero(t, chosen(s)["3"])
+
+		clock.Advance(time.Minute)
+		require.NotZero(t, chosen(s)["3"])
+	})
+
+	t.Run("ColdFirst", func(t *testing.T) {
+		clock := clockwork.NewFakeClock()
+		s := newState(clock, balancerConfig.ColdFirst)
+		require.Equal(t, map[string]int{"3": 100}, chosen(s))
+
+		clock.Advance(time.Minute)
+		require.Less(t, chosen(s)["3"], 100)
+	})
+}
@@ -0,0 +1,44 @@
+package balancer
+
+import (
+	"context"
+
+	balancerConfig "github.com/ydb-platform/ydb-go-sdk/v3/internal/balancer/config"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/endpoint"
+)
+
+// applyDiscoveryCache seeds balancer state from discovery cache.
+// Returns false if cache is not configured or cannot be used
+func (b *Balancer) applyDiscoveryCache(ctx context.Context) bool {
+	if b.config.DiscoveryCache == nil {
+		return false
+	}
+
+	endpoints, err := b.config.DiscoveryCache.Load()
+	if err != nil || len(endpoints) == 0 {
+		return false
+	}
+
+	var info balancerConfig.Info
+	if b.config.DetectNearestDC {
+		info, err = b.detectLocalDC(ctx, ctx, endpoints)
+		if err != nil {
+			return false
+		}
+	}
+
+	b.applyDiscoveredEndpoints(ctx, endpoints, info)
+	b.setFromCache()
+
+	return true
+}
+
+// saveDiscoveryCache saves fresh discovery result to discovery cache
+func (b *Balancer) saveDiscoveryCache(endpoints []endpoint.Endpoint) {
+	if b.config.DiscoveryCache == nil {
+		return
+	}
+
+	// cache is a best-effort optimization of cold start, fails of save are not fatal
+	_ = b.config.DiscoveryCache.Save(endpoints)
+}
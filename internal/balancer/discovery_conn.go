@@ -0,0 +1,123 @@
+package balancer
+
+import (
+	"context"
+	"sync"
+
+	"google.golang.org/grpc"
+	grpcCodes "google.golang.org/grpc/codes"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/config"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xerrors"
+)
+
+// closableClientConn is a dialed gRPC connection
+type closableClientConn interface {
+	grpc.ClientConnInterface
+
+	Close() error
+}
+
+// persistentDiscoveryConn is a dedicated connection of discovery which dials lazily once and reuses across
+// discovery cycles. Connection redials on next call after connection-level transport error
+type persistentDiscoveryConn struct {
+	dial func(ctx context.Context) (closableClientConn, error)
+
+	mu sync.Mutex
+	cc closableClientConn
+}
+
+func newPersistentDiscoveryConn(driverConfig *config.Config, address string) *persistentDiscoveryConn {
+	return &persistentDiscoveryConn{
+		dial: func(ctx context.Context) (closableClientConn, error) {
+			return grpc.DialContext(ctx, //nolint:staticcheck,nolintlint
+				driverConfig.DialTarget(address), driverConfig.GrpcDialOptions()...,
+			)
+		},
+	}
+}
+
+func (c *persistentDiscoveryConn) conn(ctx context.Context) (closableClientConn, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.cc != nil {
+		return c.cc, nil
+	}
+
+	cc, err := c.dial(ctx)
+	if err != nil {
+		return nil, xerrors.WithStackTrace(xerrors.Transport(err))
+	}
+	c.cc = cc
+
+	return cc, nil
+}
+
+// reset closes connection after connection-level transport error (such as refused or broken connection),
+// so next call redials connection. Errors of call on alive connection (such as PermissionDenied
+// or Unauthenticated) keeps connection
+func (c *persistentDiscoveryConn) reset(cc closableClientConn, err error) {
+	if !xerrors.IsTransportError(err, grpcCodes.Unavailable, grpcCodes.Internal) {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.cc == cc {
+		c.cc = nil
+		_ = cc.Close()
+	}
+}
+
+func (c *persistentDiscoveryConn) Invoke(
+	ctx context.Context, method string, args interface{}, reply interface{}, opts ...grpc.CallOption,
+) error {
+	cc, err := c.conn(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = cc.Invoke(ctx, method, args, reply, opts...)
+	if err != nil {
+		c.reset(cc, err)
+
+		return xerrors.WithStackTrace(err)
+	}
+
+	return nil
+}
+
+func (c *persistentDiscoveryConn) NewStream(
+	ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption,
+) (grpc.ClientStream, error) {
+	cc, err := c.conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	stream, err := cc.NewStream(ctx, desc, method, opts...)
+	if err != nil {
+		c.reset(cc, err)
+
+		return nil, xerrors.WithStackTrace(err)
+	}
+
+	return stream, nil
+}
+
+// Close closes connection. Discovery client closes connection on close of balancer
+func (c *persistentDiscoveryConn) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.cc == nil {
+		return nil
+	}
+
+	cc := c.cc
+	c.cc = nil
+
+	return cc.Close()
+}
@@ -0,0 +1,75 @@
+package balancer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	grpcCodes "google.golang.org/grpc/codes"
+	grpcStatus "google.golang.org/grpc/status"
+)
+
+type clientConnMock struct {
+	grpc.ClientConnInterface
+
+	err    error
+	closed bool
+}
+
+func (cc *clientConnMock) Invoke(context.Context, string, interface{}, interface{}, ...grpc.CallOption) error {
+	return cc.err
+}
+
+func (cc *clientConnMock) Close() error {
+	cc.closed = true
+
+	return nil
+}
+
+func TestPersistentDiscoveryConn(t *testing.T) {
+	ctx := context.Background()
+	var dialed []*clientConnMock
+	c := &persistentDiscoveryConn{
+		dial: func(ctx context.Context) (closableClientConn, error) {
+			cc := &clientConnMock{}
+			dialed = append(dialed, cc)
+
+			return cc, nil
+		},
+	}
+
+	require.NoError(t, c.Invoke(ctx, "", nil, nil))
+	require.NoError(t, c.Invoke(ctx, "", nil, nil))
+	require.Len(t, dialed, 1)
+
+	// operation error keeps connection
+	dialed[0].err = errors.New("operation error")
+	require.Error(t, c.Invoke(ctx, "", nil, nil))
+	require.Len(t, dialed, 1)
+	require.False(t, dialed[0].closed)
+
+	// transport error of call on alive connection keeps connection
+	for _, code := range []grpcCodes.Code{
+		grpcCodes.PermissionDenied,
+		grpcCodes.Unauthenticated,
+		grpcCodes.DeadlineExceeded,
+	} {
+		dialed[0].err = grpcStatus.Error(code, "")
+		require.Error(t, c.Invoke(ctx, "", nil, nil))
+		require.Len(t, dialed, 1)
+		require.False(t, dialed[0].closed)
+	}
+
+	// connection-level transport error closes connection and next call redials connection
+	dialed[0].err = grpcStatus.Error(grpcCodes.Unavailable, "")
+	require.Error(t, c.Invoke(ctx, "", nil, nil))
+	require.True(t, dialed[0].closed)
+	require.NoError(t, c.Invoke(ctx, "", nil, nil))
+	require.Len(t, dialed, 2)
+
+	require.NoError(t, c.Close())
+	require.True(t, dialed[1].closed)
+	require.NoError(t, c.Close())
+}
@@ -0,0 +1,67 @@
+package balancer
+
+import (
+	"sync"
+	"time"
+)
+
+// DiscoveryEvent is a record of applied discovery result
+type DiscoveryEvent struct {
+	// Time is a time of apply of discovery result
+	Time time.Time
+	// Endpoints is a count of applied endpoints
+	Endpoints int
+	// LocalDC is a detected local DC (empty if nearest DC not detected)
+	LocalDC string
+	// Forced is true if discovery was forced (such as on many banned endpoints)
+	Forced bool
+	// Added is addresses of endpoints which appeared in discovery result
+	Added []string
+	// Dropped is addresses of endpoints which disappeared from discovery result
+	Dropped []string
+}
+
+// discoveryHistory is a ring buffer of last applied discovery results
+type discoveryHistory struct {
+	mu     sync.Mutex
+	events []DiscoveryEvent
+	next   int
+	full   bool
+}
+
+func newDiscoveryHistory(size int) *discoveryHistory {
+	return &discoveryHistory{
+		events: make([]DiscoveryEvent, size),
+	}
+}
+
+func (h *discoveryHistory) add(event DiscoveryEvent) {
+	if h == nil {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.events[h.next] = event
+	h.next = (h.next + 1) % len(h.events)
+	if h.next == 0 {
+		h.full = true
+	}
+}
+
+// list returns events from oldest to newest
+func (h *discoveryHistory) list() []DiscoveryEvent {
+	if h == nil {
+		return nil
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !h.full {
+		return append([]DiscoveryEvent(nil), h.events[:h.next]...)
+	}
+
+	return append(append(make([]DiscoveryEvent, 0, len(h.events)), h.events[h.next:]...), h.events[:h.next]...)
+}
@@ -0,0 +1,75 @@
+package balancer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/config"
+	balancerConfig "github.com/ydb-platform/ydb-go-sdk/v3/internal/balancer/config"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/conn"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/endpoint"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/mock"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/repeater"
+)
+
+func TestDiscoveryHistory(t *testing.T) {
+	ctx := context.Background()
+	clock := clockwork.NewFakeClock()
+	cfg := config.New()
+	b := &Balancer{
+		driverConfig:     cfg,
+		config:           *(&balancerConfig.Config{}).With(balancerConfig.WithClock(clock)),
+		pool:             conn.NewPool(ctx, cfg),
+		discoveryHistory: newDiscoveryHistory(2),
+	}
+
+	b.applyDiscoveredEndpoints(ctx, []endpoint.Endpoint{
+		&mock.Endpoint{AddrField: "a:123"},
+	}, balancerConfig.Info{})
+	clock.Advance(time.Second)
+	b.applyDiscoveredEndpoints(repeater.WithEvent(ctx, repeater.EventForce), []endpoint.Endpoint{
+		&mock.Endpoint{AddrField: "a:123"},
+		&mock.Endpoint{AddrField: "b:234"},
+	}, balancerConfig.Info{SelfLocation: "dc"})
+	clock.Advance(time.Second)
+	b.applyDiscoveredEndpoints(ctx, []endpoint.Endpoint{
+		&mock.Endpoint{AddrField: "b:234"},
+	}, balancerConfig.Info{SelfLocation: "dc"})
+
+	history := b.DiscoveryHistory()
+	require.Len(t, history, 2)
+	require.Equal(t, DiscoveryEvent{
+		Time:      clock.Now().Add(-time.Second),
+		Endpoints: 2,
+		LocalDC:   "dc",
+		Forced:    true,
+		Added:     []string{"b:234"},
+		Dropped:   []string{},
+	}, history[0])
+	require.Equal(t, DiscoveryEvent{
+		Time:      clock.Now(),
+		Endpoints: 1,
+		LocalDC:   "dc",
+		Added:     []string{},
+		Dropped:   []string{"a:123"},
+	}, history[1])
+
+	t.Run("Disabled", func(t *testing.T) {
+		require.Empty(t, (&Balancer{}).DiscoveryHistory())
+	})
+}
+
+func TestDiscoveryHistoryRing(t *testing.T) {
+	h := newDiscoveryHistory(3)
+	for i := 1; i <= 5; i++ {
+		h.add(DiscoveryEvent{Endpoints: i})
+		events := h.list()
+		require.Len(t, events, min(i, 3))
+		require.Equal(t, i, events[len(events)-1].Endpoints)
+		require.Equal(t, max(1, i-2), events[0].Endpoints)
+	}
+}
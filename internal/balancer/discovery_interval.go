@@ -0,0 +1,50 @@
+package balancer
+
+import (
+	"time"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/repeater"
+)
+
+const (
+	// discoveryIntervalSpeedUp limits narrowing of discovery interval on forced discovery
+	discoveryIntervalSpeedUp = 4
+	// discoveryIntervalSlowDown limits widening of discovery interval on consecutive discovery errors
+	discoveryIntervalSlowDown = 8
+)
+
+var _ repeater.IntervalStrategy = (*adaptiveDiscoveryInterval)(nil)
+
+// adaptiveDiscoveryInterval widens discovery interval on consecutive discovery errors for prevent
+// hammering of broken control plane and narrows interval on forced discovery (such as many failed connections).
+// Successful discovery by tick returns interval to base value
+type adaptiveDiscoveryInterval struct {
+	base    time.Duration
+	current time.Duration
+}
+
+func newAdaptiveDiscoveryInterval(base time.Duration) *adaptiveDiscoveryInterval {
+	return &adaptiveDiscoveryInterval{
+		base:    base,
+		current: base,
+	}
+}
+
+func (i *adaptiveDiscoveryInterval) Interval(event repeater.Event, err error) time.Duration {
+	switch {
+	case err != nil:
+		i.current *= 2
+		if limit := i.base * discoveryIntervalSlowDown; i.current > limit {
+			i.current = limit
+		}
+	case event == repeater.EventForce:
+		i.current /= 2
+		if limit := i.base / discoveryIntervalSpeedUp; i.current < limit {
+			i.current = limit
+		}
+	default:
+		i.current = i.base
+	}
+
+	return i.current
+}
@@ -0,0 +1,27 @@
+package balancer
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/repeater"
+)
+
+func TestAdaptiveDiscoveryInterval(t *testing.T) {
+	var (
+		i   = newAdaptiveDiscoveryInterval(time.Minute)
+		err = errors.New("test")
+	)
+	require.Equal(t, 2*time.Minute, i.Interval(repeater.EventTick, err))
+	require.Equal(t, 4*time.Minute, i.Interval(repeater.EventForce, err))
+	require.Equal(t, 8*time.Minute, i.Interval(repeater.EventTick, err))
+	require.Equal(t, 8*time.Minute, i.Interval(repeater.EventTick, err))
+	require.Equal(t, time.Minute, i.Interval(repeater.EventTick, nil))
+	require.Equal(t, 30*time.Second, i.Interval(repeater.EventForce, nil))
+	require.Equal(t, 15*time.Second, i.Interval(repeater.EventForce, nil))
+	require.Equal(t, 15*time.Second, i.Interval(repeater.EventForce, nil))
+	require.Equal(t, time.Minute, i.Interval(repeater.EventTick, nil))
+}
@@ -0,0 +1,29 @@
+package balancer
+
+import (
+	"context"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xerrors"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xrand"
+)
+
+// sleepInitialJitter sleeps random duration in [0, jitter) before initial discovery for spread discovery load
+// of many simultaneously started instances. Returns error if context done before end of sleep
+func sleepInitialJitter(ctx context.Context, clock clockwork.Clock, jitter time.Duration) error {
+	if jitter <= 0 {
+		return nil
+	}
+
+	timer := clock.NewTimer(time.Duration(xrand.New().Int64(int64(jitter))))
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return xerrors.WithStackTrace(ctx.Err())
+	case <-timer.Chan():
+		return nil
+	}
+}
@@ -0,0 +1,46 @@
+package balancer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSleepInitialJitter(t *testing.T) {
+	t.Run("Disabled", func(t *testing.T) {
+		require.NoError(t, sleepInitialJitter(context.Background(), clockwork.NewFakeClock(), 0))
+	})
+	t.Run("WithinBounds", func(t *testing.T) {
+		for i := 0; i < 100; i++ {
+			clock := clockwork.NewFakeClock()
+			done := make(chan error, 1)
+			go func() {
+				done <- sleepInitialJitter(context.Background(), clock, time.Second)
+			}()
+			clock.BlockUntil(1)
+			clock.Advance(time.Second)
+			select {
+			case err := <-done:
+				require.NoError(t, err)
+			case <-time.After(time.Second):
+				t.Fatal("jitter exceeds bound")
+			}
+		}
+	})
+	t.Run("Cancel", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		require.ErrorIs(t, sleepInitialJitter(ctx, clockwork.NewFakeClock(), time.Hour), context.Canceled)
+	})
+	t.Run("Deadline", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		err := sleepInitialJitter(ctx, clockwork.NewRealClock(), time.Hour)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.Less(t, time.Since(start), time.Second)
+	})
+}
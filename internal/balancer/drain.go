@@ -0,0 +1,112 @@
+package balancer
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/conn"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/endpoint"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xcontext"
+)
+
+// drainer keeps connections to endpoints removed from discovery response for grace period.
+// Draining connections not used for new calls, existing calls (such as streams) continues until timeout.
+// After timeout drainer closes connections with pool
+type drainer struct {
+	pool    *conn.Pool
+	timeout time.Duration
+	clock   clockwork.Clock
+
+	mu       sync.Mutex
+	draining map[string]chan struct{}
+}
+
+func newDrainer(pool *conn.Pool, timeout time.Duration, clock clockwork.Clock) *drainer {
+	return &drainer{
+		pool:     pool,
+		timeout:  timeout,
+		clock:    clock,
+		draining: make(map[string]chan struct{}),
+	}
+}
+
+// drain starts draining of dropped connections and stops draining of returned endpoints
+func (d *drainer) drain(ctx context.Context, dropped []conn.Conn, newest []endpoint.Endpoint) {
+	if d == nil {
+		return
+	}
+
+	ctx = xcontext.ValueOnly(ctx)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, e := range newest {
+		if canceled, has := d.draining[e.Address()]; has {
+			close(canceled)
+			delete(d.draining, e.Address())
+		}
+	}
+
+	for _, c := range dropped {
+		address := c.Endpoint().Address()
+		if _, has := d.draining[address]; has {
+			continue
+		}
+		canceled := make(chan struct{})
+		d.draining[address] = canceled
+		go d.closeAfterTimeout(ctx, c, canceled)
+	}
+}
+
+func (d *drainer) closeAfterTimeout(ctx context.Context, c conn.Conn, canceled chan struct{}) {
+	timer := d.clock.NewTimer(d.timeout)
+	defer timer.Stop()
+
+	select {
+	case <-canceled:
+		return
+	case <-timer.Chan():
+	}
+
+	d.mu.Lock()
+	if d.draining[c.Endpoint().Address()] != canceled {
+		// draining canceled while timer fired
+		d.mu.Unlock()
+
+		return
+	}
+	delete(d.draining, c.Endpoint().Address())
+	d.mu.Unlock()
+
+	_ = d.pool.CloseConn(ctx, c)
+}
+
+// drainingCount returns count of draining connections
+func (d *drainer) drainingCount() int {
+	if d == nil {
+		return 0
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return len(d.draining)
+}
+
+func (d *drainer) stop() {
+	if d == nil {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for address, canceled := range d.draining {
+		close(canceled)
+		delete(d.draining, address)
+	}
+}
@@ -0,0 +1,70 @@
+package balancer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/config"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/conn"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/endpoint"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/mock"
+)
+
+func TestDrainer(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.New()
+	t.Run("CloseAfterTimeout", func(t *testing.T) {
+		var (
+			pool  = conn.NewPool(ctx, cfg)
+			clock = clockwork.NewFakeClock()
+			d     = newDrainer(pool, time.Minute, clock)
+			cc    = pool.Get(&mock.Endpoint{AddrField: "a:1"})
+		)
+		d.drain(ctx, []conn.Conn{cc}, nil)
+		require.Equal(t, 1, d.drainingCount())
+
+		clock.BlockUntil(1)
+		clock.Advance(time.Minute - time.Nanosecond)
+		require.NotEqual(t, conn.Destroyed, cc.GetState())
+
+		clock.Advance(time.Nanosecond)
+		require.Eventually(t, func() bool {
+			return cc.GetState() == conn.Destroyed
+		}, time.Second, time.Millisecond)
+		require.Equal(t, 0, d.drainingCount())
+	})
+	t.Run("EndpointReturned", func(t *testing.T) {
+		var (
+			pool  = conn.NewPool(ctx, cfg)
+			clock = clockwork.NewFakeClock()
+			d     = newDrainer(pool, time.Minute, clock)
+			e     = &mock.Endpoint{AddrField: "a:1"}
+			cc    = pool.Get(e)
+		)
+		d.drain(ctx, []conn.Conn{cc}, nil)
+		clock.BlockUntil(1)
+		d.drain(ctx, nil, []endpoint.Endpoint{e})
+		require.Equal(t, 0, d.drainingCount())
+
+		clock.Advance(time.Minute)
+		require.NotEqual(t, conn.Destroyed, cc.GetState())
+	})
+	t.Run("Stop", func(t *testing.T) {
+		var (
+			pool  = conn.NewPool(ctx, cfg)
+			clock = clockwork.NewFakeClock()
+			d     = newDrainer(pool, time.Minute, clock)
+			cc    = pool.Get(&mock.Endpoint{AddrField: "a:1"})
+		)
+		d.drain(ctx, []conn.Conn{cc}, nil)
+		d.stop()
+		require.Equal(t, 0, d.drainingCount())
+
+		clock.Advance(time.Minute)
+		require.NotEqual(t, conn.Destroyed, cc.GetState())
+	})
+}
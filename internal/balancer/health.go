@@ -0,0 +1,48 @@
+package balancer
+
+import (
+	"fmt"
+	"time"
+)
+
+// Healthy reports about degraded state of balancer with reason of degradation.
+// Balancer is degraded if it has no available connections, serves calls from fallback connections outside
+// local DC, serves calls from cached endpoints (discovery cache, initial state or single connection to initial
+// endpoint) or recent discovery attempts failed
+func (b *Balancer) Healthy() (healthy bool, reason string) {
+	state := b.connections()
+	if !state.hasOkConnection() {
+		return false, "no available connections"
+	}
+
+	var (
+		fromCache         bool
+		discoveryFailures int
+		lastDiscovery     time.Time
+	)
+	b.mu.WithRLock(func() {
+		fromCache = b.fromCache
+		discoveryFailures = b.discoveryFailures
+		lastDiscovery = b.lastDiscovery
+	})
+
+	switch {
+	case fromCache:
+		return false, "serving from cached endpoints, no one discovery succeeded yet"
+	case b.inFallback.Load():
+		return false, fmt.Sprintf("serving from fallback connections outside local DC %q", state.localDC)
+	case discoveryFailures > 0:
+		return false, fmt.Sprintf("%d recent discovery attempts failed, last successful discovery at %s",
+			discoveryFailures, lastDiscovery.Format(time.RFC3339),
+		)
+	default:
+		return true, ""
+	}
+}
+
+// setFromCache marks active balancer state as built from cached endpoints instead of fresh discovery
+func (b *Balancer) setFromCache() {
+	b.mu.WithLock(func() {
+		b.fromCache = true
+	})
+}
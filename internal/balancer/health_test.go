@@ -0,0 +1,62 @@
+package balancer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/balancers"
+	"github.com/ydb-platform/ydb-go-sdk/v3/config"
+	balancerConfig "github.com/ydb-platform/ydb-go-sdk/v3/internal/balancer/config"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/conn"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/endpoint"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/mock"
+)
+
+func TestHealthy(t *testing.T) {
+	ctx := context.Background()
+	endpoints := []endpoint.Endpoint{
+		&mock.Endpoint{AddrField: "a:123", NodeIDField: 1},
+	}
+	cache := &discoveryCacheMock{endpoints: endpoints}
+	cfg := config.New(
+		config.WithBalancer(balancers.Default().With(balancerConfig.WithDiscoveryCache(cache))),
+	)
+	b := &Balancer{
+		driverConfig:    cfg,
+		config:          *cfg.Balancer(),
+		pool:            conn.NewPool(ctx, cfg),
+		discoveryClient: discoveryErrorMock{err: errors.New("test")},
+	}
+
+	healthy, reason := b.Healthy()
+	require.False(t, healthy)
+	require.Equal(t, "no available connections", reason)
+
+	require.True(t, b.applyDiscoveryCache(ctx))
+	healthy, reason = b.Healthy()
+	require.False(t, healthy)
+	require.Contains(t, reason, "cached endpoints")
+
+	b.discoveryClient = discoveryMock{endpoints: endpoints}
+	require.NoError(t, b.clusterDiscoveryAttempt(ctx))
+	healthy, reason = b.Healthy()
+	require.True(t, healthy)
+	require.Empty(t, reason)
+
+	b.discoveryClient = discoveryErrorMock{err: errors.New("test")}
+	require.Error(t, b.clusterDiscoveryAttempt(ctx))
+	require.Error(t, b.clusterDiscoveryAttempt(ctx))
+	healthy, reason = b.Healthy()
+	require.False(t, healthy)
+	require.Contains(t, reason, "2 recent discovery attempts failed")
+
+	b.discoveryClient = discoveryMock{endpoints: endpoints}
+	require.NoError(t, b.clusterDiscoveryAttempt(ctx))
+	b.inFallback.Store(true)
+	healthy, reason = b.Healthy()
+	require.False(t, healthy)
+	require.Contains(t, reason, "fallback connections")
+}
@@ -0,0 +1,105 @@
+package balancer
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/conn"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/endpoint"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xcontext"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xerrors"
+)
+
+type hedgedResult struct {
+	cc    conn.Conn
+	reply proto.Message
+	err   error
+}
+
+// invokeHedged invokes call on connection and hedged duplicate of call on other connection
+// if first call not completed within delay. Result of first successful call returns, failed call wins only
+// if no other call in-flight. Other call cancels and awaits before return, so in-flight call not outlives
+// registration in b.calls. Every call writes into own copy of reply, so canceled call not touch reply of winner.
+// Every completed call pessimizes endpoint and reports outcome to circuit breaker, canceled call not pessimizes
+// endpoint. Returns info of endpoint of winner call
+func (b *Balancer) invokeHedged(
+	ctx context.Context,
+	delay time.Duration,
+	reply proto.Message,
+	invoke func(ctx context.Context, cc conn.Conn, reply proto.Message) error,
+) (_ endpoint.Info, err error) {
+	if !b.calls.add() {
+		return nil, xerrors.WithStackTrace(ErrClosed)
+	}
+	defer b.calls.done()
+
+	first, err := b.getConn(ctx)
+	if err != nil {
+		return nil, xerrors.WithStackTrace(err)
+	}
+
+	callCtx, cancel := xcontext.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		results  = make(chan hedgedResult, 2)
+		started  int
+		received int
+	)
+	start := func(cc conn.Conn) {
+		started++
+		b.circuitBreakers.onCall(ctx, cc)
+		r := proto.Clone(reply)
+		go func() {
+			err := b.call(callCtx, cc, func(ctx context.Context, cc conn.Conn) error {
+				return invoke(ctx, cc, r)
+			})
+			results <- hedgedResult{cc: cc, reply: r, err: err}
+		}()
+	}
+
+	start(first)
+
+	timer := b.clock().NewTimer(delay)
+	defer timer.Stop()
+
+	var (
+		winner hedgedResult
+		hedge  = timer.Chan()
+	)
+	for winner.cc == nil {
+		select {
+		case r := <-results:
+			received++
+			if r.err == nil || received == started {
+				winner = r
+			} else {
+				b.onCallDone(ctx, r.cc, r.err)
+			}
+		case <-hedge:
+			hedge = nil
+			if second := b.connections().otherConnection(ctx, first); second != nil {
+				start(second)
+			}
+		}
+	}
+
+	cancel()
+	for ; received < started; received++ {
+		r := <-results
+		b.circuitBreakers.onCancel(ctx, r.cc)
+	}
+
+	b.onCallDone(ctx, winner.cc, winner.err)
+
+	if winner.err != nil {
+		return winner.cc.Endpoint(), winner.err
+	}
+
+	proto.Reset(reply)
+	proto.Merge(reply, winner.reply)
+
+	return winner.cc.Endpoint(), nil
+}
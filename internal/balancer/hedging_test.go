@@ -0,0 +1,176 @@
+package balancer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	grpcCodes "google.golang.org/grpc/codes"
+	grpcStatus "google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/config"
+	balancerConfig "github.com/ydb-platform/ydb-go-sdk/v3/internal/balancer/config"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/conn"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/endpoint"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/mock"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xcontext"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xerrors"
+	"github.com/ydb-platform/ydb-go-sdk/v3/trace"
+)
+
+type hedgingConn struct {
+	*mock.Conn
+
+	invoke func(ctx context.Context, reply *wrapperspb.StringValue) error
+}
+
+func (c *hedgingConn) Invoke(
+	ctx context.Context,
+	method string,
+	args interface{},
+	reply interface{},
+	opts ...grpc.CallOption,
+) error {
+	return c.invoke(ctx, reply.(*wrapperspb.StringValue))
+}
+
+func TestInvokeHedged(t *testing.T) {
+	ctx := xcontext.WithIdempotent(
+		conn.WithHedging(endpoint.WithNodeID(context.Background(), 1), 10*time.Millisecond), true,
+	)
+	canceled := make(chan error, 1)
+	slow := &hedgingConn{
+		Conn: &mock.Conn{AddrField: "slow", NodeIDField: 1, State: conn.Online},
+		invoke: func(ctx context.Context, reply *wrapperspb.StringValue) error {
+			<-ctx.Done()
+			reply.Value = "slow"
+			canceled <- ctx.Err()
+
+			return xerrors.Transport(grpcStatus.Error(grpcCodes.Unavailable, ""))
+		},
+	}
+	fast := &hedgingConn{
+		Conn: &mock.Conn{AddrField: "fast", NodeIDField: 2, State: conn.Online},
+		invoke: func(ctx context.Context, reply *wrapperspb.StringValue) error {
+			reply.Value = "fast"
+
+			return nil
+		},
+	}
+	cfg := config.New()
+	b := &Balancer{
+		driverConfig: cfg,
+		pool:         conn.NewPool(context.Background(), cfg),
+	}
+	b.connectionsState.Store(newConnectionsState([]conn.Conn{slow, fast}, nil, balancerConfig.Info{}, false))
+
+	var banned int
+	b.OnBan(func(ctx context.Context, e trace.EndpointInfo, err error) {
+		banned++
+	})
+
+	reply := &wrapperspb.StringValue{}
+	info, err := b.InvokeWithInfo(ctx, "test", nil, reply)
+	require.NoError(t, err)
+	require.Equal(t, "fast", info.Address())
+	require.Equal(t, "fast", reply.GetValue())
+	require.ErrorIs(t, <-canceled, context.Canceled)
+	require.Zero(t, banned)
+
+	t.Run("NotIdempotent", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(xcontext.WithIdempotent(ctx, false), 50*time.Millisecond)
+		defer cancel()
+		err := b.Invoke(ctx, "test", nil, &wrapperspb.StringValue{})
+		require.Error(t, err)
+		require.ErrorIs(t, <-canceled, context.DeadlineExceeded)
+	})
+
+	t.Run("FirstErrorWhileHedgePending", func(t *testing.T) {
+		slow.invoke = func(ctx context.Context, reply *wrapperspb.StringValue) error {
+			time.Sleep(20 * time.Millisecond)
+
+			return errors.New("slow")
+		}
+		fast.invoke = func(ctx context.Context, reply *wrapperspb.StringValue) error {
+			time.Sleep(50 * time.Millisecond)
+			reply.Value = "fast"
+
+			return nil
+		}
+		reply := &wrapperspb.StringValue{}
+		info, err := b.InvokeWithInfo(ctx, "test", nil, reply)
+		require.NoError(t, err)
+		require.Equal(t, "fast", info.Address())
+		require.Equal(t, "fast", reply.GetValue())
+	})
+
+	t.Run("AllFailed", func(t *testing.T) {
+		slow.invoke = func(ctx context.Context, reply *wrapperspb.StringValue) error {
+			time.Sleep(20 * time.Millisecond)
+			reply.Value = "slow"
+
+			return errors.New("slow")
+		}
+		fast.invoke = func(ctx context.Context, reply *wrapperspb.StringValue) error {
+			reply.Value = "fast"
+
+			return errors.New("fast")
+		}
+		reply := &wrapperspb.StringValue{Value: "initial"}
+		info, err := b.InvokeWithInfo(ctx, "test", nil, reply)
+		require.ErrorContains(t, err, "slow")
+		require.Equal(t, "slow", info.Address())
+		require.Equal(t, "initial", reply.GetValue())
+	})
+}
+
+func TestInvokeHedgedCircuitBreakers(t *testing.T) {
+	ctx := xcontext.WithIdempotent(
+		conn.WithHedging(endpoint.WithNodeID(context.Background(), 1), 10*time.Millisecond), true,
+	)
+	slow := &hedgingConn{
+		Conn: &mock.Conn{AddrField: "slow", NodeIDField: 1, State: conn.Online},
+		invoke: func(ctx context.Context, reply *wrapperspb.StringValue) error {
+			<-ctx.Done()
+
+			return ctx.Err()
+		},
+	}
+	fast := &hedgingConn{
+		Conn: &mock.Conn{AddrField: "fast", NodeIDField: 2, State: conn.Online},
+		invoke: func(ctx context.Context, reply *wrapperspb.StringValue) error {
+			reply.Value = "fast"
+
+			return nil
+		},
+	}
+	cb := newCircuitBreakers(balancerConfig.CircuitBreaker{
+		Threshold: 1,
+		Window:    time.Minute,
+	}, clockwork.NewRealClock(), &trace.Driver{})
+	cb.onFailure(ctx, slow)
+	cb.onFailure(ctx, fast)
+
+	cfg := config.New()
+	b := &Balancer{
+		driverConfig:    cfg,
+		pool:            conn.NewPool(context.Background(), cfg),
+		circuitBreakers: cb,
+	}
+	b.connectionsState.Store(newConnectionsState([]conn.Conn{slow, fast}, nil, balancerConfig.Info{}, false,
+		withCircuitBreakers(cb),
+	))
+
+	info, err := b.InvokeWithInfo(ctx, "test", nil, &wrapperspb.StringValue{})
+	require.NoError(t, err)
+	require.Equal(t, "fast", info.Address())
+	require.Equal(t, map[string]CircuitBreakerState{
+		"slow": CircuitBreakerOpen,
+		"fast": CircuitBreakerClosed,
+	}, b.CircuitBreakerStates())
+}
@@ -7,19 +7,43 @@ import (
 	"math/rand"
 	"net"
 	"net/url"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/endpoint"
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xcontext"
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xerrors"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xslices"
 )
 
 const (
 	maxEndpointsCheckPerLocation = 5
+
+	defaultLatencyProbeCount   = 3
+	defaultLatencyProbeTimeout = time.Second
 )
 
-func checkFastestAddress(ctx context.Context, addresses []string) string {
+// probeDial dials tcp connection for probe of endpoint. Zero timeout means no timeout
+var probeDial = func(ctx context.Context, address string, timeout time.Duration) (net.Conn, error) {
+	dialer := net.Dialer{Timeout: timeout}
+
+	return dialer.DialContext(ctx, "tcp", address)
+}
+
+// probeWorkers returns count of simultaneous probes for count of tasks. Zero concurrency means no limit
+func probeWorkers(concurrency, tasks int) int {
+	if concurrency <= 0 || concurrency > tasks {
+		return tasks
+	}
+
+	return concurrency
+}
+
+// checkFastestAddress returns first address which accepts tcp connection.
+// Addresses dials in order of slice with up to concurrency simultaneous dials
+func checkFastestAddress(ctx context.Context, addresses []string, concurrency int) string {
 	ctx, cancel := xcontext.WithCancel(ctx)
 	defer cancel()
 
@@ -28,26 +52,32 @@ func checkFastestAddress(ctx context.Context, addresses []string) string {
 		err     error
 	}
 	results := make(chan result, len(addresses))
-	defer close(results)
+
+	queue := make(chan string, len(addresses))
+	for _, address := range addresses {
+		queue <- address
+	}
+	close(queue)
 
 	startDial := make(chan struct{})
-	var dialer net.Dialer
 
 	var wg sync.WaitGroup
 	defer wg.Wait()
 
-	for _, addr := range addresses {
+	for i := probeWorkers(concurrency, len(addresses)); i > 0; i-- {
 		wg.Add(1)
-		go func(address string) {
+		go func() {
 			defer wg.Done()
 			<-startDial
-			conn, err := dialer.DialContext(ctx, "tcp", address)
-			if err == nil {
-				cancel()
-				_ = conn.Close()
+			for address := range queue {
+				conn, err := probeDial(ctx, address, 0)
+				if err == nil {
+					cancel()
+					_ = conn.Close()
+				}
+				results <- result{address: address, err: err}
 			}
-			results <- result{address: address, err: err}
-		}(addr)
+		}()
 	}
 
 	close(startDial)
@@ -62,7 +92,9 @@ func checkFastestAddress(ctx context.Context, addresses []string) string {
 	return ""
 }
 
-func detectFastestEndpoint(ctx context.Context, endpoints []endpoint.Endpoint) (endpoint.Endpoint, error) {
+func detectFastestEndpoint(
+	ctx context.Context, endpoints []endpoint.Endpoint, concurrency int,
+) (endpoint.Endpoint, error) {
 	if len(endpoints) == 0 {
 		return nil, xerrors.WithStackTrace(errors.New("empty endpoints list"))
 	}
@@ -71,6 +103,8 @@ func detectFastestEndpoint(ctx context.Context, endpoints []endpoint.Endpoint) (
 	// common is 2 ip address for every fqdn: ipv4 + ipv6
 	initialAddressToEndpointCapacity := len(endpoints) * 2 //nolint:gomnd
 	addressToEndpoint := make(map[string]endpoint.Endpoint, initialAddressToEndpointCapacity)
+	// addressesToPing keeps order of endpoints for fair dial of endpoints with limited concurrency
+	addressesToPing := make([]string, 0, initialAddressToEndpointCapacity)
 	for _, ep := range endpoints {
 		host, port, err := extractHostPort(ep.Address())
 		if err != nil {
@@ -93,18 +127,17 @@ func detectFastestEndpoint(ctx context.Context, endpoints []endpoint.Endpoint) (
 
 		for _, ip := range addresses {
 			address := net.JoinHostPort(ip, port)
+			if _, has := addressToEndpoint[address]; !has {
+				addressesToPing = append(addressesToPing, address)
+			}
 			addressToEndpoint[address] = ep
 		}
 	}
 	if len(addressToEndpoint) == 0 {
 		return nil, xerrors.WithStackTrace(lastErr)
 	}
-	addressesToPing := make([]string, 0, len(addressToEndpoint))
-	for ip := range addressToEndpoint {
-		addressesToPing = append(addressesToPing, ip)
-	}
 
-	fastestAddress := checkFastestAddress(ctx, addressesToPing)
+	fastestAddress := checkFastestAddress(ctx, addressesToPing, concurrency)
 	if fastestAddress == "" {
 		return nil, xerrors.WithStackTrace(errors.New("failed to check fastest address"))
 	}
@@ -113,26 +146,172 @@ func detectFastestEndpoint(ctx context.Context, endpoints []endpoint.Endpoint) (
 }
 
 func detectLocalDC(ctx context.Context, endpoints []endpoint.Endpoint) (string, error) {
-	if len(endpoints) == 0 {
-		return "", xerrors.WithStackTrace(ErrNoEndpoints)
+	return detectLocalDCByFastestDial(0, 0)(ctx, endpoints)
+}
+
+// detectLocalDCByFastestDial returns detector which dials sample of endpoints in each location
+// with up to concurrency simultaneous dials and choose location of first connected endpoint
+func detectLocalDCByFastestDial(
+	concurrency, endpointsPerDC int,
+) func(ctx context.Context, endpoints []endpoint.Endpoint) (string, error) {
+	return func(ctx context.Context, endpoints []endpoint.Endpoint) (string, error) {
+		if len(endpoints) == 0 {
+			return "", xerrors.WithStackTrace(ErrNoEndpoints)
+		}
+		endpointsByDc := splitEndpointsByLocation(endpoints)
+
+		if len(endpointsByDc) == 1 {
+			return endpoints[0].Location(), nil
+		}
+
+		fastest, err := detectFastestEndpoint(ctx, sampleEndpoints(endpointsByDc, endpointsPerDC), concurrency)
+		if err == nil {
+			return fastest.Location(), nil
+		}
+
+		return "", err
+	}
+}
+
+// detectLocalDCByLatency returns detector which probes sample of endpoints in each location
+// with up to concurrency simultaneous probes and choose location with lowest median round-trip time
+func detectLocalDCByLatency(
+	probeCount int, probeTimeout time.Duration, concurrency, endpointsPerDC int,
+) func(ctx context.Context, endpoints []endpoint.Endpoint) (string, error) {
+	if probeCount <= 0 {
+		probeCount = defaultLatencyProbeCount
+	}
+	if probeTimeout <= 0 {
+		probeTimeout = defaultLatencyProbeTimeout
+	}
+
+	return func(ctx context.Context, endpoints []endpoint.Endpoint) (string, error) {
+		if len(endpoints) == 0 {
+			return "", xerrors.WithStackTrace(ErrNoEndpoints)
+		}
+		endpointsByDc := splitEndpointsByLocation(endpoints)
+
+		if len(endpointsByDc) == 1 {
+			return endpoints[0].Location(), nil
+		}
+
+		samples := sampleEndpoints(endpointsByDc, endpointsPerDC)
+		queue := make(chan endpoint.Endpoint, len(samples))
+		for _, e := range samples {
+			queue <- e
+		}
+		close(queue)
+
+		var (
+			wg   sync.WaitGroup
+			mu   sync.Mutex
+			rtts = make(map[string][]time.Duration, len(endpointsByDc))
+		)
+		for i := probeWorkers(concurrency, len(samples)); i > 0; i-- {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for e := range queue {
+					probes := probeLatency(ctx, e.Address(), probeCount, probeTimeout)
+					if len(probes) == 0 {
+						continue
+					}
+					mu.Lock()
+					rtts[e.Location()] = append(rtts[e.Location()], probes...)
+					mu.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+
+		if len(rtts) == 0 {
+			return "", xerrors.WithStackTrace(errors.New("all latency probes failed"))
+		}
+
+		var (
+			nearest string
+			lowest  time.Duration
+		)
+		for location, locationRTTs := range rtts {
+			if rtt := median(locationRTTs); nearest == "" || rtt < lowest || (rtt == lowest && location < nearest) {
+				nearest, lowest = location, rtt
+			}
+		}
+
+		return nearest, nil
+	}
+}
+
+// sampleEndpoints returns up to endpointsPerDC random endpoints of every location. Samples of locations
+// interleaves (first endpoint of every location, then second endpoint of every location and so on),
+// so probes with limited concurrency covers all locations equally. Zero endpointsPerDC means default sample size
+func sampleEndpoints(endpointsByDc map[string][]endpoint.Endpoint, endpointsPerDC int) []endpoint.Endpoint {
+	if endpointsPerDC <= 0 {
+		endpointsPerDC = maxEndpointsCheckPerLocation
+	}
+
+	locations := make([]string, 0, len(endpointsByDc))
+	for location := range endpointsByDc {
+		locations = append(locations, location)
+	}
+	rand.Shuffle(len(locations), func(i, j int) {
+		locations[i], locations[j] = locations[j], locations[i]
+	})
+
+	samples := make([][]endpoint.Endpoint, len(locations))
+	for i, location := range locations {
+		samples[i] = getRandomEndpoints(endpointsByDc[location], endpointsPerDC)
+	}
+
+	res := make([]endpoint.Endpoint, 0, endpointsPerDC*len(locations))
+	for i := 0; i < endpointsPerDC; i++ {
+		for _, sample := range samples {
+			if i < len(sample) {
+				res = append(res, sample[i])
+			}
+		}
 	}
-	endpointsByDc := splitEndpointsByLocation(endpoints)
 
-	if len(endpointsByDc) == 1 {
-		return endpoints[0].Location(), nil
+	return res
+}
+
+// probeLatency returns round-trip times of successful tcp connects to address.
+// Probe dials raw tcp connection without dial options of driver, so round-trip time not includes
+// TLS handshake and not follows custom dialer of driver
+func probeLatency(ctx context.Context, address string, probeCount int, probeTimeout time.Duration) []time.Duration {
+	host, port, err := extractHostPort(address)
+	if err != nil {
+		return nil
 	}
+	address = net.JoinHostPort(host, port)
 
-	endpointsToTest := make([]endpoint.Endpoint, 0, maxEndpointsCheckPerLocation*len(endpointsByDc))
-	for _, dcEndpoints := range endpointsByDc {
-		endpointsToTest = append(endpointsToTest, getRandomEndpoints(dcEndpoints, maxEndpointsCheckPerLocation)...)
+	rtts := make([]time.Duration, 0, probeCount)
+	for i := 0; i < probeCount; i++ {
+		if ctx.Err() != nil {
+			break
+		}
+		start := time.Now()
+		conn, err := probeDial(ctx, address, probeTimeout)
+		if err != nil {
+			continue
+		}
+		rtts = append(rtts, time.Since(start))
+		_ = conn.Close()
 	}
 
-	fastest, err := detectFastestEndpoint(ctx, endpointsToTest)
-	if err == nil {
-		return fastest.Location(), nil
+	return rtts
+}
+
+func median(values []time.Duration) time.Duration {
+	sorted := append(make([]time.Duration, 0, len(values)), values...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+	if n := len(sorted); n%2 == 0 {
+		return (sorted[n/2-1] + sorted[n/2]) / 2 //nolint:gomnd
 	}
 
-	return "", err
+	return sorted[len(sorted)/2]
 }
 
 func extractHostPort(address string) (host, port string, _ error) {
@@ -183,3 +362,17 @@ func splitEndpointsByLocation(endpoints []endpoint.Endpoint) map[string][]endpoi
 
 	return res
 }
+
+// localityEndpoints makes copies of endpoints with locality path as location for detection of nearest locality
+func localityEndpoints(endpoints []endpoint.Endpoint, locality func(e endpoint.Info) []string) []endpoint.Endpoint {
+	return xslices.Transform(endpoints, func(e endpoint.Endpoint) endpoint.Endpoint {
+		return endpoint.New(e.Address(),
+			endpoint.WithID(e.NodeID()),
+			endpoint.WithLocation(localityKey(locality(e))),
+		)
+	})
+}
+
+func localityKey(path []string) string {
+	return strings.Join(path, "/")
+}
@@ -2,17 +2,24 @@ package balancer
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/ydb-platform/ydb-go-sdk/v3/balancers"
 	"github.com/ydb-platform/ydb-go-sdk/v3/config"
+	balancerConfig "github.com/ydb-platform/ydb-go-sdk/v3/internal/balancer/config"
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/conn"
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/endpoint"
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/mock"
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xtest"
+	"github.com/ydb-platform/ydb-go-sdk/v3/trace"
 )
 
 var localIP = net.IPv4(127, 0, 0, 1)
@@ -45,7 +52,7 @@ func TestCheckFastestAddress(t *testing.T) {
 			addr1 := listen1.Addr().String()
 			addr2 := listen2.Addr().String()
 
-			fastest := checkFastestAddress(ctx, []string{addr1, addr2})
+			fastest := checkFastestAddress(ctx, []string{addr1, addr2}, 0)
 			require.NotEmpty(t, fastest)
 
 			switch fastest {
@@ -73,7 +80,7 @@ func TestCheckFastestAddress(t *testing.T) {
 
 		_ = listen2.Close() // for can't accept connections
 
-		fastest := checkFastestAddress(ctx, []string{addr1, addr2})
+		fastest := checkFastestAddress(ctx, []string{addr1, addr2}, 0)
 		require.Equal(t, addr1, fastest)
 
 		_ = listen1.Close()
@@ -89,7 +96,7 @@ func TestCheckFastestAddress(t *testing.T) {
 		_ = listen1.Close() // for can't accept connections
 		_ = listen2.Close() // for can't accept connections
 
-		res := checkFastestAddress(ctx, []string{addr1, addr2})
+		res := checkFastestAddress(ctx, []string{addr1, addr2}, 0)
 		require.Empty(t, res)
 	})
 }
@@ -231,3 +238,391 @@ func TestGetRandomEndpoints(t *testing.T) {
 		require.NotEqual(t, res[0], res[1])
 	})
 }
+
+func TestDetectLocalDCByLatency(t *testing.T) {
+	ctx := context.Background()
+	detect := detectLocalDCByLatency(2, time.Second, 0, 0)
+	t.Run("Ok", func(t *testing.T) {
+		listen1, err := net.ListenTCP("tcp", &net.TCPAddr{IP: localIP})
+		require.NoError(t, err)
+		defer func() { _ = listen1.Close() }()
+
+		listen2, err := net.ListenTCP("tcp", &net.TCPAddr{IP: localIP})
+		require.NoError(t, err)
+		listen2Addr := listen2.Addr().String()
+		_ = listen2.Close() // force close, for not accept tcp connections
+
+		dc, err := detect(ctx, []endpoint.Endpoint{
+			&mock.Endpoint{LocationField: "a", AddrField: "grpc://" + listen1.Addr().String()},
+			&mock.Endpoint{LocationField: "b", AddrField: "grpc://" + listen2Addr},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "a", dc)
+	})
+	t.Run("Empty", func(t *testing.T) {
+		res, err := detect(ctx, nil)
+		require.Equal(t, "", res)
+		require.Error(t, err)
+	})
+	t.Run("OneDC", func(t *testing.T) {
+		res, err := detect(ctx, []endpoint.Endpoint{
+			&mock.Endpoint{LocationField: "a"},
+			&mock.Endpoint{LocationField: "a"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "a", res)
+	})
+	t.Run("AllFailed", func(t *testing.T) {
+		res, err := detect(ctx, []endpoint.Endpoint{
+			&mock.Endpoint{LocationField: "a", AddrField: "wrong"},
+			&mock.Endpoint{LocationField: "b", AddrField: "wrong"},
+		})
+		require.Error(t, err)
+		require.Equal(t, "", res)
+	})
+}
+
+func TestLocalDCProbeConcurrency(t *testing.T) {
+	const (
+		concurrency    = 3
+		endpointsPerDC = 2
+	)
+
+	var (
+		mu      sync.Mutex
+		active  int
+		maxSeen int
+		dialed  map[string]int
+	)
+	locationByAddress := make(map[string]string)
+	endpoints := make([]endpoint.Endpoint, 0, 40)
+	for i, location := range []string{"a", "b", "c", "d"} {
+		for j := 0; j < 10; j++ {
+			address := fmt.Sprintf("127.0.0.1:%d", 10000+i*100+j)
+			locationByAddress[address] = location
+			endpoints = append(endpoints, &mock.Endpoint{AddrField: address, LocationField: location})
+		}
+	}
+
+	originalProbeDial := probeDial
+	defer func() {
+		probeDial = originalProbeDial
+	}()
+	probeDial = func(ctx context.Context, address string, timeout time.Duration) (net.Conn, error) {
+		mu.Lock()
+		active++
+		if active > maxSeen {
+			maxSeen = active
+		}
+		dialed[locationByAddress[address]]++
+		mu.Unlock()
+
+		time.Sleep(time.Millisecond)
+
+		mu.Lock()
+		active--
+		mu.Unlock()
+
+		return nil, errors.New("refused")
+	}
+
+	for _, tt := range []struct {
+		name   string
+		detect func(ctx context.Context, endpoints []endpoint.Endpoint) (string, error)
+		probes int
+	}{
+		{
+			name:   "FastestDial",
+			detect: detectLocalDCByFastestDial(concurrency, endpointsPerDC),
+			probes: 1,
+		},
+		{
+			name:   "LatencyProbe",
+			detect: detectLocalDCByLatency(2, time.Second, concurrency, endpointsPerDC),
+			probes: 2,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			maxSeen, dialed = 0, make(map[string]int)
+
+			_, err := tt.detect(context.Background(), endpoints)
+			require.Error(t, err)
+			require.LessOrEqual(t, maxSeen, concurrency)
+			require.Equal(t, map[string]int{
+				"a": endpointsPerDC * tt.probes,
+				"b": endpointsPerDC * tt.probes,
+				"c": endpointsPerDC * tt.probes,
+				"d": endpointsPerDC * tt.probes,
+			}, dialed)
+		})
+	}
+}
+
+func TestSampleEndpoints(t *testing.T) {
+	endpointsByDc := map[string][]endpoint.Endpoint{
+		"a": {&mock.Endpoint{AddrField: "a1"}, &mock.Endpoint{AddrField: "a2"}, &mock.Endpoint{AddrField: "a3"}},
+		"b": {&mock.Endpoint{AddrField: "b1"}},
+	}
+	samples := sampleEndpoints(endpointsByDc, 2)
+	require.Len(t, samples, 3)
+	// first samples of all locations goes first
+	require.ElementsMatch(t, []byte{'a', 'b'}, []byte{samples[0].Address()[0], samples[1].Address()[0]})
+	require.Equal(t, byte('a'), samples[2].Address()[0])
+}
+
+func TestMedian(t *testing.T) {
+	require.Equal(t, 2*time.Second, median([]time.Duration{3 * time.Second, time.Second, 2 * time.Second}))
+	require.Equal(t, 2*time.Second, median([]time.Duration{3 * time.Second, time.Second}))
+}
+
+func TestLocalDCDetectTimeout(t *testing.T) {
+	ctx := context.Background()
+	newBalancer := func(opts ...balancerConfig.Option) *Balancer {
+		cfg := config.New(
+			config.WithBalancer(balancers.PreferNearestDC(balancers.Default()).With(opts...)),
+		)
+
+		return &Balancer{
+			driverConfig: cfg,
+			config:       *cfg.Balancer(),
+			pool:         conn.NewPool(context.Background(), cfg),
+			discoveryClient: discoveryMock{endpoints: []endpoint.Endpoint{
+				&mock.Endpoint{AddrField: "a:123", LocationField: "a"},
+				&mock.Endpoint{AddrField: "b:234", LocationField: "b"},
+			}},
+			localDCDetector: func(ctx context.Context, endpoints []endpoint.Endpoint) (string, error) {
+				<-ctx.Done()
+
+				return "", ctx.Err()
+			},
+		}
+	}
+
+	t.Run("TimedOut", func(t *testing.T) {
+		b := newBalancer(balancerConfig.WithLocalDCDetectTimeout(10 * time.Millisecond))
+		require.NoError(t, b.clusterDiscoveryAttempt(ctx))
+		require.Len(t, b.connections().All(), 2)
+		require.Empty(t, b.Stats().LocalDC)
+	})
+	t.Run("ParentCanceled", func(t *testing.T) {
+		b := newBalancer(balancerConfig.WithLocalDCDetectTimeout(time.Minute))
+		childCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+		require.ErrorIs(t, b.clusterDiscoveryAttempt(childCtx), context.DeadlineExceeded)
+	})
+}
+
+func TestForcedLocalDC(t *testing.T) {
+	ctx := context.Background()
+	newBalancer := func(opts ...balancerConfig.Option) (*Balancer, *[]trace.DriverBalancerLocalDCNotFoundInfo) {
+		var notFound []trace.DriverBalancerLocalDCNotFoundInfo
+		cfg := config.New(
+			config.WithBalancer(balancers.PreferNearestDC(balancers.Default()).With(opts...)),
+			config.WithTrace(trace.Driver{
+				OnBalancerLocalDCNotFound: func(info trace.DriverBalancerLocalDCNotFoundInfo) {
+					notFound = append(notFound, info)
+				},
+			}),
+		)
+
+		return &Balancer{
+			driverConfig: cfg,
+			config:       *cfg.Balancer(),
+			pool:         conn.NewPool(context.Background(), cfg),
+			discoveryClient: discoveryMock{endpoints: []endpoint.Endpoint{
+				&mock.Endpoint{AddrField: "a:123", LocationField: "a"},
+				&mock.Endpoint{AddrField: "b:234", LocationField: "b"},
+				&mock.Endpoint{AddrField: "b:345", LocationField: "b"},
+			}},
+			localDCDetector: func(ctx context.Context, endpoints []endpoint.Endpoint) (string, error) {
+				t.Fatal("local DC detector called with forced local DC")
+
+				return "", nil
+			},
+		}, &notFound
+	}
+
+	t.Run("Found", func(t *testing.T) {
+		b, notFound := newBalancer(balancerConfig.WithForcedLocalDC("b"))
+		require.NoError(t, b.clusterDiscoveryAttempt(ctx))
+		require.Empty(t, *notFound)
+		require.Equal(t, "b", b.Stats().LocalDC)
+		for i := 0; i < 100; i++ {
+			c, _ := b.connections().GetConnection(ctx)
+			require.Equal(t, "b", c.Endpoint().Location())
+		}
+	})
+	t.Run("NotFound", func(t *testing.T) {
+		b, notFound := newBalancer(balancerConfig.WithForcedLocalDC("c"))
+		require.NoError(t, b.clusterDiscoveryAttempt(ctx))
+		require.Len(t, *notFound, 1)
+		require.Equal(t, "c", (*notFound)[0].LocalDC)
+		require.Equal(t, []string{"a", "b"}, (*notFound)[0].Locations)
+		require.Equal(t, "c", b.Stats().LocalDC)
+		require.Empty(t, b.connections().All())
+	})
+	t.Run("NotFoundWithFallback", func(t *testing.T) {
+		b, notFound := newBalancer(balancerConfig.WithForcedLocalDC("c"), func(c *balancerConfig.Config) {
+			c.AllowFallback = true
+		})
+		require.NoError(t, b.clusterDiscoveryAttempt(ctx))
+		require.Empty(t, *notFound)
+		require.Len(t, b.connections().All(), 3)
+	})
+}
+
+func TestDetectLocality(t *testing.T) {
+	ctx := context.Background()
+	var chosen []trace.DriverBalancerChooseEndpointDoneInfo
+	cfg := config.New(
+		config.WithBalancer(balancers.PreferNearestDC(balancers.Default()).With(
+			balancerConfig.WithLocality(func(e endpoint.Info) []string {
+				return strings.Split(e.Address(), "/")[:2]
+			}),
+		)),
+		config.WithTrace(trace.Driver{
+			OnBalancerChooseEndpoint: func(
+				trace.DriverBalancerChooseEndpointStartInfo,
+			) func(trace.DriverBalancerChooseEndpointDoneInfo) {
+				return func(info trace.DriverBalancerChooseEndpointDoneInfo) {
+					chosen = append(chosen, info)
+				}
+			},
+		}),
+	)
+	b := &Balancer{
+		driverConfig: cfg,
+		config:       *cfg.Balancer(),
+		pool:         conn.NewPool(context.Background(), cfg),
+		discoveryClient: discoveryMock{endpoints: []endpoint.Endpoint{
+			&mock.Endpoint{AddrField: "a/r1/1", LocationField: "a"},
+			&mock.Endpoint{AddrField: "a/r2/1", LocationField: "a"},
+			&mock.Endpoint{AddrField: "b/r1/1", LocationField: "b"},
+		}},
+		localDCDetector: func(ctx context.Context, endpoints []endpoint.Endpoint) (string, error) {
+			locations := make([]string, 0, len(endpoints))
+			for _, e := range endpoints {
+				locations = append(locations, e.Location())
+			}
+			require.Equal(t, []string{"a/r1", "a/r2", "b/r1"}, locations)
+
+			return "a/r2", nil
+		},
+	}
+
+	require.NoError(t, b.clusterDiscoveryAttempt(ctx))
+	require.Equal(t, "a", b.Stats().LocalDC)
+	for i := 0; i < 10; i++ {
+		c, err := b.getConn(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "a/r2/1", c.Endpoint().Address())
+	}
+	require.Len(t, chosen, 10)
+	require.Equal(t, 2, chosen[0].LocalityLevel)
+}
+
+func TestStrictLocalDC(t *testing.T) {
+	ctx := context.Background()
+	newBalancer := func(localDC string) *Balancer {
+		cfg := config.New(
+			config.WithBalancer(balancers.PreferNearestDCWithFallBack(balancers.Default()).With(
+				balancerConfig.WithStrictLocalDC(true),
+				balancerConfig.WithDCPriority([]string{"a"}),
+			)),
+		)
+
+		return &Balancer{
+			driverConfig: cfg,
+			config:       *cfg.Balancer(),
+			pool:         conn.NewPool(context.Background(), cfg),
+			discoveryClient: discoveryMock{endpoints: []endpoint.Endpoint{
+				&mock.Endpoint{AddrField: "a:123", LocationField: "a", NodeIDField: 1},
+				&mock.Endpoint{AddrField: "b:234", LocationField: "b", NodeIDField: 2},
+				&mock.Endpoint{AddrField: "b:345", LocationField: "b", NodeIDField: 3},
+			}},
+			localDCDetector: func(ctx context.Context, endpoints []endpoint.Endpoint) (string, error) {
+				return localDC, nil
+			},
+		}
+	}
+	locations := func(ctx context.Context, t *testing.T, b *Balancer) map[string]bool {
+		res := make(map[string]bool)
+		for i := 0; i < 100; i++ {
+			c, err := b.getConn(ctx)
+			require.NoError(t, err)
+			res[c.Endpoint().Location()] = true
+		}
+
+		return res
+	}
+
+	b := newBalancer("b")
+	require.NoError(t, b.clusterDiscoveryAttempt(ctx))
+	require.Len(t, b.connections().All(), 2)
+	require.Equal(t, map[string]bool{"b": true}, locations(ctx, t, b))
+
+	t.Run("NodeIDOutsideLocalDC", func(t *testing.T) {
+		require.Equal(t, map[string]bool{"b": true}, locations(endpoint.WithNodeID(ctx, 1), t, b))
+	})
+	t.Run("LocalDCBanned", func(t *testing.T) {
+		for _, c := range b.connections().all {
+			c.SetState(ctx, conn.Banned)
+		}
+		_, err := b.getConn(ctx)
+		require.ErrorIs(t, err, ErrAllEndpointsBanned)
+		for _, c := range b.connections().all {
+			c.SetState(ctx, conn.Online)
+		}
+		b.connections().all[0].SetState(ctx, conn.Banned)
+		require.Equal(t, map[string]bool{"b": true}, locations(ctx, t, b))
+	})
+	t.Run("LocalDCNotDetected", func(t *testing.T) {
+		b := newBalancer("")
+		require.NoError(t, b.clusterDiscoveryAttempt(ctx))
+		_, err := b.getConn(ctx)
+		require.ErrorIs(t, err, ErrNoEndpoints)
+	})
+	t.Run("NoEndpointsInLocalDC", func(t *testing.T) {
+		b := newBalancer("c")
+		require.NoError(t, b.clusterDiscoveryAttempt(ctx))
+		_, err := b.getConn(ctx)
+		require.ErrorIs(t, err, ErrNoEndpoints)
+	})
+}
+
+func TestOnLocalDCChange(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.New(
+		config.WithBalancer(balancers.PreferNearestDC(balancers.Default())),
+	)
+	localDC := "a"
+	b := &Balancer{
+		driverConfig: cfg,
+		config:       *cfg.Balancer(),
+		pool:         conn.NewPool(ctx, cfg),
+		discoveryClient: discoveryMock{endpoints: []endpoint.Endpoint{
+			&mock.Endpoint{AddrField: "a:123", LocationField: "a"},
+			&mock.Endpoint{AddrField: "b:234", LocationField: "b"},
+		}},
+		localDCDetector: func(ctx context.Context, endpoints []endpoint.Endpoint) (string, error) {
+			return localDC, nil
+		},
+	}
+	var changes [][2]string
+	b.OnLocalDCChange(func(ctx context.Context, oldDC, newDC string) {
+		changes = append(changes, [2]string{oldDC, newDC})
+	})
+
+	require.NoError(t, b.clusterDiscoveryAttempt(ctx))
+	require.NoError(t, b.clusterDiscoveryAttempt(ctx))
+	require.Empty(t, changes)
+
+	localDC = "b"
+	require.NoError(t, b.clusterDiscoveryAttempt(ctx))
+	require.NoError(t, b.clusterDiscoveryAttempt(ctx))
+	require.Equal(t, [][2]string{{"a", "b"}}, changes)
+
+	localDC = "a"
+	require.NoError(t, b.clusterDiscoveryAttempt(ctx))
+	require.Equal(t, [][2]string{{"a", "b"}, {"b", "a"}}, changes)
+}
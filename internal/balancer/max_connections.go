@@ -0,0 +1,78 @@
+package balancer
+
+import (
+	"strings"
+
+	balancerConfig "github.com/ydb-platform/ydb-go-sdk/v3/internal/balancer/config"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/endpoint"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xslices"
+)
+
+// limitEndpoints selects up to limit endpoints for materialize connections.
+// Preferred endpoints (by balancer filter, such as endpoints in local DC) selects first,
+// other endpoints selects only with allowed fallback if preferred endpoints less than limit.
+// Selection rotates across discovery cycles inside preferred and other endpoints
+func limitEndpoints(
+	endpoints []endpoint.Endpoint,
+	filter balancerConfig.Filter,
+	info balancerConfig.Info,
+	allowFallback bool,
+	limit int,
+	rotation int,
+) []endpoint.Endpoint {
+	if len(endpoints) <= limit {
+		return endpoints
+	}
+
+	endpoints = xslices.SortCopy(endpoints, func(lhs, rhs endpoint.Endpoint) int {
+		return strings.Compare(lhs.Address(), rhs.Address())
+	})
+
+	var prefer, other []endpoint.Endpoint
+	for _, e := range endpoints {
+		if filter == nil || filter.Allow(info, e) {
+			prefer = append(prefer, e)
+		} else if allowFallback {
+			other = append(other, e)
+		}
+	}
+
+	selected := make([]endpoint.Endpoint, 0, limit)
+	for _, group := range [][]endpoint.Endpoint{prefer, other} {
+		for i := 0; i < len(group) && len(selected) < limit; i++ {
+			selected = append(selected, group[(i+rotation)%len(group)])
+		}
+	}
+
+	return selected
+}
+
+// limitRemoteDCEndpoints keeps all endpoints in local DC and selects up to limit endpoints outside local DC.
+// Selection of remote endpoints rotates across discovery cycles
+func limitRemoteDCEndpoints(
+	endpoints []endpoint.Endpoint, localDC string, limit int, rotation int,
+) []endpoint.Endpoint {
+	var local, remote []endpoint.Endpoint
+	for _, e := range endpoints {
+		if e.Location() == localDC {
+			local = append(local, e)
+		} else {
+			remote = append(remote, e)
+		}
+	}
+
+	if len(remote) <= limit {
+		return endpoints
+	}
+
+	remote = xslices.SortCopy(remote, func(lhs, rhs endpoint.Endpoint) int {
+		return strings.Compare(lhs.Address(), rhs.Address())
+	})
+
+	selected := append(make([]endpoint.Endpoint, 0, len(local)+limit), local...)
+	for i := 0; i < limit; i++ {
+		selected = append(selected, remote[(i+rotation)%len(remote)])
+	}
+
+	return selected
+}
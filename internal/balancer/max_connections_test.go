@@ -0,0 +1,188 @@
+package balancer
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/balancers"
+	"github.com/ydb-platform/ydb-go-sdk/v3/config"
+	balancerConfig "github.com/ydb-platform/ydb-go-sdk/v3/internal/balancer/config"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/conn"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/endpoint"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/mock"
+	"github.com/ydb-platform/ydb-go-sdk/v3/trace"
+)
+
+func TestLimitEndpoints(t *testing.T) {
+	endpoints := []endpoint.Endpoint{
+		&mock.Endpoint{AddrField: "a:1", LocationField: "a"},
+		&mock.Endpoint{AddrField: "b:1", LocationField: "b"},
+		&mock.Endpoint{AddrField: "a:2", LocationField: "a"},
+		&mock.Endpoint{AddrField: "b:2", LocationField: "b"},
+		&mock.Endpoint{AddrField: "a:3", LocationField: "a"},
+	}
+	filter := balancers.PreferLocations(balancers.Default(), "a").Filter
+	addresses := func(endpoints []endpoint.Endpoint) (addresses []string) {
+		for _, e := range endpoints {
+			addresses = append(addresses, e.Address())
+		}
+
+		return addresses
+	}
+
+	t.Run("NotExceeded", func(t *testing.T) {
+		require.Len(t, limitEndpoints(endpoints, filter, balancerConfig.Info{}, true, 5, 0), 5)
+	})
+	t.Run("PreferredFirst", func(t *testing.T) {
+		require.Equal(t, []string{"a:1", "a:2"},
+			addresses(limitEndpoints(endpoints, filter, balancerConfig.Info{}, true, 2, 0)),
+		)
+		require.Equal(t, []string{"a:1", "a:2", "a:3", "b:1"},
+			addresses(limitEndpoints(endpoints, filter, balancerConfig.Info{}, true, 4, 0)),
+		)
+		require.Equal(t, []string{"a:1", "a:2", "a:3"},
+			addresses(limitEndpoints(endpoints, filter, balancerConfig.Info{}, false, 4, 0)),
+		)
+	})
+	t.Run("Rotation", func(t *testing.T) {
+		require.Equal(t, []string{"a:2", "a:3"},
+			addresses(limitEndpoints(endpoints, filter, balancerConfig.Info{}, true, 2, 1)),
+		)
+		require.Equal(t, []string{"a:3", "a:1"},
+			addresses(limitEndpoints(endpoints, filter, balancerConfig.Info{}, true, 2, 2)),
+		)
+	})
+}
+
+func TestMaxConnections(t *testing.T) {
+	const maxConnections = 3
+
+	var (
+		ctx  = context.Background()
+		mu   sync.Mutex
+		open = make(map[string]struct{})
+	)
+	cfg := config.New(
+		config.WithBalancer(balancers.Default().With(balancers.WithMaxConnections(maxConnections))),
+		config.WithTrace(trace.Driver{
+			OnConnDial: func(info trace.DriverConnDialStartInfo) func(trace.DriverConnDialDoneInfo) {
+				address := info.Endpoint.Address()
+
+				return func(info trace.DriverConnDialDoneInfo) {
+					if info.Error == nil {
+						mu.Lock()
+						defer mu.Unlock()
+						open[address] = struct{}{}
+					}
+				}
+			},
+			OnConnClose: func(info trace.DriverConnCloseStartInfo) func(trace.DriverConnCloseDoneInfo) {
+				mu.Lock()
+				defer mu.Unlock()
+				delete(open, info.Endpoint.Address())
+
+				return nil
+			},
+		}),
+	)
+	endpoints := make([]endpoint.Endpoint, 10)
+	for i := range endpoints {
+		endpoints[i] = &mock.Endpoint{AddrField: fmt.Sprintf("127.0.0.1:%d", 10000+i), NodeIDField: uint32(i + 1)}
+	}
+	b := &Balancer{
+		driverConfig:    cfg,
+		config:          *cfg.Balancer(),
+		pool:            conn.NewPool(ctx, cfg),
+		discoveryClient: discoveryMock{endpoints: endpoints},
+	}
+	defer func() { _ = b.pool.Release(ctx) }()
+
+	seen := make(map[string]struct{})
+	for i := 0; i < len(endpoints); i++ {
+		require.NoError(t, b.clusterDiscoveryAttempt(ctx))
+		require.Len(t, b.connections().All(), maxConnections)
+		for _, c := range b.connections().all {
+			_ = c.Ping(ctx) // dial connection
+			seen[c.Endpoint().Address()] = struct{}{}
+		}
+		mu.Lock()
+		require.NotEmpty(t, open)
+		require.LessOrEqual(t, len(open), maxConnections)
+		mu.Unlock()
+	}
+	require.Len(t, seen, len(endpoints))
+}
+
+func TestLimitRemoteDCEndpoints(t *testing.T) {
+	endpoints := []endpoint.Endpoint{
+		&mock.Endpoint{AddrField: "a:1", LocationField: "a"},
+		&mock.Endpoint{AddrField: "b:1", LocationField: "b"},
+		&mock.Endpoint{AddrField: "a:2", LocationField: "a"},
+		&mock.Endpoint{AddrField: "c:1", LocationField: "c"},
+		&mock.Endpoint{AddrField: "b:2", LocationField: "b"},
+	}
+	addresses := func(endpoints []endpoint.Endpoint) (addresses []string) {
+		for _, e := range endpoints {
+			addresses = append(addresses, e.Address())
+		}
+
+		return addresses
+	}
+
+	t.Run("NotExceeded", func(t *testing.T) {
+		require.Len(t, limitRemoteDCEndpoints(endpoints, "a", 3, 0), 5)
+	})
+	t.Run("LocalNotLimited", func(t *testing.T) {
+		require.Equal(t, []string{"a:1", "a:2", "b:1"}, addresses(limitRemoteDCEndpoints(endpoints, "a", 1, 0)))
+	})
+	t.Run("Rotation", func(t *testing.T) {
+		require.Equal(t, []string{"a:1", "a:2", "b:2", "c:1"}, addresses(limitRemoteDCEndpoints(endpoints, "a", 2, 1)))
+		require.Equal(t, []string{"a:1", "a:2", "c:1", "b:1"}, addresses(limitRemoteDCEndpoints(endpoints, "a", 2, 2)))
+	})
+}
+
+func TestRemoteDCConnectionLimit(t *testing.T) {
+	const limit = 2
+
+	ctx := context.Background()
+	cfg := config.New(
+		config.WithBalancer(balancers.PreferNearestDCWithFallBack(balancers.Default()).With(
+			balancerConfig.WithForcedLocalDC("local"),
+			balancers.WithRemoteDCConnectionLimit(limit),
+		)),
+	)
+	var endpoints []endpoint.Endpoint
+	for i := 0; i < 3; i++ {
+		endpoints = append(endpoints, &mock.Endpoint{AddrField: fmt.Sprintf("local:%d", i), LocationField: "local"})
+	}
+	for i := 0; i < 5; i++ {
+		endpoints = append(endpoints, &mock.Endpoint{AddrField: fmt.Sprintf("remote:%d", i), LocationField: "remote"})
+	}
+	b := &Balancer{
+		driverConfig:    cfg,
+		config:          *cfg.Balancer(),
+		pool:            conn.NewPool(ctx, cfg),
+		discoveryClient: discoveryMock{endpoints: endpoints},
+	}
+
+	seen := make(map[string]struct{})
+	for i := 0; i < 5; i++ {
+		require.NoError(t, b.clusterDiscoveryAttempt(ctx))
+		var local, remote int
+		for _, e := range b.connections().All() {
+			if e.Location() == "local" {
+				local++
+			} else {
+				remote++
+				seen[e.Address()] = struct{}{}
+			}
+		}
+		require.Equal(t, 3, local)
+		require.Equal(t, limit, remote)
+	}
+	require.Len(t, seen, 5)
+}
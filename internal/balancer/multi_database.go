@@ -0,0 +1,446 @@
+package balancer
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/conn"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/endpoint"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/stack"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xerrors"
+	"github.com/ydb-platform/ydb-go-sdk/v3/trace"
+)
+
+// MultiDatabaseBalancer routes calls to balancer of primary database and fails over to balancer
+// of secondary (standby) database if calls of primary database fails for sustained period.
+// MultiDatabaseBalancer fails back to primary database only after primary database has available connection
+// for sustained period (observed on calls), so calls not flaps between databases on unstable primary database
+type MultiDatabaseBalancer struct {
+	primary       *Balancer
+	newSecondary  func(ctx context.Context) (*Balancer, error)
+	failoverDelay time.Duration
+	failbackDelay time.Duration
+
+	secondaryMu sync.Mutex
+	// secondary is a balancer of secondary database, nil until first failover
+	secondary *Balancer
+	closed    bool
+	// onSecondary is a list of registrations of callbacks which applies to balancer of secondary database
+	// on make of it
+	onSecondary []func(b *Balancer)
+
+	mu sync.Mutex
+	// active is a balancer which serves calls
+	active *Balancer
+	// unavailableSince is a time of first failed call of primary balancer after last successful call,
+	// zero value means primary balancer is available
+	unavailableSince time.Time
+	// availableSince is a time of first observed available connection of primary balancer while secondary
+	// balancer is active, zero value means primary balancer is unavailable
+	availableSince time.Time
+}
+
+var _ grpc.ClientConnInterface = (*MultiDatabaseBalancer)(nil)
+
+// NewMultiDatabase makes balancer which fails over from primary database to secondary database if calls
+// of primary database fails without successful call during failoverDelay and fails back to primary database
+// if primary database has available connection during failbackDelay.
+// Call of primary database fails if primary balancer has no available endpoints (ErrNoEndpoints or
+// ErrAllEndpointsBanned), has no online connection or call failed with transport error of connection.
+// Balancer of secondary database makes with newSecondary on first failover, so unavailable secondary database
+// not fails start of driver
+func NewMultiDatabase(
+	primary *Balancer,
+	newSecondary func(ctx context.Context) (*Balancer, error),
+	failoverDelay, failbackDelay time.Duration,
+) *MultiDatabaseBalancer {
+	return &MultiDatabaseBalancer{
+		primary:       primary,
+		newSecondary:  newSecondary,
+		failoverDelay: failoverDelay,
+		failbackDelay: failbackDelay,
+		active:        primary,
+	}
+}
+
+// ActiveDatabase returns database of balancer which serves calls
+func (m *MultiDatabaseBalancer) ActiveDatabase() string {
+	return m.activeBalancer().driverConfig.Database()
+}
+
+// activeBalancer returns balancer which serves calls
+func (m *MultiDatabaseBalancer) activeBalancer() *Balancer {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.active
+}
+
+// ForceDiscovery runs cluster discovery of active database synchronously (see Balancer.ForceDiscovery)
+func (m *MultiDatabaseBalancer) ForceDiscovery(ctx context.Context) ([]endpoint.Info, error) {
+	return m.activeBalancer().ForceDiscovery(ctx)
+}
+
+func (m *MultiDatabaseBalancer) Invoke(
+	ctx context.Context,
+	method string,
+	args interface{},
+	reply interface{},
+	opts ...grpc.CallOption,
+) error {
+	return m.call(ctx, func(ctx context.Context, b *Balancer) error {
+		return b.Invoke(ctx, method, args, reply, opts...)
+	})
+}
+
+// InvokeWithInfo invokes unary call same as Invoke and returns info of endpoint which served the call
+// (see Balancer.InvokeWithInfo)
+func (m *MultiDatabaseBalancer) InvokeWithInfo(
+	ctx context.Context,
+	method string,
+	args interface{},
+	reply interface{},
+	opts ...grpc.CallOption,
+) (info endpoint.Info, err error) {
+	err = m.call(ctx, func(ctx context.Context, b *Balancer) (err error) {
+		info, err = b.InvokeWithInfo(ctx, method, args, reply, opts...)
+
+		return err
+	})
+
+	return info, err
+}
+
+func (m *MultiDatabaseBalancer) NewStream(
+	ctx context.Context,
+	desc *grpc.StreamDesc,
+	method string,
+	opts ...grpc.CallOption,
+) (client grpc.ClientStream, err error) {
+	err = m.call(ctx, func(ctx context.Context, b *Balancer) (err error) {
+		client, err = b.NewStream(ctx, desc, method, opts...)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// Stats returns snapshot of balancer state of active database (see Balancer.Stats)
+func (m *MultiDatabaseBalancer) Stats() BalancerStats {
+	return m.activeBalancer().Stats()
+}
+
+// WaitReady blocks until balancer of active database has usable connection (see Balancer.WaitReady)
+func (m *MultiDatabaseBalancer) WaitReady(ctx context.Context) error {
+	return m.activeBalancer().WaitReady(ctx)
+}
+
+// OnBan registers callback which calls on ban of endpoint of primary or secondary database (see Balancer.OnBan)
+func (m *MultiDatabaseBalancer) OnBan(onBan func(ctx context.Context, e trace.EndpointInfo, err error)) {
+	m.register(func(b *Balancer) {
+		b.OnBan(onBan)
+	})
+}
+
+// OnLocalDCChange registers callback which calls on change of detected local DC of primary or secondary
+// database (see Balancer.OnLocalDCChange)
+func (m *MultiDatabaseBalancer) OnLocalDCChange(onLocalDCChange func(ctx context.Context, oldDC, newDC string)) {
+	m.register(func(b *Balancer) {
+		b.OnLocalDCChange(onLocalDCChange)
+	})
+}
+
+// register applies registration of callback to balancers of both databases
+func (m *MultiDatabaseBalancer) register(register func(b *Balancer)) {
+	m.secondaryMu.Lock()
+	defer m.secondaryMu.Unlock()
+
+	register(m.primary)
+	if m.secondary != nil {
+		register(m.secondary)
+	}
+	m.onSecondary = append(m.onSecondary, register)
+}
+
+// DiscoveryHistory returns last applied discovery results of active database (see Balancer.DiscoveryHistory)
+func (m *MultiDatabaseBalancer) DiscoveryHistory() []DiscoveryEvent {
+	return m.activeBalancer().DiscoveryHistory()
+}
+
+// ClusterInfo returns info of cluster of active database (see Balancer.ClusterInfo)
+func (m *MultiDatabaseBalancer) ClusterInfo() ClusterInfo {
+	return m.activeBalancer().ClusterInfo()
+}
+
+// Unban allows banned connection to endpoint of primary or secondary database (see Balancer.Unban)
+func (m *MultiDatabaseBalancer) Unban(ctx context.Context, e endpoint.Info) (unbanned bool) {
+	for _, b := range m.balancers() {
+		if b.Unban(ctx, e) {
+			unbanned = true
+		}
+	}
+
+	return unbanned
+}
+
+// UnbanAll allows all banned connections of both databases (see Balancer.UnbanAll)
+func (m *MultiDatabaseBalancer) UnbanAll(ctx context.Context) (unbanned int) {
+	for _, b := range m.balancers() {
+		unbanned += b.UnbanAll(ctx)
+	}
+
+	return unbanned
+}
+
+// ExportState serializes state of balancer of active database into versioned snapshot (see Balancer.ExportState)
+func (m *MultiDatabaseBalancer) ExportState() ([]byte, error) {
+	return m.activeBalancer().ExportState()
+}
+
+// RecycleEndpoint closes connection to known endpoint of primary or secondary database
+// (see Balancer.RecycleEndpoint)
+func (m *MultiDatabaseBalancer) RecycleEndpoint(ctx context.Context, e endpoint.Info) (err error) {
+	for _, b := range m.balancers() {
+		if err = b.RecycleEndpoint(ctx, e); !xerrors.Is(err, ErrUnknownEndpoint) {
+			return err
+		}
+	}
+
+	return err
+}
+
+// Healthy reports about degraded state of balancer of active database (see Balancer.Healthy).
+// MultiDatabaseBalancer is degraded while calls served by secondary database
+func (m *MultiDatabaseBalancer) Healthy() (healthy bool, reason string) {
+	active := m.activeBalancer()
+
+	healthy, reason = active.Healthy()
+	if active == m.primary {
+		return healthy, reason
+	}
+
+	if healthy {
+		return false, fmt.Sprintf("serving from secondary database %q", active.driverConfig.Database())
+	}
+
+	return false, fmt.Sprintf("serving from secondary database %q, %s", active.driverConfig.Database(), reason)
+}
+
+// BannedEndpoints returns currently banned endpoints of both databases (see Balancer.BannedEndpoints)
+func (m *MultiDatabaseBalancer) BannedEndpoints() (banned []BannedEndpoint) {
+	for _, b := range m.balancers() {
+		banned = append(banned, b.BannedEndpoints()...)
+	}
+
+	return banned
+}
+
+// CircuitBreakerStates returns states of per-endpoint circuit breakers of both databases by endpoint address
+// (see Balancer.CircuitBreakerStates)
+func (m *MultiDatabaseBalancer) CircuitBreakerStates() map[string]CircuitBreakerState {
+	states := make(map[string]CircuitBreakerState)
+	for _, b := range m.balancers() {
+		for address, state := range b.CircuitBreakerStates() {
+			states[address] = state
+		}
+	}
+
+	return states
+}
+
+// balancers returns balancer of primary database and balancer of secondary database if it made
+func (m *MultiDatabaseBalancer) balancers() []*Balancer {
+	m.secondaryMu.Lock()
+	defer m.secondaryMu.Unlock()
+
+	if m.secondary == nil {
+		return []*Balancer{m.primary}
+	}
+
+	return []*Balancer{m.primary, m.secondary}
+}
+
+// secondaryBalancer returns balancer of secondary database and makes it on first call
+func (m *MultiDatabaseBalancer) secondaryBalancer(ctx context.Context) (*Balancer, error) {
+	m.secondaryMu.Lock()
+	defer m.secondaryMu.Unlock()
+
+	if m.closed {
+		return nil, xerrors.WithStackTrace(ErrClosed)
+	}
+
+	if m.secondary == nil {
+		b, err := m.newSecondary(ctx)
+		if err != nil {
+			return nil, xerrors.WithStackTrace(err)
+		}
+		for _, register := range m.onSecondary {
+			register(b)
+		}
+		m.secondary = b
+	}
+
+	return m.secondary, nil
+}
+
+// BeginShutdown switches balancers of both databases to fast-fail mode for graceful shutdown
+func (m *MultiDatabaseBalancer) BeginShutdown() {
+	for _, b := range m.balancers() {
+		b.BeginShutdown()
+	}
+}
+
+// Shutdown gracefully closes balancers of both databases (see Balancer.Shutdown)
+func (m *MultiDatabaseBalancer) Shutdown(ctx context.Context) error {
+	return m.close(ctx, (*Balancer).Shutdown)
+}
+
+// Close closes balancers of both databases
+func (m *MultiDatabaseBalancer) Close(ctx context.Context) error {
+	return m.close(ctx, (*Balancer).Close)
+}
+
+func (m *MultiDatabaseBalancer) close(
+	ctx context.Context, closeBalancer func(b *Balancer, ctx context.Context) error,
+) error {
+	m.secondaryMu.Lock()
+	m.closed = true
+	m.secondaryMu.Unlock()
+
+	var issues []error
+	for _, b := range m.balancers() {
+		if err := closeBalancer(b, ctx); err != nil {
+			issues = append(issues, err)
+		}
+	}
+
+	switch len(issues) {
+	case 0:
+		return nil
+	case 1:
+		return xerrors.WithStackTrace(issues[0])
+	default:
+		return xerrors.WithStackTrace(xerrors.NewWithIssues("multi database balancer close failed", issues...))
+	}
+}
+
+// call calls f with active balancer. Call which caused failover with ErrNoEndpoints repeats with balancer
+// of secondary database, because ErrNoEndpoints means that call was not sent to primary database.
+// Other failed calls may be executed by primary database, so they repeats only by retryer of caller
+func (m *MultiDatabaseBalancer) call(ctx context.Context, f func(ctx context.Context, b *Balancer) error) error {
+	b := m.choose(ctx)
+
+	err := f(ctx, b)
+	if b != m.primary {
+		return err
+	}
+
+	if secondary := m.onPrimaryDone(ctx, err); secondary != nil && xerrors.Is(err, ErrNoEndpoints) {
+		return f(ctx, secondary)
+	}
+
+	return err
+}
+
+// choose returns active balancer. If secondary database is active and primary database has available
+// connection during failbackDelay - choose fails back to primary database
+func (m *MultiDatabaseBalancer) choose(ctx context.Context) *Balancer {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.active == m.primary {
+		return m.active
+	}
+
+	if !m.primary.connections().hasOkConnection() {
+		m.availableSince = time.Time{}
+
+		return m.active
+	}
+
+	now := m.primary.clock().Now()
+	if m.availableSince.IsZero() {
+		m.availableSince = now
+	}
+
+	if now.Sub(m.availableSince) >= m.failbackDelay {
+		m.switchTo(ctx, m.primary, nil)
+	}
+
+	return m.active
+}
+
+// isPrimaryFailure checks that failed call means unavailability of primary database
+func (m *MultiDatabaseBalancer) isPrimaryFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	return xerrors.Is(err, ErrNoEndpoints) || conn.IsBadConn(err) || !m.primary.connections().hasOkConnection()
+}
+
+// onPrimaryDone tracks availability of primary database by result of call.
+// Returns balancer of secondary database if call caused failover to secondary database
+func (m *MultiDatabaseBalancer) onPrimaryDone(ctx context.Context, err error) (secondary *Balancer) {
+	if !m.mustFailover(err) {
+		return nil
+	}
+
+	// balancer of secondary database makes without lock, so calls of primary database not waits it
+	secondary, secondaryErr := m.secondaryBalancer(ctx)
+	if secondaryErr != nil {
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.active != m.primary {
+		return nil
+	}
+
+	m.switchTo(ctx, secondary, err)
+
+	return secondary
+}
+
+// mustFailover tracks failures of calls of primary database and checks that primary database fails
+// without successful call during failoverDelay
+func (m *MultiDatabaseBalancer) mustFailover(err error) bool {
+	failure := m.isPrimaryFailure(err)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !failure {
+		m.unavailableSince = time.Time{}
+
+		return false
+	}
+
+	now := m.primary.clock().Now()
+	if m.unavailableSince.IsZero() {
+		m.unavailableSince = now
+	}
+
+	return m.active == m.primary && now.Sub(m.unavailableSince) >= m.failoverDelay
+}
+
+// switchTo changes active balancer and traces failover or failback. Must be called under lock
+func (m *MultiDatabaseBalancer) switchTo(ctx context.Context, b *Balancer, cause error) {
+	from := m.active
+	m.active, m.unavailableSince, m.availableSince = b, time.Time{}, time.Time{}
+
+	trace.DriverOnBalancerFailover(m.primary.driverConfig.Trace(), &ctx,
+		stack.FunctionID("github.com/ydb-platform/ydb-go-sdk/v3/internal/balancer.(*MultiDatabaseBalancer).switchTo"),
+		from.driverConfig.Database(), b.driverConfig.Database(), b == m.primary, cause,
+	)
+}
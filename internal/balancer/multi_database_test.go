@@ -0,0 +1,267 @@
+package balancer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"github.com/stretchr/testify/require"
+	grpcCodes "google.golang.org/grpc/codes"
+	grpcStatus "google.golang.org/grpc/status"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/config"
+	balancerConfig "github.com/ydb-platform/ydb-go-sdk/v3/internal/balancer/config"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/conn"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/endpoint"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/mock"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xerrors"
+	"github.com/ydb-platform/ydb-go-sdk/v3/trace"
+)
+
+func TestMultiDatabaseBalancer(t *testing.T) {
+	ctx := context.Background()
+	clock := clockwork.NewFakeClock()
+	r := trace.Recorder()
+	newBalancer := func(database string, state conn.State) *Balancer {
+		cfg := config.New(config.WithDatabase(database), config.WithTrace(r.Driver))
+		b := &Balancer{
+			driverConfig: cfg,
+			config:       balancerConfig.Config{Clock: clock},
+			pool:         conn.NewPool(ctx, cfg),
+		}
+		b.connectionsState.Store(newConnectionsState([]conn.Conn{
+			&mock.Conn{AddrField: database, State: state},
+		}, nil, balancerConfig.Info{}, false))
+
+		return b
+	}
+	primary := newBalancer("/primary", conn.Destroyed)
+	secondary := newBalancer("/secondary", conn.Online)
+	m := NewMultiDatabase(primary, func(ctx context.Context) (*Balancer, error) {
+		return secondary, nil
+	}, time.Minute, time.Minute)
+	call := func() (address string, err error) {
+		err = m.call(ctx, func(ctx context.Context, b *Balancer) error {
+			e, err := b.wrapCall(ctx, "", func(ctx context.Context, cc conn.Conn) error {
+				return nil
+			})
+			if e != nil {
+				address = e.Address()
+			}
+
+			return err
+		})
+
+		return address, err
+	}
+
+	_, err := call()
+	require.ErrorIs(t, err, ErrAllEndpointsBanned)
+	require.Equal(t, "/primary", m.ActiveDatabase())
+
+	clock.Advance(time.Minute / 2)
+	_, err = call()
+	require.ErrorIs(t, err, ErrNoEndpoints)
+	require.Equal(t, "/primary", m.ActiveDatabase())
+	require.Empty(t, r.Events("OnBalancerFailover"))
+
+	clock.Advance(time.Minute / 2)
+	address, err := call()
+	require.NoError(t, err)
+	require.Equal(t, "/secondary", address)
+	require.Equal(t, "/secondary", m.ActiveDatabase())
+	events := r.Events("OnBalancerFailover")
+	require.Len(t, events, 1)
+	info := events[0].Info.(trace.DriverBalancerFailoverInfo)
+	require.Equal(t, "/primary", info.From)
+	require.Equal(t, "/secondary", info.To)
+	require.False(t, info.Failback)
+	require.ErrorIs(t, info.Error, ErrNoEndpoints)
+	healthy, reason := m.Healthy()
+	require.False(t, healthy)
+	require.Contains(t, reason, "/secondary")
+
+	address, err = call()
+	require.NoError(t, err)
+	require.Equal(t, "/secondary", address)
+
+	setPrimaryState := func(state conn.State) {
+		primary.connectionsState.Store(newConnectionsState([]conn.Conn{
+			&mock.Conn{AddrField: "/primary", State: state},
+		}, nil, balancerConfig.Info{}, false))
+	}
+
+	// primary database flaps, so calls stay on secondary database
+	setPrimaryState(conn.Online)
+	address, err = call()
+	require.NoError(t, err)
+	require.Equal(t, "/secondary", address)
+	clock.Advance(time.Minute / 2)
+	setPrimaryState(conn.Destroyed)
+	address, err = call()
+	require.NoError(t, err)
+	require.Equal(t, "/secondary", address)
+	clock.Advance(time.Minute / 2)
+	setPrimaryState(conn.Online)
+	address, err = call()
+	require.NoError(t, err)
+	require.Equal(t, "/secondary", address)
+	require.Len(t, r.Events("OnBalancerFailover"), 1)
+
+	// primary database available during failback delay
+	clock.Advance(time.Minute)
+	address, err = call()
+	require.NoError(t, err)
+	require.Equal(t, "/primary", address)
+	require.Equal(t, "/primary", m.ActiveDatabase())
+	events = r.Events("OnBalancerFailover")
+	require.Len(t, events, 2)
+	info = events[1].Info.(trace.DriverBalancerFailoverInfo)
+	require.Equal(t, "/secondary", info.From)
+	require.Equal(t, "/primary", info.To)
+	require.True(t, info.Failback)
+	require.NoError(t, info.Error)
+	healthy, _ = m.Healthy()
+	require.True(t, healthy)
+}
+
+func TestMultiDatabaseBalancerTransportErrors(t *testing.T) {
+	ctx := context.Background()
+	clock := clockwork.NewFakeClock()
+	newBalancer := func(database string) *Balancer {
+		cfg := config.New(config.WithDatabase(database))
+		b := &Balancer{
+			driverConfig: cfg,
+			config:       balancerConfig.Config{Clock: clock},
+			pool:         conn.NewPool(ctx, cfg),
+		}
+		b.connectionsState.Store(newConnectionsState([]conn.Conn{
+			&mock.Conn{AddrField: database, State: conn.Online},
+		}, nil, balancerConfig.Info{}, false))
+
+		return b
+	}
+	primary := newBalancer("/primary")
+	secondaryErr := errors.New("secondary unavailable")
+	m := NewMultiDatabase(primary, func(ctx context.Context) (*Balancer, error) {
+		if secondaryErr != nil {
+			return nil, secondaryErr
+		}
+
+		return newBalancer("/secondary"), nil
+	}, time.Minute, time.Minute)
+	call := func(callErr error) (addresses []string, err error) {
+		err = m.call(ctx, func(ctx context.Context, b *Balancer) error {
+			e, err := b.wrapCall(ctx, "", func(ctx context.Context, cc conn.Conn) error {
+				return callErr
+			})
+			if e != nil {
+				addresses = append(addresses, e.Address())
+			}
+
+			return err
+		})
+
+		return addresses, err
+	}
+	unavailable := xerrors.Transport(grpcStatus.Error(grpcCodes.Unavailable, ""))
+
+	_, err := call(unavailable)
+	require.Error(t, err)
+	clock.Advance(time.Minute / 2)
+	_, err = call(nil)
+	require.NoError(t, err)
+	clock.Advance(time.Minute / 2)
+	_, err = call(unavailable)
+	require.Error(t, err)
+	require.Equal(t, "/primary", m.ActiveDatabase(), "successful call resets failures of primary database")
+
+	t.Run("SecondaryUnavailable", func(t *testing.T) {
+		clock.Advance(time.Minute)
+		_, err = call(unavailable)
+		require.Error(t, err)
+		require.Equal(t, "/primary", m.ActiveDatabase())
+	})
+
+	t.Run("Failover", func(t *testing.T) {
+		secondaryErr = nil
+		addresses, err := call(unavailable)
+		require.Error(t, err)
+		require.Equal(t, []string{"/primary"}, addresses, "call sent to primary database not repeats")
+		require.Equal(t, "/secondary", m.ActiveDatabase())
+
+		addresses, err = call(nil)
+		require.NoError(t, err)
+		require.Equal(t, []string{"/secondary"}, addresses)
+	})
+}
+
+func TestMultiDatabaseBalancerOnBan(t *testing.T) {
+	ctx := context.Background()
+	newBalancer := func(database string) *Balancer {
+		cfg := config.New(config.WithDatabase(database))
+		b := &Balancer{
+			driverConfig: cfg,
+			pool:         conn.NewPool(ctx, cfg),
+		}
+		b.connectionsState.Store(newConnectionsState([]conn.Conn{
+			&mock.Conn{AddrField: database, State: conn.Online},
+		}, nil, balancerConfig.Info{}, false))
+
+		return b
+	}
+	primary, secondary := newBalancer("/primary"), newBalancer("/secondary")
+	m := NewMultiDatabase(primary, func(ctx context.Context) (*Balancer, error) {
+		return secondary, nil
+	}, time.Minute, time.Minute)
+
+	var banned []string
+	m.OnBan(func(ctx context.Context, e trace.EndpointInfo, err error) {
+		banned = append(banned, e.Address())
+	})
+	unavailable := xerrors.Transport(grpcStatus.Error(grpcCodes.Unavailable, ""))
+
+	primary.ban(ctx, primary.connections().all[0], unavailable)
+	require.Equal(t, []string{"/primary"}, banned)
+
+	_, err := m.secondaryBalancer(ctx)
+	require.NoError(t, err)
+	secondary.ban(ctx, secondary.connections().all[0], unavailable)
+	require.Equal(t, []string{"/primary", "/secondary"}, banned)
+}
+
+func TestMultiDatabaseBalancerUnban(t *testing.T) {
+	ctx := context.Background()
+	newBalancer := func(database, address string) *Balancer {
+		cfg := config.New(config.WithDatabase(database))
+		b := &Balancer{
+			driverConfig: cfg,
+			pool:         conn.NewPool(ctx, cfg),
+			discoveryClient: discoveryMock{endpoints: []endpoint.Endpoint{
+				endpoint.New(address, endpoint.WithID(1)),
+			}},
+		}
+		require.NoError(t, b.clusterDiscoveryAttempt(ctx))
+		b.pool.Ban(ctx, b.connections().all[0], xerrors.Transport(grpcStatus.Error(grpcCodes.Unavailable, "")))
+		require.Equal(t, conn.Banned, b.connections().all[0].GetState())
+
+		return b
+	}
+	primary, secondary := newBalancer("/primary", "a:1"), newBalancer("/secondary", "b:1")
+	m := NewMultiDatabase(primary, func(ctx context.Context) (*Balancer, error) {
+		return secondary, nil
+	}, time.Minute, time.Minute)
+
+	require.Len(t, m.BannedEndpoints(), 1)
+	require.Equal(t, 1, m.UnbanAll(ctx), "balancer of secondary database not made yet")
+
+	_, err := m.secondaryBalancer(ctx)
+	require.NoError(t, err)
+	banned := m.BannedEndpoints()
+	require.Len(t, banned, 1)
+	require.Equal(t, "b:1", banned[0].Endpoint.Address())
+	require.True(t, m.Unban(ctx, endpoint.New("b:1", endpoint.WithID(1))))
+	require.False(t, m.Unban(ctx, endpoint.New("b:1", endpoint.WithID(1))))
+}
@@ -0,0 +1,75 @@
+package balancer
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/config"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/conn"
+	internalDiscovery "github.com/ydb-platform/ydb-go-sdk/v3/internal/discovery"
+	discoveryConfig "github.com/ydb-platform/ydb-go-sdk/v3/internal/discovery/config"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xerrors"
+	"github.com/ydb-platform/ydb-go-sdk/v3/trace"
+)
+
+// EndpointProbeResult is a result of probe of discovered endpoint
+type EndpointProbeResult struct {
+	Endpoint trace.EndpointInfo
+
+	// RTT is a round-trip time of probe request
+	RTT time.Duration
+
+	// Error is an error of probe request. Endpoint is reachable if Error is nil
+	Error error
+}
+
+// Probe makes discovery and probes each discovered endpoint with lightweight request without balancer initialization
+func Probe(
+	ctx context.Context,
+	driverConfig *config.Config,
+	opts ...discoveryConfig.Option,
+) ([]EndpointProbeResult, error) {
+	pool := conn.NewPool(ctx, driverConfig)
+	cfg := newDiscoveryConfig(driverConfig, driverConfig.Endpoint(), opts...)
+	client := newDiscoveryClient(ctx, driverConfig, pool, cfg, opts...)
+	defer func() {
+		closeCtx, cancel := closeContext(ctx)
+		defer cancel()
+
+		_ = client.Close(closeCtx)
+		_ = pool.Release(closeCtx)
+	}()
+
+	endpoints, err := client.Discover(ctx)
+	if err != nil {
+		return nil, xerrors.WithStackTrace(err)
+	}
+
+	var (
+		conns   = endpointsToConnections(pool, endpoints)
+		results = make([]EndpointProbeResult, len(conns))
+		wg      sync.WaitGroup
+	)
+	wg.Add(len(conns))
+	for i, cc := range conns {
+		go func(i int, cc conn.Conn) {
+			defer wg.Done()
+			results[i] = probeConnection(ctx, cc, cfg)
+		}(i, cc)
+	}
+	wg.Wait()
+
+	return results, nil
+}
+
+func probeConnection(ctx context.Context, cc conn.Conn, cfg *discoveryConfig.Config) EndpointProbeResult {
+	start := time.Now()
+	_, err := internalDiscovery.New(ctx, cc, cfg).WhoAmI(ctx)
+
+	return EndpointProbeResult{
+		Endpoint: cc.Endpoint(),
+		RTT:      time.Since(start),
+		Error:    err,
+	}
+}
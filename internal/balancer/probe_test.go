@@ -0,0 +1,32 @@
+package balancer
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/config"
+)
+
+func TestProbe(t *testing.T) {
+	t.Run("DiscoveryFailed", func(t *testing.T) {
+		listen, err := net.ListenTCP("tcp", &net.TCPAddr{IP: localIP})
+		require.NoError(t, err)
+		address := listen.Addr().String()
+		_ = listen.Close() // force close, for not accept tcp connections
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		results, err := Probe(ctx, config.New(
+			config.WithEndpoint(address),
+			config.WithDatabase("/local"),
+			config.WithDialTimeout(time.Second),
+		))
+		require.Error(t, err)
+		require.Empty(t, results)
+	})
+}
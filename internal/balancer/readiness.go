@@ -0,0 +1,161 @@
+package balancer
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/conn"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xcontext"
+)
+
+const (
+	readinessCheckMinInterval = 500 * time.Millisecond
+	readinessCheckMaxInterval = 10 * time.Second
+)
+
+// readinessGate checks new connections with lightweight request (such as WhoAmI) before use of connection
+// for calls. Connections which failed check stay pending and checks again with exponential backoff.
+// Pending connections used for calls only if no one ready connection available
+type readinessGate struct {
+	check func(ctx context.Context, c conn.Conn) error
+	clock clockwork.Clock
+
+	ctx    context.Context //nolint:containedctx
+	cancel context.CancelFunc
+
+	mu      sync.Mutex
+	ready   map[string]struct{}
+	pending map[string]*readinessProbe
+}
+
+// readinessProbe is a pending readiness check of connection
+type readinessProbe struct {
+	ctx    context.Context //nolint:containedctx
+	cancel context.CancelFunc
+}
+
+func newReadinessGate(
+	ctx context.Context, clock clockwork.Clock, check func(ctx context.Context, c conn.Conn) error,
+) *readinessGate {
+	ctx, cancel := xcontext.WithCancel(xcontext.ValueOnly(ctx))
+
+	return &readinessGate{
+		check:   check,
+		clock:   clock,
+		ctx:     ctx,
+		cancel:  cancel,
+		ready:   make(map[string]struct{}),
+		pending: make(map[string]*readinessProbe),
+	}
+}
+
+// isReady checks that connection passed readiness check
+func (g *readinessGate) isReady(c conn.Conn) bool {
+	if g == nil {
+		return true
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	_, ready := g.ready[c.Endpoint().Address()]
+
+	return ready
+}
+
+// update starts readiness checks of new connections, forgets connections removed from discovery
+// and cancels their pending checks
+func (g *readinessGate) update(conns []conn.Conn) {
+	if g == nil {
+		return
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	actual := make(map[string]struct{}, len(conns))
+	for _, c := range conns {
+		address := c.Endpoint().Address()
+		actual[address] = struct{}{}
+		if _, ready := g.ready[address]; ready {
+			continue
+		}
+		if _, pending := g.pending[address]; pending {
+			continue
+		}
+		ctx, cancel := xcontext.WithCancel(g.ctx)
+		p := &readinessProbe{ctx: ctx, cancel: cancel}
+		g.pending[address] = p
+
+		go g.run(p, c)
+	}
+
+	for address := range g.ready {
+		if _, has := actual[address]; !has {
+			delete(g.ready, address)
+		}
+	}
+	for address, p := range g.pending {
+		if _, has := actual[address]; !has {
+			p.cancel()
+			delete(g.pending, address)
+		}
+	}
+}
+
+func (g *readinessGate) run(p *readinessProbe, c conn.Conn) {
+	address := c.Endpoint().Address()
+	defer func() {
+		p.cancel()
+
+		g.mu.Lock()
+		if g.pending[address] == p {
+			delete(g.pending, address)
+		}
+		g.mu.Unlock()
+	}()
+
+	interval := readinessCheckMinInterval
+	for {
+		if g.probe(p.ctx, c) == nil {
+			g.mu.Lock()
+			if g.pending[address] == p {
+				g.ready[address] = struct{}{}
+			}
+			g.mu.Unlock()
+
+			return
+		}
+
+		timer := g.clock.NewTimer(interval)
+		select {
+		case <-p.ctx.Done():
+			timer.Stop()
+
+			return
+		case <-timer.Chan():
+		}
+
+		if interval *= 2; interval > readinessCheckMaxInterval {
+			interval = readinessCheckMaxInterval
+		}
+	}
+}
+
+func (g *readinessGate) probe(ctx context.Context, c conn.Conn) error {
+	ctx, cancel := xcontext.WithTimeout(ctx, readinessCheckMaxInterval)
+	defer cancel()
+
+	return g.check(ctx, c)
+}
+
+func (g *readinessGate) stop() {
+	if g == nil {
+		return
+	}
+
+	g.cancel()
+}
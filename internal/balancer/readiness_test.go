@@ -0,0 +1,113 @@
+package balancer
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"github.com/stretchr/testify/require"
+
+	balancerConfig "github.com/ydb-platform/ydb-go-sdk/v3/internal/balancer/config"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/conn"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/mock"
+)
+
+func TestReadinessGate(t *testing.T) {
+	ctx := context.Background()
+	clock := clockwork.NewFakeClock()
+
+	var (
+		mu         sync.Mutex
+		notReady   = map[string]bool{"b:234": true}
+		checks     = make(map[string]int)
+		errNotInit = errors.New("not initialized")
+	)
+	g := newReadinessGate(ctx, clock, func(ctx context.Context, c conn.Conn) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		checks[c.Endpoint().Address()]++
+		if notReady[c.Endpoint().Address()] {
+			return errNotInit
+		}
+
+		return nil
+	})
+	defer g.stop()
+
+	conns := []conn.Conn{
+		&mock.Conn{AddrField: "a:123", State: conn.Online},
+		&mock.Conn{AddrField: "b:234", State: conn.Online},
+	}
+	s := newConnectionsState(conns, nil, balancerConfig.Info{}, false, withReadinessGate(g))
+	addresses := func() map[string]bool {
+		res := make(map[string]bool)
+		for i := 0; i < 100; i++ {
+			c, _ := s.GetConnection(ctx)
+			require.NotNil(t, c)
+			res[c.Endpoint().Address()] = true
+		}
+
+		return res
+	}
+
+	t.Run("Pending", func(t *testing.T) {
+		// not ready connections used if no one ready connection available
+		require.Equal(t, map[string]bool{"a:123": true, "b:234": true}, addresses())
+		// not ready connections are not failed, so they not force rediscovery
+		_, failedCount := s.GetConnection(ctx)
+		require.Zero(t, failedCount)
+	})
+
+	g.update(conns)
+	g.update(conns) // duplicate check of pending connection ignored
+	require.Eventually(t, func() bool {
+		return g.isReady(conns[0])
+	}, time.Second, time.Millisecond)
+	require.Equal(t, map[string]bool{"a:123": true}, addresses())
+
+	clock.BlockUntil(1)
+	require.False(t, g.isReady(conns[1]))
+	mu.Lock()
+	notReady["b:234"] = false
+	mu.Unlock()
+	clock.Advance(readinessCheckMinInterval)
+	require.Eventually(t, func() bool {
+		return g.isReady(conns[1])
+	}, time.Second, time.Millisecond)
+	require.Equal(t, map[string]bool{"a:123": true, "b:234": true}, addresses())
+
+	mu.Lock()
+	require.Equal(t, map[string]int{"a:123": 1, "b:234": 2}, checks)
+	mu.Unlock()
+
+	t.Run("Removed", func(t *testing.T) {
+		g.update(conns[:1])
+		require.True(t, g.isReady(conns[0]))
+		require.False(t, g.isReady(conns[1]))
+	})
+
+	t.Run("PendingRemoved", func(t *testing.T) {
+		c := &mock.Conn{AddrField: "c:345", State: conn.Online}
+		mu.Lock()
+		notReady["c:345"] = true
+		mu.Unlock()
+		g.update([]conn.Conn{conns[0], c})
+		clock.BlockUntil(1)
+		g.update(conns[:1])
+		require.Eventually(t, func() bool {
+			g.mu.Lock()
+			defer g.mu.Unlock()
+
+			return len(g.pending) == 0
+		}, time.Second, time.Millisecond)
+		clock.Advance(readinessCheckMaxInterval)
+		require.False(t, g.isReady(c))
+		mu.Lock()
+		require.Equal(t, 1, checks["c:345"])
+		mu.Unlock()
+	})
+}
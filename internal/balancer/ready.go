@@ -0,0 +1,60 @@
+package balancer
+
+import (
+	"context"
+	"time"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xerrors"
+)
+
+// waitReadyCheckInterval is an interval of checks of connections states (such as unban) between discoveries
+const waitReadyCheckInterval = 100 * time.Millisecond
+
+// WaitReady blocks until balancer has at least one usable (not banned) connection or context done
+func (b *Balancer) WaitReady(ctx context.Context) error {
+	ticker := b.clock().NewTicker(waitReadyCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		var updated chan struct{}
+		b.mu.WithLock(func() {
+			if b.updated == nil {
+				b.updated = make(chan struct{})
+			}
+			updated = b.updated
+		})
+
+		if b.connections().hasOkConnection() {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return xerrors.WithStackTrace(xerrors.Join(ctx.Err(), ErrNoEndpoints))
+		case <-updated:
+		case <-ticker.Chan():
+		}
+	}
+}
+
+// notifyUpdated wakes up all waiters of balancer readiness. Must be called under lock
+func (b *Balancer) notifyUpdated() {
+	if b.updated != nil {
+		close(b.updated)
+		b.updated = nil
+	}
+}
+
+func (s *connectionsState) hasOkConnection() bool {
+	if s == nil {
+		return false
+	}
+
+	for _, c := range s.all {
+		if s.isOkConnection(c, false) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,176 @@
+package balancer
+
+import (
+	"context"
+	"sync"
+
+	"google.golang.org/grpc"
+	grpcCodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/conn"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xerrors"
+)
+
+// maxStreamReconnects is a maximum count of consecutive reconnects of stream on single operation of stream
+const maxStreamReconnects = 3
+
+// reconnectingStream re-opens stream with new connection on loss of connection and replays initialization
+// of stream (messages sent before first received message). Connection of failed stream bans before re-open,
+// so new stream opens on other connection. See conn.WithAutoReconnect for delivery semantics
+type reconnectingStream struct {
+	ctx  context.Context //nolint:containedctx
+	open func(ctx context.Context) (grpc.ClientStream, conn.Conn, error)
+	ban  func(ctx context.Context, cc conn.Conn, err error)
+
+	mu     sync.Mutex
+	stream grpc.ClientStream
+	cc     conn.Conn
+	// init contains messages sent before first received message for replay on every reconnect
+	init        []interface{}
+	initialized bool
+	closeSend   bool
+}
+
+var _ grpc.ClientStream = (*reconnectingStream)(nil)
+
+func newReconnectingStream(
+	ctx context.Context,
+	open func(ctx context.Context) (grpc.ClientStream, conn.Conn, error),
+	ban func(ctx context.Context, cc conn.Conn, err error),
+) (*reconnectingStream, error) {
+	stream, cc, err := open(ctx)
+	if err != nil {
+		return nil, xerrors.WithStackTrace(err)
+	}
+
+	return &reconnectingStream{
+		ctx:    ctx,
+		open:   open,
+		ban:    ban,
+		stream: stream,
+		cc:     cc,
+	}, nil
+}
+
+// isReconnectable reports whether stream error caused by loss of connection
+func isReconnectable(err error) bool {
+	return xerrors.IsTransportError(err, grpcCodes.Unavailable)
+}
+
+func (s *reconnectingStream) current() grpc.ClientStream {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.stream
+}
+
+// reconnect bans connection of failed stream, opens new stream instead of failed stream and replays
+// initialization of stream. If failed stream already replaced by concurrent operation - reconnect returns
+// current stream
+func (s *reconnectingStream) reconnect(failed grpc.ClientStream, cause error) (grpc.ClientStream, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stream != failed {
+		return s.stream, nil
+	}
+
+	if s.ban != nil && s.cc != nil {
+		s.ban(s.ctx, s.cc, cause)
+	}
+
+	stream, cc, err := s.open(s.ctx)
+	if err != nil {
+		return nil, xerrors.WithStackTrace(err)
+	}
+
+	for _, m := range s.init {
+		if err = stream.SendMsg(m); err != nil {
+			return nil, xerrors.WithStackTrace(err)
+		}
+	}
+
+	if s.closeSend {
+		if err = stream.CloseSend(); err != nil {
+			return nil, xerrors.WithStackTrace(err)
+		}
+	}
+
+	s.stream, s.cc = stream, cc
+
+	return stream, nil
+}
+
+// do calls f with current stream and reconnects stream on loss of connection
+func (s *reconnectingStream) do(f func(stream grpc.ClientStream) error) error {
+	stream := s.current()
+	for attempt := 0; ; attempt++ {
+		err := f(stream)
+		if err == nil || !isReconnectable(err) || attempt >= maxStreamReconnects || s.ctx.Err() != nil {
+			return err
+		}
+
+		if stream, err = s.reconnect(stream, err); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *reconnectingStream) Header() (metadata.MD, error) {
+	return s.current().Header()
+}
+
+func (s *reconnectingStream) Trailer() metadata.MD {
+	return s.current().Trailer()
+}
+
+func (s *reconnectingStream) Context() context.Context {
+	return s.current().Context()
+}
+
+func (s *reconnectingStream) CloseSend() error {
+	s.mu.Lock()
+	s.closeSend = true
+	s.mu.Unlock()
+
+	return s.do(func(stream grpc.ClientStream) error {
+		return stream.CloseSend()
+	})
+}
+
+func (s *reconnectingStream) SendMsg(m interface{}) error {
+	err := s.do(func(stream grpc.ClientStream) error {
+		return stream.SendMsg(m)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.initialized {
+		s.init = append(s.init, m)
+	}
+
+	return nil
+}
+
+func (s *reconnectingStream) RecvMsg(m interface{}) error {
+	err := s.do(func(stream grpc.ClientStream) error {
+		return stream.RecvMsg(m)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// initialization of stream completes on first received message, so next sent messages not buffered.
+	// Buffered initialization keeps for replay on every next reconnect
+	s.initialized = true
+
+	return nil
+}
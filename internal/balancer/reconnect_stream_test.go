@@ -0,0 +1,146 @@
+package balancer
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	grpcCodes "google.golang.org/grpc/codes"
+	grpcStatus "google.golang.org/grpc/status"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/conn"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/mock"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xerrors"
+)
+
+type streamMock struct {
+	grpc.ClientStream
+
+	sent      []interface{}
+	closeSend bool
+	recv      []error
+}
+
+func (s *streamMock) SendMsg(m interface{}) error {
+	s.sent = append(s.sent, m)
+
+	return nil
+}
+
+func (s *streamMock) CloseSend() error {
+	s.closeSend = true
+
+	return nil
+}
+
+func (s *streamMock) RecvMsg(m interface{}) error {
+	err := s.recv[0]
+	s.recv = s.recv[1:]
+
+	return err
+}
+
+func TestReconnectingStream(t *testing.T) {
+	ctx := context.Background()
+	errUnavailable := xerrors.Transport(grpcStatus.Error(grpcCodes.Unavailable, "test"))
+
+	t.Run("ReplayInitialization", func(t *testing.T) {
+		streams := []*streamMock{
+			{recv: []error{errUnavailable}},
+			{recv: []error{nil, errUnavailable}},
+			{recv: []error{nil}},
+		}
+		var (
+			opened int
+			banned []string
+		)
+		s, err := newReconnectingStream(ctx, func(ctx context.Context) (grpc.ClientStream, conn.Conn, error) {
+			opened++
+
+			return streams[opened-1], &mock.Conn{AddrField: strconv.Itoa(opened)}, nil
+		}, func(ctx context.Context, cc conn.Conn, err error) {
+			banned = append(banned, cc.Endpoint().Address())
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, s.SendMsg("init"))
+		require.NoError(t, s.CloseSend())
+		require.NoError(t, s.RecvMsg(nil))
+		require.Equal(t, 2, opened)
+		require.Equal(t, []string{"1"}, banned)
+		require.Equal(t, []interface{}{"init"}, streams[1].sent)
+		require.True(t, streams[1].closeSend)
+	})
+
+	t.Run("ReconnectAfterRecv", func(t *testing.T) {
+		streams := []*streamMock{
+			{recv: []error{nil, errUnavailable}},
+			{recv: []error{nil}},
+		}
+		var (
+			opened int
+			banned []string
+		)
+		s, err := newReconnectingStream(ctx, func(ctx context.Context) (grpc.ClientStream, conn.Conn, error) {
+			opened++
+
+			return streams[opened-1], &mock.Conn{AddrField: strconv.Itoa(opened)}, nil
+		}, func(ctx context.Context, cc conn.Conn, err error) {
+			banned = append(banned, cc.Endpoint().Address())
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, s.SendMsg("init"))
+		require.NoError(t, s.RecvMsg(nil))
+		// initialization completed, so messages sent after first received message not replayed
+		require.NoError(t, s.SendMsg("data"))
+		require.NoError(t, s.RecvMsg(nil))
+		require.Equal(t, 2, opened)
+		require.Equal(t, []string{"1"}, banned)
+		require.Equal(t, []interface{}{"init"}, streams[1].sent)
+		require.False(t, streams[1].closeSend)
+	})
+
+	t.Run("NonRetryable", func(t *testing.T) {
+		errTest := errors.New("test")
+		opened := 0
+		s, err := newReconnectingStream(ctx, func(ctx context.Context) (grpc.ClientStream, conn.Conn, error) {
+			opened++
+
+			return &streamMock{recv: []error{errTest}}, &mock.Conn{}, nil
+		}, nil)
+		require.NoError(t, err)
+		require.ErrorIs(t, s.RecvMsg(nil), errTest)
+		require.Equal(t, 1, opened)
+	})
+
+	t.Run("MaxReconnects", func(t *testing.T) {
+		opened := 0
+		s, err := newReconnectingStream(ctx, func(ctx context.Context) (grpc.ClientStream, conn.Conn, error) {
+			opened++
+
+			return &streamMock{recv: []error{errUnavailable}}, &mock.Conn{}, nil
+		}, nil)
+		require.NoError(t, err)
+		require.True(t, isReconnectable(s.RecvMsg(nil)))
+		require.Equal(t, maxStreamReconnects+1, opened)
+	})
+
+	t.Run("ReopenFailed", func(t *testing.T) {
+		errOpen := errors.New("open")
+		opened := 0
+		s, err := newReconnectingStream(ctx, func(ctx context.Context) (grpc.ClientStream, conn.Conn, error) {
+			opened++
+			if opened > 1 {
+				return nil, nil, errOpen
+			}
+
+			return &streamMock{recv: []error{errUnavailable}}, &mock.Conn{}, nil
+		}, nil)
+		require.NoError(t, err)
+		require.ErrorIs(t, s.RecvMsg(nil), errOpen)
+	})
+}
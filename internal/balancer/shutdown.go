@@ -0,0 +1,136 @@
+package balancer
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/stack"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xcontext"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xerrors"
+	"github.com/ydb-platform/ydb-go-sdk/v3/trace"
+)
+
+// closeTimeout is a maximum duration of close of discovery client and release of connections
+const closeTimeout = 5 * time.Second
+
+// closeContext makes context for close of discovery client and connections which is not cancelled with parent
+// context, so cancellation of parent context (such as during discovery) never leaks connections
+func closeContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return xcontext.WithTimeout(xcontext.ValueOnly(ctx), closeTimeout)
+}
+
+// inFlightCalls counts in-flight calls of balancer for graceful shutdown.
+// Zero value is ready for use
+type inFlightCalls struct {
+	mu      sync.Mutex
+	count   int
+	closed  bool
+	drained chan struct{}
+}
+
+// add registers new call. Returns false if balancer shutting down and call must be refused
+func (c *inFlightCalls) add() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return false
+	}
+
+	c.count++
+
+	return true
+}
+
+func (c *inFlightCalls) done() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.count--
+	if c.closed && c.count == 0 {
+		close(c.drained)
+	}
+}
+
+// close refuses new calls and returns channel which closes after completion of all in-flight calls
+func (c *inFlightCalls) close() <-chan struct{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.closed {
+		c.closed = true
+		c.drained = make(chan struct{})
+		if c.count == 0 {
+			close(c.drained)
+		}
+	}
+
+	return c.drained
+}
+
+func (c *inFlightCalls) isClosed() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.closed
+}
+
+// BeginShutdown switches balancer to fast-fail mode: new calls immediately fail with conn.ErrShuttingDown
+// without choice of connection, but in-flight calls continue. Unlike Shutdown, BeginShutdown does not wait
+// for in-flight calls and does not close balancer
+func (b *Balancer) BeginShutdown() {
+	b.shuttingDown.Store(true)
+}
+
+// Shutdown gracefully closes balancer: stops cluster discovery, refuses new calls and waits for completion
+// of in-flight calls up to deadline of context. After that balancer closes discovery client and releases
+// connections pool
+func (b *Balancer) Shutdown(ctx context.Context) (err error) {
+	onDone := trace.DriverOnBalancerClose(
+		b.driverConfig.Trace(), &ctx,
+		stack.FunctionID("github.com/ydb-platform/ydb-go-sdk/v3/internal/balancer.(*Balancer).Shutdown"),
+	)
+	defer func() {
+		onDone(err)
+	}()
+
+	if b.discoveryRepeater != nil {
+		b.discoveryRepeater.Stop()
+	}
+
+	b.drainer.stop()
+	b.warmer.stop()
+	b.readiness.stop()
+	b.banRecovery.stop()
+
+	select {
+	case <-b.calls.close():
+	case <-ctx.Done():
+	}
+
+	ctx, cancel := closeContext(ctx)
+	defer cancel()
+
+	var issues []error
+	if err = b.discoveryClient.Close(ctx); err != nil {
+		issues = append(issues, err)
+	}
+
+	if b.releasePool != nil {
+		b.releasePool.Do(func() {
+			if err = b.pool.Release(ctx); err != nil {
+				issues = append(issues, err)
+			}
+		})
+	}
+
+	switch len(issues) {
+	case 0:
+		return nil
+	case 1:
+		return xerrors.WithStackTrace(issues[0])
+	default:
+		return xerrors.WithStackTrace(xerrors.NewWithIssues("balancer shutdown failed", issues...))
+	}
+}
@@ -0,0 +1,197 @@
+package balancer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/balancers"
+	"github.com/ydb-platform/ydb-go-sdk/v3/config"
+	balancerConfig "github.com/ydb-platform/ydb-go-sdk/v3/internal/balancer/config"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/conn"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/endpoint"
+)
+
+func TestShutdown(t *testing.T) {
+	ctx := context.Background()
+	newBalancer := func(t *testing.T) (*Balancer, *conn.Pool) {
+		cfg := config.New(
+			config.WithEndpoint("initial:2135"),
+			config.WithBalancer(balancers.Default().With(balancerConfig.WithDiscoveryClient(
+				func(ctx context.Context) (balancerConfig.DiscoveryClient, error) {
+					return discoveryMock{endpoints: []endpoint.Endpoint{
+						endpoint.New("a:123", endpoint.WithID(1)),
+					}}, nil
+				},
+			))),
+		)
+		pool := conn.NewPool(ctx, cfg)
+		b, err := New(ctx, cfg, pool)
+		require.NoError(t, err)
+
+		// release of pool by owner (such as driver), pool closes after release by balancer
+		require.NoError(t, pool.Release(ctx))
+
+		return b, pool
+	}
+
+	t.Run("WaitInFlightCalls", func(t *testing.T) {
+		b, pool := newBalancer(t)
+
+		started := make(chan struct{})
+		finish := make(chan struct{})
+		callDone := make(chan error, 1)
+		go func() {
+			_, err := b.wrapCall(ctx, "", func(ctx context.Context, cc conn.Conn) error {
+				close(started)
+				<-finish
+
+				return nil
+			})
+			callDone <- err
+		}()
+		<-started
+
+		shutdownDone := make(chan error, 1)
+		go func() {
+			shutdownDone <- b.Shutdown(ctx)
+		}()
+
+		require.Eventually(t, b.calls.isClosed, time.Second, time.Millisecond)
+		_, err := b.wrapCall(ctx, "", func(ctx context.Context, cc conn.Conn) error {
+			return nil
+		})
+		require.ErrorIs(t, err, ErrClosed)
+
+		select {
+		case <-shutdownDone:
+			t.Fatal("shutdown completed before in-flight call")
+		case <-time.After(10 * time.Millisecond):
+		}
+		cc := pool.Get(endpoint.New("a:123", endpoint.WithID(1)))
+		require.NotEqual(t, conn.Destroyed, cc.GetState())
+
+		close(finish)
+		require.NoError(t, <-callDone)
+		require.NoError(t, <-shutdownDone)
+		require.Equal(t, conn.Destroyed, cc.GetState())
+	})
+
+	t.Run("Deadline", func(t *testing.T) {
+		b, _ := newBalancer(t)
+
+		finish := make(chan struct{})
+		defer close(finish)
+		started := make(chan struct{})
+		go func() {
+			_, _ = b.wrapCall(ctx, "", func(ctx context.Context, cc conn.Conn) error {
+				close(started)
+				<-finish
+
+				return nil
+			})
+		}()
+		<-started
+
+		shutdownCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+		require.NoError(t, b.Shutdown(shutdownCtx))
+	})
+
+	t.Run("BeginShutdown", func(t *testing.T) {
+		b, _ := newBalancer(t)
+
+		started := make(chan struct{})
+		finish := make(chan struct{})
+		callDone := make(chan error, 1)
+		go func() {
+			_, err := b.wrapCall(ctx, "", func(ctx context.Context, cc conn.Conn) error {
+				close(started)
+				<-finish
+
+				return nil
+			})
+			callDone <- err
+		}()
+		<-started
+
+		b.BeginShutdown()
+		_, err := b.getConn(ctx)
+		require.ErrorIs(t, err, conn.ErrShuttingDown)
+		_, err = b.wrapCall(ctx, "", func(ctx context.Context, cc conn.Conn) error {
+			return nil
+		})
+		require.ErrorIs(t, err, conn.ErrShuttingDown)
+
+		close(finish)
+		require.NoError(t, <-callDone)
+		require.NoError(t, b.Close(ctx))
+	})
+
+	t.Run("Close", func(t *testing.T) {
+		b, _ := newBalancer(t)
+		require.NoError(t, b.Close(ctx))
+		require.NoError(t, b.Close(ctx))
+
+		_, err := b.getConn(ctx)
+		require.ErrorIs(t, err, ErrClosed)
+	})
+}
+
+type blockingDiscoveryMock struct {
+	started chan struct{}
+	// closed receives error of context of close at the moment of close
+	closed chan error
+}
+
+func (d *blockingDiscoveryMock) Discover(ctx context.Context) ([]endpoint.Endpoint, error) {
+	close(d.started)
+	<-ctx.Done()
+
+	return nil, ctx.Err()
+}
+
+func (d *blockingDiscoveryMock) Close(ctx context.Context) error {
+	if _, hasDeadline := ctx.Deadline(); !hasDeadline {
+		d.closed <- errors.New("close without deadline")
+	} else {
+		d.closed <- ctx.Err()
+	}
+
+	return nil
+}
+
+func TestCancelInitialDiscovery(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	discovery := &blockingDiscoveryMock{
+		started: make(chan struct{}),
+		closed:  make(chan error, 1),
+	}
+	cfg := config.New(
+		config.WithEndpoint("initial:2135"),
+		config.WithBalancer(balancers.Default().With(balancerConfig.WithDiscoveryClient(
+			func(ctx context.Context) (balancerConfig.DiscoveryClient, error) {
+				return discovery, nil
+			},
+		))),
+	)
+	pool := conn.NewPool(context.Background(), cfg)
+	cc := pool.Get(endpoint.New("initial:2135"))
+
+	go func() {
+		<-discovery.started
+		cancel()
+	}()
+	_, err := New(ctx, cfg, pool)
+	require.ErrorIs(t, err, context.Canceled)
+
+	require.NoError(t, <-discovery.closed)
+
+	// release of pool by owner (such as driver) closes connections after release by failed balancer
+	require.NotEqual(t, conn.Destroyed, cc.GetState())
+	require.NoError(t, pool.Release(ctx))
+	require.Equal(t, conn.Destroyed, cc.GetState())
+}
@@ -0,0 +1,127 @@
+package balancer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	balancerConfig "github.com/ydb-platform/ydb-go-sdk/v3/internal/balancer/config"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/endpoint"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xerrors"
+)
+
+// stateSnapshotVersion is a version of format of balancer state snapshot.
+// Version must be incremented on every incompatible change of format
+const stateSnapshotVersion = 1
+
+// ErrIncompatibleState returned on restore of balancer state from snapshot of unknown format or version
+var ErrIncompatibleState = xerrors.Wrap(fmt.Errorf("incompatible balancer state snapshot"))
+
+type stateSnapshot struct {
+	Version   int                     `json:"version"`
+	LocalDC   string                  `json:"local_dc,omitempty"`
+	Locality  []string                `json:"locality,omitempty"`
+	Endpoints []stateSnapshotEndpoint `json:"endpoints"`
+}
+
+type stateSnapshotEndpoint struct {
+	Address    string  `json:"address"`
+	Location   string  `json:"location,omitempty"`
+	NodeID     uint32  `json:"node_id,omitempty"`
+	LoadFactor float32 `json:"load_factor,omitempty"`
+}
+
+// ExportState serializes discovered endpoints and local DC of balancer into versioned snapshot.
+// Snapshot seeds balancer state of other process (such as on blue/green deploy) with WithInitialState option
+func (b *Balancer) ExportState() ([]byte, error) {
+	snapshot := stateSnapshot{
+		Version:   stateSnapshotVersion,
+		Endpoints: []stateSnapshotEndpoint{},
+	}
+
+	if state := b.connections(); state != nil {
+		snapshot.LocalDC = state.localDC
+		snapshot.Locality = state.selfLocality
+		for _, e := range state.discovered {
+			snapshot.Endpoints = append(snapshot.Endpoints, stateSnapshotEndpoint{
+				Address:    e.Address(),
+				Location:   e.Location(),
+				NodeID:     e.NodeID(),
+				LoadFactor: e.LoadFactor(),
+			})
+		}
+	}
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, xerrors.WithStackTrace(err)
+	}
+
+	return data, nil
+}
+
+// parseStateSnapshot parses snapshot of balancer state into endpoints and info of local DC
+func parseStateSnapshot(data []byte) (endpoints []endpoint.Endpoint, info balancerConfig.Info, err error) {
+	var snapshot stateSnapshot
+	if err = json.Unmarshal(data, &snapshot); err != nil {
+		return nil, info, xerrors.WithStackTrace(fmt.Errorf("%w: %w", ErrIncompatibleState, err))
+	}
+
+	if snapshot.Version != stateSnapshotVersion {
+		return nil, info, xerrors.WithStackTrace(fmt.Errorf("%w: version %d, expected %d",
+			ErrIncompatibleState, snapshot.Version, stateSnapshotVersion,
+		))
+	}
+
+	endpoints = make([]endpoint.Endpoint, 0, len(snapshot.Endpoints))
+	for _, e := range snapshot.Endpoints {
+		endpoints = append(endpoints, endpoint.New(e.Address,
+			endpoint.WithLocation(e.Location),
+			endpoint.WithID(e.NodeID),
+			endpoint.WithLoadFactor(e.LoadFactor),
+		))
+	}
+
+	return endpoints, balancerConfig.Info{SelfLocation: snapshot.LocalDC, SelfLocality: snapshot.Locality}, nil
+}
+
+// applyInitialState seeds balancer state from initial state snapshot without detection of local DC.
+// Returns false if initial state is not configured or cannot be used
+func (b *Balancer) applyInitialState(ctx context.Context) bool {
+	if len(b.config.InitialState) == 0 {
+		return false
+	}
+
+	endpoints, info, err := parseStateSnapshot(b.config.InitialState)
+	if err != nil || len(endpoints) == 0 {
+		return false
+	}
+
+	if !b.config.DetectNearestDC {
+		info = balancerConfig.Info{}
+	}
+
+	b.applyDiscoveredEndpoints(ctx, endpoints, info)
+	b.setFromCache()
+
+	return true
+}
+
+// handleRawDiscovery passes state snapshot of successful discovery into raw discovery handler.
+// Handler is a best-effort export of discovery result, so fails of export and panics of handler are not fatal
+func (b *Balancer) handleRawDiscovery() {
+	if b.rawDiscoveryHandler == nil {
+		return
+	}
+
+	data, err := b.ExportState()
+	if err != nil {
+		return
+	}
+
+	defer func() {
+		_ = recover()
+	}()
+
+	b.rawDiscoveryHandler(data)
+}
@@ -0,0 +1,101 @@
+package balancer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/balancers"
+	"github.com/ydb-platform/ydb-go-sdk/v3/config"
+	balancerConfig "github.com/ydb-platform/ydb-go-sdk/v3/internal/balancer/config"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/conn"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/endpoint"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/mock"
+)
+
+func TestStateSnapshot(t *testing.T) {
+	ctx := context.Background()
+	newBalancer := func(opts ...balancerConfig.Option) *Balancer {
+		cfg := config.New(config.WithBalancer(balancers.PreferNearestDC(balancers.Default()).With(opts...)))
+
+		return &Balancer{
+			driverConfig: cfg,
+			config:       *cfg.Balancer(),
+			pool:         conn.NewPool(context.Background(), cfg),
+			discoveryClient: discoveryMock{endpoints: []endpoint.Endpoint{
+				&mock.Endpoint{AddrField: "a:123", LocationField: "a", NodeIDField: 1},
+				&mock.Endpoint{AddrField: "b:234", LocationField: "b", NodeIDField: 2, LoadFactorField: 0.5},
+				&mock.Endpoint{AddrField: "b:345", LocationField: "b", NodeIDField: 3},
+			}},
+			localDCDetector: func(ctx context.Context, endpoints []endpoint.Endpoint) (string, error) {
+				return "b", nil
+			},
+		}
+	}
+
+	old := newBalancer()
+	require.NoError(t, old.clusterDiscoveryAttempt(ctx))
+	state, err := old.ExportState()
+	require.NoError(t, err)
+
+	b := newBalancer(balancerConfig.WithInitialState(state))
+	b.localDCDetector = func(ctx context.Context, endpoints []endpoint.Endpoint) (string, error) {
+		t.Fatal("local DC detector called with initial state")
+
+		return "", nil
+	}
+	require.True(t, b.applyInitialState(ctx))
+	require.Equal(t, "b", b.connections().localDC)
+	require.Equal(t, 2, b.connections().PreferredCount())
+
+	addresses := func(b *Balancer) []string {
+		var res []string
+		for _, e := range b.connections().All() {
+			res = append(res, e.Address())
+		}
+
+		return res
+	}
+	require.ElementsMatch(t, addresses(old), addresses(b))
+
+	restored, err := b.ExportState()
+	require.NoError(t, err)
+	require.JSONEq(t, string(state), string(restored))
+
+	t.Run("RawDiscoveryHandler", func(t *testing.T) {
+		var raw []byte
+		b := newBalancer()
+		b.rawDiscoveryHandler = func(response []byte) {
+			raw = response
+		}
+		require.NoError(t, b.clusterDiscoveryAttempt(ctx))
+		require.JSONEq(t, string(state), string(raw))
+
+		b.rawDiscoveryHandler = func(response []byte) {
+			panic("test")
+		}
+		require.NoError(t, b.clusterDiscoveryAttempt(ctx))
+	})
+	t.Run("Empty", func(t *testing.T) {
+		state, err := (&Balancer{}).ExportState()
+		require.NoError(t, err)
+		require.JSONEq(t, `{"version":1,"endpoints":[]}`, string(state))
+		require.False(t, newBalancer(balancerConfig.WithInitialState(state)).applyInitialState(ctx))
+	})
+	t.Run("NotConfigured", func(t *testing.T) {
+		require.False(t, newBalancer().applyInitialState(ctx))
+	})
+	t.Run("Incompatible", func(t *testing.T) {
+		for _, state := range []string{
+			`{"version":2,"endpoints":[{"address":"a:123"}]}`,
+			`{"endpoints":[{"address":"a:123"}]}`,
+			`[{"address":"a:123"}]`,
+			`garbage`,
+		} {
+			_, _, err := parseStateSnapshot([]byte(state))
+			require.ErrorIs(t, err, ErrIncompatibleState)
+			require.False(t, newBalancer(balancerConfig.WithInitialState([]byte(state))).applyInitialState(ctx))
+		}
+	})
+}
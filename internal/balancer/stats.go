@@ -0,0 +1,105 @@
+package balancer
+
+import (
+	"time"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/conn"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/endpoint"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xsync"
+)
+
+// BalancerStats is a snapshot of balancer state
+type BalancerStats struct {
+	// PreferredConnections is a count of connections preferred by balancer filter (such as connections in local DC)
+	PreferredConnections int
+
+	// FallbackConnections is a count of connections which used if no one preferred connection available
+	FallbackConnections int
+
+	// BannedConnections is a count of pessimized connections
+	BannedConnections int
+
+	// DrainingConnections is a count of connections to endpoints removed from discovery response
+	// which waits for drain timeout before close
+	DrainingConnections int
+
+	// LocalDC is a detected local DC. LocalDC is empty if detection of nearest DC disabled
+	LocalDC string
+
+	// LastDiscovery is a time of last successful discovery. LastDiscovery is zero if no one discovery completed
+	LastDiscovery time.Time
+
+	// InFlightCalls is a count of in-flight calls by address of endpoint of balancer connection
+	InFlightCalls map[string]int
+
+	// OpenStreams is a count of open streams by address of endpoint of balancer connection
+	OpenStreams map[string]int
+}
+
+// Stats returns snapshot of balancer state
+func (b *Balancer) Stats() BalancerStats {
+	return xsync.WithRLock(&b.mu, func() BalancerStats {
+		stats := b.connections().stats()
+		stats.LastDiscovery = b.lastDiscovery
+		stats.DrainingConnections = b.drainer.drainingCount()
+		stats.OpenStreams = b.streams.snapshot()
+
+		return stats
+	})
+}
+
+// BannedEndpoint describes endpoint which balancer considers banned
+type BannedEndpoint struct {
+	// Endpoint is an info of banned endpoint
+	Endpoint endpoint.Info
+
+	// BannedAt is a time of ban. BannedAt is zero if connection banned bypassing connections pool
+	BannedAt time.Time
+
+	// Cause is an error which caused ban
+	Cause error
+}
+
+// BannedEndpoints returns currently banned endpoints of balancer connections
+func (b *Balancer) BannedEndpoints() []BannedEndpoint {
+	return xsync.WithRLock(&b.mu, func() (banned []BannedEndpoint) {
+		state := b.connections()
+		if state == nil {
+			return nil
+		}
+
+		for _, c := range state.all {
+			if c.GetState() != conn.Banned {
+				continue
+			}
+			e := BannedEndpoint{
+				Endpoint: c.Endpoint().Copy(),
+			}
+			if ban, ok := b.pool.LastBan(c); ok {
+				e.BannedAt, e.Cause = ban.At, ban.Cause
+			}
+			banned = append(banned, e)
+		}
+
+		return banned
+	})
+}
+
+func (s *connectionsState) stats() (stats BalancerStats) {
+	if s == nil {
+		return stats
+	}
+
+	stats.PreferredConnections = len(s.prefer)
+	stats.FallbackConnections = len(s.fallback)
+	stats.LocalDC = s.localDC
+	stats.InFlightCalls = make(map[string]int, len(s.all))
+	for _, c := range s.all {
+		if c.GetState() == conn.Banned {
+			stats.BannedConnections++
+		}
+		stats.InFlightCalls[c.Endpoint().Address()] = c.InFlight()
+	}
+
+	return stats
+}
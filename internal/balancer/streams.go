@@ -0,0 +1,111 @@
+package balancer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/conn"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xerrors"
+)
+
+// ErrTooManyStreams returned if all balancer connections reached maximum count of open streams
+var ErrTooManyStreams = xerrors.Wrap(errors.New("too many streams"))
+
+type ctxStreamCallKey struct{}
+
+// withStreamCall marks context of choice of connection for new stream
+func withStreamCall(ctx context.Context) context.Context {
+	return context.WithValue(ctx, ctxStreamCallKey{}, true)
+}
+
+func isStreamCall(ctx context.Context) bool {
+	streamCall, _ := ctx.Value(ctxStreamCallKey{}).(bool)
+
+	return streamCall
+}
+
+// streamsCounter counts open streams by address of endpoint of connection.
+// Zero value is ready for use
+type streamsCounter struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+func (s *streamsCounter) count(address string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.counts[address]
+}
+
+// open registers open stream. Returned done must be called on end of stream
+func (s *streamsCounter) open(address string) (done func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.counts == nil {
+		s.counts = make(map[string]int)
+	}
+	s.counts[address]++
+
+	var once sync.Once
+
+	return func() {
+		once.Do(func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+
+			if s.counts[address]--; s.counts[address] <= 0 {
+				delete(s.counts, address)
+			}
+		})
+	}
+}
+
+func (s *streamsCounter) snapshot() map[string]int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.counts) == 0 {
+		return nil
+	}
+
+	counts := make(map[string]int, len(s.counts))
+	for address, count := range s.counts {
+		counts[address] = count
+	}
+
+	return counts
+}
+
+// streamConnection returns chosen connection if count of open streams through connection less than limit.
+// Otherwise streamConnection returns alive connection with the least count of open streams
+func (b *Balancer) streamConnection(state *connectionsState, c conn.Conn, limit int) (conn.Conn, error) {
+	if b.streams.count(c.Endpoint().Address()) < limit {
+		return c, nil
+	}
+
+	var (
+		leastLoaded conn.Conn
+		leastCount  = limit
+	)
+	for _, candidate := range state.all {
+		if !state.isOkConnection(candidate, false) {
+			continue
+		}
+		if count := b.streams.count(candidate.Endpoint().Address()); count < leastCount {
+			leastLoaded, leastCount = candidate, count
+		}
+	}
+
+	if leastLoaded == nil {
+		return nil, xerrors.WithStackTrace(xerrors.Retryable(
+			fmt.Errorf("%w: all connections reached maximum %d open streams", ErrTooManyStreams, limit),
+			xerrors.WithName("ErrTooManyStreams"),
+		))
+	}
+
+	return leastLoaded, nil
+}
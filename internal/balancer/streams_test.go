@@ -0,0 +1,68 @@
+package balancer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/config"
+	balancerConfig "github.com/ydb-platform/ydb-go-sdk/v3/internal/balancer/config"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/conn"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/mock"
+)
+
+func TestStreamsCounter(t *testing.T) {
+	var s streamsCounter
+	require.Nil(t, s.snapshot())
+
+	done1 := s.open("a")
+	done2 := s.open("a")
+	done3 := s.open("b")
+	require.Equal(t, 2, s.count("a"))
+	require.Equal(t, map[string]int{"a": 2, "b": 1}, s.snapshot())
+
+	done1()
+	done1()
+	require.Equal(t, 1, s.count("a"))
+
+	done2()
+	done3()
+	require.Nil(t, s.snapshot())
+}
+
+func TestMaxStreamsPerConn(t *testing.T) {
+	ctx := context.Background()
+	b := &Balancer{
+		driverConfig: config.New(),
+		config:       balancerConfig.Config{MaxStreamsPerConn: 2},
+	}
+	b.connectionsState.Store(newConnectionsState([]conn.Conn{
+		&mock.Conn{AddrField: "1", NodeIDField: 1, State: conn.Online},
+		&mock.Conn{AddrField: "2", NodeIDField: 2, State: conn.Online},
+		&mock.Conn{AddrField: "3", NodeIDField: 3, State: conn.Banned},
+	}, nil, balancerConfig.Info{}, false))
+
+	var dones []func()
+	for i := 0; i < 4; i++ {
+		c, err := b.getConn(withStreamCall(ctx))
+		require.NoError(t, err)
+		require.NotEqual(t, "3", c.Endpoint().Address())
+		dones = append(dones, b.streams.open(c.Endpoint().Address()))
+	}
+	require.Equal(t, map[string]int{"1": 2, "2": 2}, b.streams.snapshot())
+
+	_, err := b.getConn(withStreamCall(ctx))
+	require.ErrorIs(t, err, ErrTooManyStreams)
+
+	// limit not applies to unary calls
+	_, err = b.getConn(ctx)
+	require.NoError(t, err)
+
+	dones[0]()
+	for i := 0; i < 10; i++ {
+		c, err := b.getConn(withStreamCall(ctx))
+		require.NoError(t, err)
+		require.Equal(t, 1, b.streams.count(c.Endpoint().Address()))
+	}
+}
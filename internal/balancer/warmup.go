@@ -0,0 +1,64 @@
+package balancer
+
+import (
+	"context"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/conn"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xcontext"
+)
+
+// warmupConcurrency is a maximum count of concurrent dials of new connections
+const warmupConcurrency = 16
+
+// warmer dials new connections in background for avoid dial latency on first call to new endpoint.
+// Failures of dial not fail discovery and reports with OnConnDial trace only
+type warmer struct {
+	ctx    context.Context //nolint:containedctx
+	cancel context.CancelFunc
+	sem    chan struct{}
+}
+
+func newWarmer(ctx context.Context, concurrency int) *warmer {
+	ctx, cancel := xcontext.WithCancel(xcontext.ValueOnly(ctx))
+
+	return &warmer{
+		ctx:    ctx,
+		cancel: cancel,
+		sem:    make(chan struct{}, concurrency),
+	}
+}
+
+// warmup starts dial of never dialed connections
+func (w *warmer) warmup(conns []conn.Conn) {
+	if w == nil {
+		return
+	}
+
+	for _, c := range conns {
+		if !c.IsState(conn.Created) {
+			continue
+		}
+		go w.dial(c)
+	}
+}
+
+func (w *warmer) dial(c conn.Conn) {
+	select {
+	case <-w.ctx.Done():
+		return
+	case w.sem <- struct{}{}:
+	}
+	defer func() {
+		<-w.sem
+	}()
+
+	_ = c.Ping(w.ctx)
+}
+
+func (w *warmer) stop() {
+	if w == nil {
+		return
+	}
+
+	w.cancel()
+}
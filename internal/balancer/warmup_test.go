@@ -0,0 +1,44 @@
+package balancer
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/config"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/conn"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/mock"
+)
+
+func TestWarmer(t *testing.T) {
+	ctx := context.Background()
+	listen, err := net.ListenTCP("tcp", &net.TCPAddr{IP: localIP})
+	require.NoError(t, err)
+	defer func() { _ = listen.Close() }()
+
+	cfg := config.New()
+	pool := conn.NewPool(ctx, cfg)
+	defer func() { _ = pool.Release(ctx) }()
+
+	created := pool.Get(&mock.Endpoint{AddrField: listen.Addr().String(), NodeIDField: 1})
+	banned := pool.Get(&mock.Endpoint{AddrField: "banned:123", NodeIDField: 2})
+	banned.SetState(ctx, conn.Banned)
+
+	w := newWarmer(ctx, 1)
+	defer w.stop()
+
+	w.warmup([]conn.Conn{created, banned})
+	require.Eventually(t, func() bool {
+		return created.IsState(conn.Online)
+	}, time.Second, 10*time.Millisecond)
+	require.Equal(t, conn.Banned, banned.GetState())
+
+	t.Run("Disabled", func(t *testing.T) {
+		var w *warmer
+		w.warmup([]conn.Conn{created})
+		w.stop()
+	})
+}
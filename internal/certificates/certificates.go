@@ -1,8 +1,10 @@
 package certificates
 
 import (
+	"bytes"
 	"crypto/x509"
 	"encoding/pem"
+	"errors"
 	"fmt"
 	"os"
 	"sync"
@@ -10,6 +12,9 @@ import (
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xerrors"
 )
 
+// ErrMalformedPem returned if pem-encoded data contains malformed or unexpected blocks
+var ErrMalformedPem = xerrors.Wrap(errors.New("malformed pem-encoded certificates"))
+
 var (
 	// fileCache stores certificates by file name
 	fileCache sync.Map
@@ -22,6 +27,7 @@ type (
 		onHit   func()
 		onMiss  func()
 		noCache bool
+		strict  bool
 	}
 	FromFileOption func(opts *fromFileOptions)
 )
@@ -148,6 +154,7 @@ type (
 		onHit   func()
 		onMiss  func()
 		noCache bool
+		strict  bool
 	}
 	FromPemOption func(opts *fromPemOptions)
 )
@@ -170,10 +177,28 @@ func FromPemNoCache(noCache bool) FromPemOption {
 	}
 }
 
+// FromPemStrict makes FromPem to return ErrMalformedPem if bytes contains no one certificate,
+// non-certificate block, unparsed certificate or data outside of pem blocks
+func FromPemStrict(strict bool) FromPemOption {
+	return func(opts *fromPemOptions) {
+		opts.strict = strict
+	}
+}
+
 // FromPem parses one or more certificate from pem blocks in bytes.
 // It returns nil error if at least one certificate was successfully parsed.
 // This function uses cached parseCertificate.
 func FromPem(bytes []byte, opts ...FromPemOption) (certs []*x509.Certificate, err error) {
+	options := fromPemOptions{}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&options)
+		}
+	}
+	if options.strict {
+		return fromPemStrict(bytes, opts...)
+	}
+
 	var block *pem.Block
 
 	for len(bytes) > 0 {
@@ -198,3 +223,33 @@ func FromPem(bytes []byte, opts ...FromPemOption) (certs []*x509.Certificate, er
 
 	return certs, nil
 }
+
+// fromPemStrict parses all pem blocks in bytes as certificates and returns ErrMalformedPem
+// if bytes contains no one certificate, non-certificate block, unparsed certificate or data outside of pem blocks
+func fromPemStrict(data []byte, opts ...FromPemOption) ([]*x509.Certificate, error) {
+	var (
+		block *pem.Block
+		certs []*x509.Certificate
+	)
+
+	for len(bytes.TrimSpace(data)) > 0 {
+		block, data = pem.Decode(data)
+		if block == nil {
+			return nil, xerrors.WithStackTrace(fmt.Errorf("%w: unexpected data outside of pem blocks", ErrMalformedPem))
+		}
+		if block.Type != "CERTIFICATE" {
+			return nil, xerrors.WithStackTrace(fmt.Errorf("%w: unexpected block type %q", ErrMalformedPem, block.Type))
+		}
+		cert, err := parseCertificate(block.Bytes, opts...)
+		if err != nil {
+			return nil, xerrors.WithStackTrace(fmt.Errorf("%w: %w", ErrMalformedPem, err))
+		}
+		certs = append(certs, cert)
+	}
+
+	if len(certs) == 0 {
+		return nil, xerrors.WithStackTrace(fmt.Errorf("%w: no certificates", ErrMalformedPem))
+	}
+
+	return certs, nil
+}
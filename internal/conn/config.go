@@ -1,10 +1,12 @@
 package conn
 
 import (
+	"context"
 	"time"
 
 	"google.golang.org/grpc"
 
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/endpoint"
 	"github.com/ydb-platform/ydb-go-sdk/v3/trace"
 )
 
@@ -13,4 +15,7 @@ type Config interface {
 	ConnectionTTL() time.Duration
 	Trace() *trace.Driver
 	GrpcDialOptions() []grpc.DialOption
+	DialTarget(address string) string
+	// DialGuard returns check of endpoint before dial, nil means dial without check
+	DialGuard() func(ctx context.Context, e endpoint.Endpoint) error
 }
@@ -40,12 +40,19 @@ type Conn interface {
 	Endpoint() endpoint.Endpoint
 
 	LastUsage() time.Time
+	// Touch marks connection as used now (such as on choice of connection for call by balancer)
+	Touch()
 
 	Ping(ctx context.Context) error
 	IsState(states ...State) bool
 	GetState() State
 	SetState(ctx context.Context, state State) State
 	Unban(ctx context.Context) State
+
+	// InFlight returns count of in-flight calls through connection
+	InFlight() int
+	// TrackInFlight registers in-flight call through connection. Returned done must be called on end of call
+	TrackInFlight() (done func())
 }
 
 type conn struct {
@@ -56,10 +63,12 @@ type conn struct {
 	endpoint          endpoint.Endpoint // ro access
 	closed            bool
 	state             atomic.Uint32
+	inFlight          atomic.Int64
 	childStreams      *xcontext.CancelsGuard
 	lastUsage         xsync.LastUsage
 	onClose           []func(*conn)
 	onTransportErrors []func(ctx context.Context, cc Conn, cause error)
+	onDialRefused     []func(ctx context.Context, cc Conn, cause error)
 }
 
 func (c *conn) Address() string {
@@ -78,6 +87,10 @@ func (c *conn) Ping(ctx context.Context) error {
 	return nil
 }
 
+func (c *conn) Touch() {
+	c.lastUsage.Start()()
+}
+
 func (c *conn) LastUsage() time.Time {
 	c.mtx.RLock()
 	defer c.mtx.RUnlock()
@@ -141,6 +154,18 @@ func (c *conn) Endpoint() endpoint.Endpoint {
 	return nil
 }
 
+func (c *conn) InFlight() int {
+	return int(c.inFlight.Load())
+}
+
+func (c *conn) TrackInFlight() (done func()) {
+	c.inFlight.Add(1)
+
+	return func() {
+		c.inFlight.Add(-1)
+	}
+}
+
 func (c *conn) SetState(ctx context.Context, s State) State {
 	return c.setState(ctx, s)
 }
@@ -195,20 +220,36 @@ func (c *conn) realConn(ctx context.Context) (cc *grpc.ClientConn, err error) {
 		defer cancel()
 	}
 
-	onDone := trace.DriverOnConnDial(
-		c.config.Trace(), &ctx,
-		stack.FunctionID("github.com/ydb-platform/ydb-go-sdk/v3/internal/conn.(*conn).realConn"),
-		c.endpoint.Copy(),
+	var (
+		onDone = trace.DriverOnConnDial(
+			c.config.Trace(), &ctx,
+			stack.FunctionID("github.com/ydb-platform/ydb-go-sdk/v3/internal/conn.(*conn).realConn"),
+			c.endpoint.Copy(),
+		)
+		start = time.Now()
 	)
 	defer func() {
-		onDone(err)
+		onDone(err, time.Since(start))
 	}()
 
-	// prepend "ydb" scheme for grpc dns-resolver to find the proper scheme
-	// three slashes in "ydb:///" is ok. It needs for good parse scheme in grpc resolver.
-	address := "ydb:///" + c.endpoint.Address()
+	if guard := c.config.DialGuard(); guard != nil {
+		if err = guard(ctx, c.endpoint.Copy()); err != nil {
+			err = fmt.Errorf("%w: %w", ErrDialRefused, err)
+
+			defer func() {
+				if _, direct := DirectEndpoint(ctx); direct {
+					return
+				}
+				for _, onDialRefused := range c.onDialRefused {
+					onDialRefused(ctx, c, err)
+				}
+			}()
+
+			return nil, xerrors.WithStackTrace(err)
+		}
+	}
 
-	cc, err = grpc.DialContext(ctx, address, append( //nolint:staticcheck,nolintlint
+	cc, err = grpc.DialContext(ctx, c.config.DialTarget(c.endpoint.Address()), append( //nolint:staticcheck,nolintlint
 		[]grpc.DialOption{
 			grpc.WithStatsHandler(statsHandler{}),
 		}, c.config.GrpcDialOptions()...,
@@ -233,10 +274,37 @@ func (c *conn) realConn(ctx context.Context) (cc *grpc.ClientConn, err error) {
 	c.grpcConn = cc
 	c.setState(ctx, Online)
 
+	if c.config.Trace().OnConnGrpcStateChange != nil {
+		go c.watchGrpcState(xcontext.ValueOnly(ctx), cc)
+	}
+
 	return c.grpcConn, nil
 }
 
+// watchGrpcState reports connectivity state changes of gRPC connection (such as on GOAWAY from server)
+// until gRPC connection shutdown
+func (c *conn) watchGrpcState(ctx context.Context, cc *grpc.ClientConn) {
+	for state := cc.GetState(); state != connectivity.Shutdown; {
+		if !cc.WaitForStateChange(ctx, state) {
+			return
+		}
+
+		state = cc.GetState()
+
+		trace.DriverOnConnGrpcStateChange(
+			c.config.Trace(), &ctx,
+			stack.FunctionID("github.com/ydb-platform/ydb-go-sdk/v3/internal/conn.(*conn).watchGrpcState"),
+			c.endpoint.Copy(), state,
+		)
+	}
+}
+
 func (c *conn) onTransportError(ctx context.Context, cause error) {
+	if _, direct := DirectEndpoint(ctx); direct {
+		// direct calls bypass balancer, so errors of direct calls not ban connection
+		return
+	}
+
 	for _, onTransportError := range c.onTransportErrors {
 		onTransportError(ctx, c, cause)
 	}
@@ -568,6 +636,14 @@ func withOnTransportError(onTransportError func(ctx context.Context, cc Conn, ca
 	}
 }
 
+func withOnDialRefused(onDialRefused func(ctx context.Context, cc Conn, cause error)) option {
+	return func(c *conn) {
+		if onDialRefused != nil {
+			c.onDialRefused = append(c.onDialRefused, onDialRefused)
+		}
+	}
+}
+
 func newConn(e endpoint.Endpoint, config Config, opts ...option) *conn {
 	c := &conn{
 		endpoint:     e,
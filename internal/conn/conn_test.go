@@ -3,7 +3,10 @@ package conn
 import (
 	"context"
 	"fmt"
+	"net"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"github.com/ydb-platform/ydb-go-genproto/Ydb_Discovery_V1"
@@ -15,10 +18,14 @@ import (
 	"go.uber.org/mock/gomock"
 	"google.golang.org/grpc"
 	grpcCodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials/insecure"
 	grpcStatus "google.golang.org/grpc/status"
 
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/endpoint"
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xerrors"
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xtest"
+	"github.com/ydb-platform/ydb-go-sdk/v3/trace"
 )
 
 //go:generate mockgen -destination grpc_client_conn_interface_mock_test.go --typed -package conn -write_package_comment=false google.golang.org/grpc ClientConnInterface
@@ -150,3 +157,93 @@ func TestConn(t *testing.T) {
 		})
 	})
 }
+
+type configMock struct {
+	trace *trace.Driver
+}
+
+func (c configMock) DialTimeout() time.Duration {
+	return 0
+}
+
+func (c configMock) ConnectionTTL() time.Duration {
+	return 0
+}
+
+func (c configMock) Trace() *trace.Driver {
+	return c.trace
+}
+
+func (c configMock) GrpcDialOptions() []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	}
+}
+
+func (c configMock) DialTarget(address string) string {
+	return address
+}
+
+func (c configMock) DialGuard() func(ctx context.Context, e endpoint.Endpoint) error {
+	return nil
+}
+
+func TestConnGrpcStateChange(t *testing.T) {
+	ctx := xtest.Context(t)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	srv := grpc.NewServer()
+	go func() {
+		_ = srv.Serve(lis)
+	}()
+
+	var (
+		mu     sync.Mutex
+		states []string
+	)
+	hasState := func(state connectivity.State) func() bool {
+		return func() bool {
+			mu.Lock()
+			defer mu.Unlock()
+
+			return len(states) > 0 && states[len(states)-1] == lis.Addr().String()+":"+state.String()
+		}
+	}
+	c := newConn(endpoint.New(lis.Addr().String()), configMock{
+		trace: &trace.Driver{
+			OnConnGrpcStateChange: func(info trace.DriverConnGrpcStateChangeInfo) {
+				mu.Lock()
+				defer mu.Unlock()
+
+				states = append(states, info.Endpoint.Address()+":"+info.State.String())
+			},
+		},
+	})
+
+	cc, err := c.realConn(ctx)
+	require.NoError(t, err)
+
+	cc.Connect()
+	require.Eventually(t, hasState(connectivity.Ready), time.Second, time.Millisecond)
+
+	srv.GracefulStop()
+	require.Eventually(t, hasState(connectivity.Idle), time.Second, time.Millisecond)
+
+	require.NoError(t, c.Close(ctx))
+	require.Eventually(t, hasState(connectivity.Shutdown), time.Second, time.Millisecond)
+}
+
+func TestDirectEndpointTransportError(t *testing.T) {
+	var banned int
+	c := newConn(endpoint.New("a:1"), nil, withOnTransportError(func(ctx context.Context, cc Conn, cause error) {
+		banned++
+	}))
+	err := xerrors.Transport(grpcStatus.Error(grpcCodes.Unavailable, ""))
+
+	c.onTransportError(WithDirectEndpoint(context.Background(), "a:1"), err)
+	require.Zero(t, banned)
+
+	c.onTransportError(context.Background(), err)
+	require.Equal(t, 1, banned)
+}
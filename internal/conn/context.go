@@ -1,8 +1,30 @@
 package conn
 
-import "context"
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
 
-type ctxNoWrappingKey struct{}
+	grpcCodes "google.golang.org/grpc/codes"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/endpoint"
+)
+
+type (
+	ctxNoWrappingKey         struct{}
+	ctxPessimizationCodesKey struct{}
+	ctxEndpointAffinityKey   struct{}
+	ctxPreferReadReplicaKey  struct{}
+	ctxHedgingKey            struct{}
+	ctxShardKey              struct{}
+	ctxEndpointAttemptsKey   struct{}
+	ctxAcquireTimeoutKey     struct{}
+	ctxAutoReconnectKey      struct{}
+	ctxRequestTagsKey        struct{}
+	ctxStrictNodeIDKey       struct{}
+	ctxDirectEndpointKey     struct{}
+)
 
 func WithoutWrapping(ctx context.Context) context.Context {
 	return context.WithValue(ctx, ctxNoWrappingKey{}, true)
@@ -13,3 +35,197 @@ func UseWrapping(ctx context.Context) bool {
 
 	return !ok || !b
 }
+
+// WithPessimizationCodes returns a copy of parent context with grpc codes which triggers
+// pessimization of endpoint on call with this context. Codes from context overrides driver config
+func WithPessimizationCodes(ctx context.Context, codes ...grpcCodes.Code) context.Context {
+	return context.WithValue(ctx, ctxPessimizationCodesKey{}, codes)
+}
+
+func PessimizationCodes(ctx context.Context) (codes []grpcCodes.Code, has bool) {
+	codes, has = ctx.Value(ctxPessimizationCodesKey{}).([]grpcCodes.Code)
+
+	return codes, has
+}
+
+// WithEndpointAffinity returns a copy of parent context with affinity key.
+// Balancer routes calls with same affinity key to same connection while it alive
+func WithEndpointAffinity(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, ctxEndpointAffinityKey{}, key)
+}
+
+func EndpointAffinity(ctx context.Context) (key string, has bool) {
+	key, has = ctx.Value(ctxEndpointAffinityKey{}).(string)
+
+	return key, has
+}
+
+// WithPreferReadReplica returns a copy of parent context with preference of read replica endpoints.
+// Balancer routes calls with this context to read replica endpoints if they available
+func WithPreferReadReplica(ctx context.Context) context.Context {
+	return context.WithValue(ctx, ctxPreferReadReplicaKey{}, true)
+}
+
+func PreferReadReplica(ctx context.Context) bool {
+	prefer, _ := ctx.Value(ctxPreferReadReplicaKey{}).(bool)
+
+	return prefer
+}
+
+// WithHedging returns a copy of parent context with hedging delay.
+// If idempotent unary call with this context not completed within delay - balancer invokes
+// hedged duplicate of call on other connection. Result of first completed call returns, other call cancels
+func WithHedging(ctx context.Context, delay time.Duration) context.Context {
+	return context.WithValue(ctx, ctxHedgingKey{}, delay)
+}
+
+func Hedging(ctx context.Context) (delay time.Duration, has bool) {
+	delay, has = ctx.Value(ctxHedgingKey{}).(time.Duration)
+
+	return delay, has
+}
+
+// WithDirectEndpoint returns a copy of parent context with address (host:port) of endpoint for direct calls.
+// Balancer invokes calls with this context on connection to this exact address (pooled connection reuses
+// if exists) without discovery and choice of connection. Transport errors of direct calls not ban and
+// not pessimize connection. Direct endpoint is an expert escape hatch (such as for node-local introspection)
+func WithDirectEndpoint(ctx context.Context, address string) context.Context {
+	return context.WithValue(ctx, ctxDirectEndpointKey{}, address)
+}
+
+func DirectEndpoint(ctx context.Context) (address string, has bool) {
+	address, has = ctx.Value(ctxDirectEndpointKey{}).(string)
+
+	return address, has && address != ""
+}
+
+// WithShardKey returns a copy of parent context with sharding key.
+// Balancer routes calls with same sharding key to same alive connection with rendezvous hashing
+func WithShardKey(ctx context.Context, key []byte) context.Context {
+	return context.WithValue(ctx, ctxShardKey{}, key)
+}
+
+func ShardKey(ctx context.Context) (key []byte, has bool) {
+	key, has = ctx.Value(ctxShardKey{}).([]byte)
+
+	return key, has
+}
+
+// WithAcquireTimeout returns a copy of parent context with timeout of choice of connection for call.
+// Acquire timeout bounds only choice of connection, call uses remaining deadline of parent context
+func WithAcquireTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, ctxAcquireTimeoutKey{}, timeout)
+}
+
+func AcquireTimeout(ctx context.Context) (timeout time.Duration, has bool) {
+	timeout, has = ctx.Value(ctxAcquireTimeoutKey{}).(time.Duration)
+
+	return timeout, has
+}
+
+// WithAutoReconnect returns a copy of parent context with auto reconnect of streams.
+// Stream opened with this context on retryable transport error (such as loss of connection) re-opens
+// on other connection and replays messages sent before first received message (initialization of stream).
+// Delivery is at-least-once: replayed initialization messages and message which send failed may be
+// delivered twice, messages sent after initialization and before failure are not replayed.
+// Non-retryable errors returns to caller as is
+func WithAutoReconnect(ctx context.Context) context.Context {
+	return context.WithValue(ctx, ctxAutoReconnectKey{}, true)
+}
+
+func AutoReconnect(ctx context.Context) bool {
+	autoReconnect, _ := ctx.Value(ctxAutoReconnectKey{}).(bool)
+
+	return autoReconnect
+}
+
+// WithPreferNodeID returns a copy of parent context with preferred node ID of endpoint.
+// Balancer routes calls with this context to connection of node if it exists and alive (banned connection
+// of node is allowed), otherwise balancer uses default choice of connection
+func WithPreferNodeID(ctx context.Context, nodeID uint32) context.Context {
+	return endpoint.WithNodeID(ctx, nodeID)
+}
+
+// WithStrictNodeID returns a copy of parent context with strict node ID of endpoint.
+// Balancer routes calls with this context only to connection of node, calls fails with ErrNodeUnavailable
+// instead of default choice of connection if connection of node not exists or not alive
+func WithStrictNodeID(ctx context.Context, nodeID uint32) context.Context {
+	return context.WithValue(WithPreferNodeID(ctx, nodeID), ctxStrictNodeIDKey{}, true)
+}
+
+func StrictNodeID(ctx context.Context) bool {
+	strict, _ := ctx.Value(ctxStrictNodeIDKey{}).(bool)
+
+	return strict
+}
+
+// WithRequestTag returns a copy of parent context with request tag. Tags from context merges into outgoing
+// metadata of call with meta.HeaderRequestTagPrefix namespace. Key of tag is case-insensitive,
+// tag with same key overrides previous value
+func WithRequestTag(ctx context.Context, key, value string) context.Context {
+	parent, _ := ctx.Value(ctxRequestTagsKey{}).(map[string]string)
+	tags := make(map[string]string, len(parent)+1)
+	for k, v := range parent {
+		tags[k] = v
+	}
+	tags[strings.ToLower(key)] = value
+
+	return context.WithValue(ctx, ctxRequestTagsKey{}, tags)
+}
+
+func RequestTags(ctx context.Context) (tags map[string]string, has bool) {
+	tags, has = ctx.Value(ctxRequestTagsKey{}).(map[string]string)
+
+	return tags, has
+}
+
+// endpointAttempts contains distinct endpoints tried by logical operation (including retries)
+type endpointAttempts struct {
+	max int
+
+	mu    sync.Mutex
+	tried map[string]struct{}
+}
+
+// WithMaxEndpointAttempts returns a copy of parent context with limit of distinct endpoints for logical operation.
+// Counter of tried endpoints shares between all calls with this context (such as retries of operation),
+// so every logical operation must use own context from WithMaxEndpointAttempts
+func WithMaxEndpointAttempts(ctx context.Context, maxAttempts int) context.Context {
+	return context.WithValue(ctx, ctxEndpointAttemptsKey{}, &endpointAttempts{
+		max:   maxAttempts,
+		tried: make(map[string]struct{}, maxAttempts),
+	})
+}
+
+func MaxEndpointAttempts(ctx context.Context) (maxAttempts int, has bool) {
+	attempts, has := ctx.Value(ctxEndpointAttemptsKey{}).(*endpointAttempts)
+	if !has {
+		return 0, false
+	}
+
+	return attempts.max, true
+}
+
+// TryEndpoint registers attempt of call to endpoint with address.
+// Returns false if endpoint not tried yet and limit of distinct endpoints from context exhausted
+func TryEndpoint(ctx context.Context, address string) bool {
+	attempts, has := ctx.Value(ctxEndpointAttemptsKey{}).(*endpointAttempts)
+	if !has {
+		return true
+	}
+
+	attempts.mu.Lock()
+	defer attempts.mu.Unlock()
+
+	if _, tried := attempts.tried[address]; tried {
+		return true
+	}
+
+	if len(attempts.tried) >= attempts.max {
+		return false
+	}
+
+	attempts.tried[address] = struct{}{}
+
+	return true
+}
@@ -1,11 +1,38 @@
 package conn
 
 import (
+	"context"
+	"errors"
+	"fmt"
+
 	grpcCodes "google.golang.org/grpc/codes"
 
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xerrors"
 )
 
+// ErrAcquireTimeout returned if balancer cannot choose connection for call within acquire timeout from context
+var ErrAcquireTimeout = xerrors.Wrap(errors.New("connection acquire timeout"))
+
+// ErrNodeUnavailable returned if balancer cannot choose connection of strict node ID from context
+var ErrNodeUnavailable = xerrors.Wrap(errors.New("node unavailable"))
+
+// ErrShuttingDown returned if balancer refuses new calls after begin of shutdown of process
+var ErrShuttingDown = xerrors.Wrap(errors.New("shutting down"))
+
+// ErrNoEndpoints returned if balancer have no discovered endpoints
+var ErrNoEndpoints = xerrors.Wrap(errors.New("no endpoints"))
+
+// ErrAllEndpointsBanned returned if balancer have discovered endpoints but all of them banned or unavailable.
+// ErrAllEndpointsBanned wraps ErrNoEndpoints
+var ErrAllEndpointsBanned = xerrors.Wrap(fmt.Errorf("%w: all endpoints banned", ErrNoEndpoints))
+
+// ErrEndpointAttemptsExhausted returned if logical operation already tried maximum distinct endpoints
+// from context WithMaxEndpointAttempts
+var ErrEndpointAttemptsExhausted = xerrors.Wrap(errors.New("endpoint attempts exhausted"))
+
+// ErrDialRefused returned if dial guard from balancer config refused dial of connection
+var ErrDialRefused = xerrors.Wrap(errors.New("dial refused by dial guard"))
+
 func IsBadConn(err error, goodConnCodes ...grpcCodes.Code) bool {
 	if !xerrors.IsTransportError(err) {
 		return false
@@ -38,3 +65,13 @@ func IsBadConn(err error, goodConnCodes ...grpcCodes.Code) bool {
 
 	return true
 }
+
+// MustPessimizeEndpoint checks that error on call with context must ban endpoint.
+// Pessimization codes from context overrides codes from driver config
+func MustPessimizeEndpoint(ctx context.Context, err error, goodConnCodes ...grpcCodes.Code) bool {
+	if codes, has := PessimizationCodes(ctx); has {
+		return len(codes) > 0 && xerrors.IsTransportError(err, codes...)
+	}
+
+	return IsBadConn(err, goodConnCodes...)
+}
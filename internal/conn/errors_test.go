@@ -1,6 +1,7 @@
 package conn
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -108,3 +109,26 @@ func TestIsBadConn(t *testing.T) {
 		})
 	}
 }
+
+func TestMustPessimizeEndpoint(t *testing.T) {
+	var (
+		ctx         = context.Background()
+		unavailable = xerrors.Transport(grpcStatus.Error(grpcCodes.Unavailable, ""))
+		exhausted   = xerrors.Transport(grpcStatus.Error(grpcCodes.ResourceExhausted, ""))
+	)
+	t.Run("WithoutOverride", func(t *testing.T) {
+		require.True(t, MustPessimizeEndpoint(ctx, unavailable))
+		require.False(t, MustPessimizeEndpoint(ctx, unavailable, grpcCodes.Unavailable))
+		require.False(t, MustPessimizeEndpoint(ctx, exhausted))
+	})
+	t.Run("WithOverride", func(t *testing.T) {
+		ctx := WithPessimizationCodes(ctx, grpcCodes.Unavailable)
+		require.True(t, MustPessimizeEndpoint(ctx, unavailable, grpcCodes.Unavailable))
+		require.False(t, MustPessimizeEndpoint(ctx, exhausted))
+		require.False(t, MustPessimizeEndpoint(ctx, fmt.Errorf("test")))
+	})
+	t.Run("EmptyOverride", func(t *testing.T) {
+		ctx := WithPessimizationCodes(ctx)
+		require.False(t, MustPessimizeEndpoint(ctx, unavailable))
+	})
+}
@@ -30,6 +30,34 @@ type Pool struct {
 	opts   []grpc.DialOption
 	conns  map[connsKey]*conn
 	done   chan struct{}
+
+	// direct contains connections to endpoints by address out of discovered endpoints
+	// (such as for direct calls to endpoint). Direct connections not banned and closes on TTL expiry
+	direct map[string]*conn
+
+	bansMtx xsync.Mutex
+	bans    map[connsKey]Ban
+}
+
+// Ban describes last ban of connection
+type Ban struct {
+	// At is a time of ban
+	At time.Time
+
+	// Cause is an error which caused ban
+	Cause error
+}
+
+// LastBan returns last ban of connection. LastBan returns false if connection never banned by pool
+func (p *Pool) LastBan(cc Conn) (ban Ban, ok bool) {
+	e := cc.Endpoint()
+
+	p.bansMtx.Lock()
+	defer p.bansMtx.Unlock()
+
+	ban, ok = p.bans[connsKey{e.Address(), e.NodeID()}]
+
+	return ban, ok
 }
 
 func (p *Pool) Get(endpoint endpoint.Endpoint) Conn {
@@ -53,6 +81,7 @@ func (p *Pool) Get(endpoint endpoint.Endpoint) Conn {
 		p.config,
 		withOnClose(p.remove),
 		withOnTransportError(p.Ban),
+		withOnDialRefused(p.Pessimize),
 	)
 
 	p.conns[key] = cc
@@ -60,10 +89,52 @@ func (p *Pool) Get(endpoint endpoint.Endpoint) Conn {
 	return cc
 }
 
+// GetByAddress returns connection from pool with address and any node ID or direct connection
+// with address (such as for direct calls to endpoint)
+func (p *Pool) GetByAddress(address string) Conn {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	for key, cc := range p.conns {
+		if key.address == address {
+			return cc
+		}
+	}
+
+	if cc, has := p.direct[address]; has {
+		return cc
+	}
+
+	cc := newConn(
+		endpoint.New(address),
+		p.config,
+		withOnClose(p.removeDirect),
+	)
+
+	p.direct[address] = cc
+
+	return cc
+}
+
 func (p *Pool) remove(c *conn) {
+	key := connsKey{c.Endpoint().Address(), c.Endpoint().NodeID()}
+
 	p.mtx.Lock()
 	defer p.mtx.Unlock()
-	delete(p.conns, connsKey{c.Endpoint().Address(), c.Endpoint().NodeID()})
+	delete(p.conns, key)
+
+	p.bansMtx.WithLock(func() {
+		delete(p.bans, key)
+	})
+}
+
+func (p *Pool) removeDirect(c *conn) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	if p.direct[c.Endpoint().Address()] == c {
+		delete(p.direct, c.Endpoint().Address())
+	}
 }
 
 func (p *Pool) isClosed() bool {
@@ -102,16 +173,37 @@ func (p *Pool) Ban(ctx context.Context, cc Conn, cause error) {
 		return
 	}
 
+	p.ban(ctx, cc, cause)
+}
+
+// Pessimize bans connection without check of cause (such as for ban by custom pessimization decision)
+func (p *Pool) Pessimize(ctx context.Context, cc Conn, cause error) {
+	if p.isClosed() {
+		return
+	}
+
+	p.ban(ctx, cc, cause)
+}
+
+func (p *Pool) ban(ctx context.Context, cc Conn, cause error) {
 	e := cc.Endpoint().Copy()
 
 	p.mtx.RLock()
 	defer p.mtx.RUnlock()
 
-	cc, ok := p.conns[connsKey{e.Address(), e.NodeID()}]
+	key := connsKey{e.Address(), e.NodeID()}
+	cc, ok := p.conns[key]
 	if !ok {
 		return
 	}
 
+	p.bansMtx.WithLock(func() {
+		if p.bans == nil {
+			p.bans = make(map[connsKey]Ban)
+		}
+		p.bans[key] = Ban{At: time.Now(), Cause: cause}
+	})
+
 	trace.DriverOnConnBan(
 		p.config.Trace(), &ctx,
 		stack.FunctionID("github.com/ydb-platform/ydb-go-sdk/v3/internal/conn.(*Pool).Ban"),
@@ -141,6 +233,40 @@ func (p *Pool) Allow(ctx context.Context, cc Conn) {
 	)(cc.Unban(ctx))
 }
 
+// Recycle closes underlying grpc connection to endpoint but keeps connection in pool,
+// so connection will be lazily re-dialed on next use
+func (p *Pool) Recycle(ctx context.Context, cc Conn) error {
+	e := cc.Endpoint()
+
+	p.mtx.RLock()
+	c, ok := p.conns[connsKey{e.Address(), e.NodeID()}]
+	p.mtx.RUnlock()
+
+	if !ok {
+		return nil
+	}
+
+	return c.park(ctx)
+}
+
+// CloseConn closes connection to endpoint (or direct connection) and removes it from pool
+func (p *Pool) CloseConn(ctx context.Context, cc Conn) error {
+	e := cc.Endpoint()
+
+	p.mtx.RLock()
+	c, ok := p.conns[connsKey{e.Address(), e.NodeID()}]
+	if !ok && e.NodeID() == 0 {
+		c, ok = p.direct[e.Address()]
+	}
+	p.mtx.RUnlock()
+
+	if !ok {
+		return nil
+	}
+
+	return c.Close(ctx)
+}
+
 func (p *Pool) Take(context.Context) error {
 	atomic.AddInt64(&p.usages, 1)
 
@@ -163,10 +289,13 @@ func (p *Pool) Release(ctx context.Context) (finalErr error) {
 
 	var conns []closer.Closer
 	p.mtx.WithRLock(func() {
-		conns = make([]closer.Closer, 0, len(p.conns))
+		conns = make([]closer.Closer, 0, len(p.conns)+len(p.direct))
 		for _, c := range p.conns {
 			conns = append(conns, c)
 		}
+		for _, c := range p.direct {
+			conns = append(conns, c)
+		}
 	})
 
 	var (
@@ -216,10 +345,28 @@ func (p *Pool) connParker(ctx context.Context, ttl, interval time.Duration) {
 					}
 				}
 			}
+			p.closeIdleDirectConns(ctx, ttl)
 		}
 	}
 }
 
+// closeIdleDirectConns closes direct connections which not used more than ttl, so connections
+// to endpoints out of discovered endpoints not accumulates in pool
+func (p *Pool) closeIdleDirectConns(ctx context.Context, ttl time.Duration) {
+	var idle []*conn
+	p.mtx.WithRLock(func() {
+		for _, c := range p.direct {
+			if time.Since(c.LastUsage()) > ttl {
+				idle = append(idle, c)
+			}
+		}
+	})
+
+	for _, c := range idle {
+		_ = c.Close(ctx)
+	}
+}
+
 func (p *Pool) collectConns() []*conn {
 	p.mtx.RLock()
 	defer p.mtx.RUnlock()
@@ -242,6 +389,7 @@ func NewPool(ctx context.Context, config Config) *Pool {
 		config: config,
 		opts:   config.GrpcDialOptions(),
 		conns:  make(map[connsKey]*conn),
+		direct: make(map[string]*conn),
 		done:   make(chan struct{}),
 	}
 
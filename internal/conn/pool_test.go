@@ -0,0 +1,49 @@
+package conn
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/endpoint"
+	"github.com/ydb-platform/ydb-go-sdk/v3/trace"
+)
+
+func TestPoolDirectConns(t *testing.T) {
+	ctx := context.Background()
+	p := NewPool(ctx, configMock{trace: &trace.Driver{}})
+
+	discovered := p.Get(endpoint.New("a:1", endpoint.WithID(1)))
+	require.Equal(t, discovered, p.GetByAddress("a:1"))
+	require.Empty(t, p.direct)
+
+	direct := p.GetByAddress("b:1")
+	require.Equal(t, direct, p.GetByAddress("b:1"))
+	require.Len(t, p.direct, 1)
+	require.Len(t, p.conns, 1)
+
+	t.Run("CloseConn", func(t *testing.T) {
+		require.NoError(t, p.CloseConn(ctx, direct))
+		require.Empty(t, p.direct)
+		require.Equal(t, Destroyed, direct.GetState())
+		require.NotEqual(t, direct, p.GetByAddress("b:1"))
+	})
+
+	t.Run("TTL", func(t *testing.T) {
+		p.closeIdleDirectConns(ctx, time.Hour)
+		require.Len(t, p.direct, 1)
+
+		p.closeIdleDirectConns(ctx, 0)
+		require.Empty(t, p.direct)
+		require.Len(t, p.conns, 1)
+	})
+
+	t.Run("Release", func(t *testing.T) {
+		direct := p.GetByAddress("b:1")
+		require.NoError(t, p.Release(ctx))
+		require.Equal(t, Destroyed, direct.GetState())
+		require.Equal(t, Destroyed, discovered.GetState())
+	})
+}
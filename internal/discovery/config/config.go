@@ -7,6 +7,7 @@ import (
 
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/config"
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/meta"
+	"github.com/ydb-platform/ydb-go-sdk/v3/retry"
 	"github.com/ydb-platform/ydb-go-sdk/v3/trace"
 )
 
@@ -24,8 +25,14 @@ type Config struct {
 	addressMutator func(address string) string
 	clock          clockwork.Clock
 
-	interval time.Duration
-	trace    *trace.Discovery
+	interval      time.Duration
+	initialJitter time.Duration
+	maxEndpoints  int
+	trace         *trace.Discovery
+
+	persistentConnection bool
+	retryOptions         []retry.Option
+	rawResponseHandler   func(response []byte)
 }
 
 func New(opts ...Option) *Config {
@@ -62,6 +69,32 @@ func (c *Config) Interval() time.Duration {
 	return c.interval
 }
 
+// InitialJitter returns maximum random delay before first discovery. Zero value means no delay
+func (c *Config) InitialJitter() time.Duration {
+	return c.initialJitter
+}
+
+// MaxEndpoints returns maximum count of endpoints in discovery response. Zero value means no limit
+func (c *Config) MaxEndpoints() int {
+	return c.maxEndpoints
+}
+
+// PersistentConnection reports whether discovery uses dedicated connection for all discovery cycles
+func (c *Config) PersistentConnection() bool {
+	return c.persistentConnection
+}
+
+// RetryOptions returns options of retries of cluster discovery.
+// Options applies over default options of cluster discovery retries
+func (c *Config) RetryOptions() []retry.Option {
+	return c.retryOptions
+}
+
+// RawResponseHandler returns handler of serialized result of successful cluster discovery or nil
+func (c *Config) RawResponseHandler() func(response []byte) {
+	return c.rawResponseHandler
+}
+
 func (c *Config) Endpoint() string {
 	return c.endpoint
 }
@@ -153,3 +186,53 @@ func WithInterval(interval time.Duration) Option {
 		}
 	}
 }
+
+// WithMaxEndpoints set the maximum count of endpoints in discovery response.
+// Discovery with greater count of endpoints fails. Zero value means no limit
+func WithMaxEndpoints(maxEndpoints int) Option {
+	return func(c *Config) {
+		c.maxEndpoints = maxEndpoints
+	}
+}
+
+// WithInitialJitter set the maximum random delay before first discovery for spread discovery load
+// of many simultaneously started instances. Zero or negative value means no delay
+func WithInitialJitter(jitter time.Duration) Option {
+	return func(c *Config) {
+		if jitter < 0 {
+			jitter = 0
+		}
+		c.initialJitter = jitter
+	}
+}
+
+// WithPersistentConnection makes discovery dial dedicated connection once and reuse it across discovery cycles.
+// Connection redials only after transport error. By default discovery uses connection from connections pool
+// which may be parked or closed between discovery cycles
+func WithPersistentConnection(persistent bool) Option {
+	return func(c *Config) {
+		c.persistentConnection = persistent
+	}
+}
+
+// WithRetryOptions appends options of retries of cluster discovery.
+//
+// By default cluster discovery retries as idempotent operation with default fast and slow backoffs,
+// retry trace and retry budget from driver config. Retries are not limited by count of attempts
+// and last until success, non-retryable error or done of context (such as dial timeout on initial discovery).
+// Options applies after defaults, so retry.WithBudget(budget.Limited(...)) limits attempts of discovery,
+// retry.WithIdempotent(false) overrides idempotency of discovery and so on
+func WithRetryOptions(opts ...retry.Option) Option {
+	return func(c *Config) {
+		c.retryOptions = append(c.retryOptions, opts...)
+	}
+}
+
+// WithRawResponseHandler defines handler of serialized result of every successful cluster discovery
+// (such as for external cache of discovery results). Serialized result is a balancer state snapshot
+// which accepts by balancer option WithInitialState. Panics of handler are recovered and never fail discovery
+func WithRawResponseHandler(handler func(response []byte)) Option {
+	return func(c *Config) {
+		c.rawResponseHandler = handler
+	}
+}
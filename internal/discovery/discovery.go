@@ -5,6 +5,7 @@ import (
 	"io"
 	"net"
 	"strconv"
+	"time"
 
 	"github.com/ydb-platform/ydb-go-genproto/Ydb_Discovery_V1"
 	"github.com/ydb-platform/ydb-go-genproto/protos/Ydb"
@@ -97,13 +98,14 @@ func (c *Client) Discover(ctx context.Context) (endpoints []endpoint.Endpoint, f
 			c.config.Endpoint(), c.config.Database(),
 		)
 		location string
+		latency  time.Duration
 	)
 	defer func() {
 		nodes := make([]trace.EndpointInfo, 0, len(endpoints))
 		for _, e := range endpoints {
 			nodes = append(nodes, e.Copy())
 		}
-		onDone(location, nodes, finalErr)
+		onDone(location, nodes, latency, finalErr)
 	}()
 
 	ctx, err := c.config.Meta().Context(ctx)
@@ -111,7 +113,9 @@ func (c *Client) Discover(ctx context.Context) (endpoints []endpoint.Endpoint, f
 		return nil, xerrors.WithStackTrace(err)
 	}
 
+	start := c.config.Clock().Now()
 	endpoints, location, err = discover(ctx, c.client, c.config)
+	latency = c.config.Clock().Since(start)
 	if err != nil {
 		return nil, xerrors.WithStackTrace(err)
 	}
@@ -138,6 +138,12 @@ func WithID(id uint32) Option {
 	}
 }
 
+func WithAddress(address string) Option {
+	return func(e *endpoint) {
+		e.address = address
+	}
+}
+
 func WithLocation(location string) Option {
 	return func(e *endpoint) {
 		e.location = location
@@ -48,3 +48,14 @@ func WithAllowFeatures(ctx context.Context, features ...string) context.Context
 
 	return metadata.AppendToOutgoingContext(ctx, kv...)
 }
+
+// WithRequestTags returns a copy of parent context with request tags in outgoing metadata.
+// Keys of tags prefixes with HeaderRequestTagPrefix
+func WithRequestTags(ctx context.Context, tags map[string]string) context.Context {
+	kv := make([]string, 0, len(tags)*2) //nolint:gomnd
+	for key, value := range tags {
+		kv = append(kv, HeaderRequestTagPrefix+key, value)
+	}
+
+	return metadata.AppendToOutgoingContext(ctx, kv...)
+}
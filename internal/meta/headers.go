@@ -1,5 +1,7 @@
 package meta
 
+import "strings"
+
 const (
 	// outgoing headers
 	HeaderDatabase           = "x-ydb-database"
@@ -11,6 +13,10 @@ const (
 	HeaderClientCapabilities = "x-ydb-client-capabilities"
 	HeaderClientPid          = "x-ydb-client-pid"
 
+	// HeaderRequestTagPrefix is a namespace of user-defined request tags. Tag with key "tenant"
+	// sends as "x-ydb-request-tag-tenant" header, so tags never collide with reserved headers
+	HeaderRequestTagPrefix = "x-ydb-request-tag-"
+
 	// outgoing hints
 	HintSessionBalancer = "session-balancer"
 
@@ -21,3 +27,24 @@ const (
 	// incoming hints
 	HintSessionClose = "session-close"
 )
+
+// reservedHeaders contains outgoing headers of SDK which static metadata cannot override
+var reservedHeaders = map[string]struct{}{
+	HeaderDatabase:           {},
+	HeaderTicket:             {},
+	HeaderVersion:            {},
+	HeaderRequestType:        {},
+	HeaderApplicationName:    {},
+	HeaderClientCapabilities: {},
+	HeaderClientPid:          {},
+}
+
+// IsReservedHeader reports whether header is an outgoing header of SDK (including namespace of request tags)
+func IsReservedHeader(key string) bool {
+	key = strings.ToLower(key)
+	if _, has := reservedHeaders[key]; has {
+		return true
+	}
+
+	return strings.HasPrefix(key, HeaderRequestTagPrefix)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 
 	"google.golang.org/grpc/metadata"
 
@@ -46,6 +47,28 @@ func WithApplicationNameOption(applicationName string) Option {
 	}
 }
 
+// WithProductTokenOption prepends product token (such as "myorm/1.2") to sdk version in version header
+func WithProductTokenOption(token string) Option {
+	return func(m *Meta) {
+		m.productTokens = append(m.productTokens, token)
+	}
+}
+
+// WithStaticMetadataOption appends static headers to metadata of all requests.
+// Reserved headers of SDK are ignored, headers from outgoing metadata of context takes precedence over static headers
+func WithStaticMetadataOption(md map[string]string) Option {
+	return func(m *Meta) {
+		if m.staticMetadata == nil {
+			m.staticMetadata = make(map[string]string, len(md))
+		}
+		for key, value := range md {
+			if !IsReservedHeader(key) {
+				m.staticMetadata[strings.ToLower(key)] = value
+			}
+		}
+	}
+}
+
 func WithRequestTypeOption(requestType string) Option {
 	return func(m *Meta) {
 		m.requestsType = requestType
@@ -78,7 +101,23 @@ type Meta struct {
 	database        string
 	requestsType    string
 	applicationName string
+	productTokens   []string
 	capabilities    []string
+	staticMetadata  map[string]string
+}
+
+// ApplicationName returns name of application from application name header
+func (m *Meta) ApplicationName() string {
+	return m.applicationName
+}
+
+// UserAgent returns sdk version prefixed with product tokens
+func (m *Meta) UserAgent() string {
+	if len(m.productTokens) == 0 {
+		return version.FullVersion
+	}
+
+	return strings.Join(m.productTokens, " ") + " " + version.FullVersion
 }
 
 func (m *Meta) meta(ctx context.Context) (_ metadata.MD, err error) {
@@ -87,6 +126,12 @@ func (m *Meta) meta(ctx context.Context) (_ metadata.MD, err error) {
 		md = metadata.MD{}
 	}
 
+	for key, value := range m.staticMetadata {
+		if len(md.Get(key)) == 0 {
+			md.Set(key, value)
+		}
+	}
+
 	md.Set(HeaderClientPid, m.pid)
 
 	if len(md.Get(HeaderDatabase)) == 0 {
@@ -94,7 +139,7 @@ func (m *Meta) meta(ctx context.Context) (_ metadata.MD, err error) {
 	}
 
 	if len(md.Get(HeaderVersion)) == 0 {
-		md.Set(HeaderVersion, version.FullVersion)
+		md.Set(HeaderVersion, m.UserAgent())
 	}
 
 	if m.requestsType != "" {
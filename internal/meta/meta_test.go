@@ -0,0 +1,93 @@
+package meta
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/version"
+)
+
+func TestProductToken(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		opts  []Option
+		value string
+	}{
+		{
+			name:  "Default",
+			value: version.FullVersion,
+		},
+		{
+			name:  "SingleToken",
+			opts:  []Option{WithProductTokenOption("myorm/1.2")},
+			value: "myorm/1.2 " + version.FullVersion,
+		},
+		{
+			name: "MultipleTokens",
+			opts: []Option{
+				WithProductTokenOption("myapp/0.1"),
+				WithProductTokenOption("myorm/1.2"),
+			},
+			value: "myapp/0.1 myorm/1.2 " + version.FullVersion,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, err := New("/local", nil, nil, tt.opts...).Context(context.Background())
+			require.NoError(t, err)
+			md, has := metadata.FromOutgoingContext(ctx)
+			require.True(t, has)
+			require.Equal(t, []string{tt.value}, md.Get(HeaderVersion))
+		})
+	}
+}
+
+func TestStaticMetadata(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		ctx      context.Context //nolint:containedctx
+		opts     []Option
+		expected map[string][]string
+	}{
+		{
+			name: "Static",
+			ctx:  context.Background(),
+			opts: []Option{WithStaticMetadataOption(map[string]string{"X-Routing-Hint": "a"})},
+			expected: map[string][]string{
+				"x-routing-hint": {"a"},
+			},
+		},
+		{
+			name: "ReservedIgnored",
+			ctx:  context.Background(),
+			opts: []Option{WithStaticMetadataOption(map[string]string{
+				HeaderDatabase:                 "/other",
+				HeaderRequestTagPrefix + "key": "value",
+			})},
+			expected: map[string][]string{
+				HeaderDatabase:                 {"/local"},
+				HeaderRequestTagPrefix + "key": nil,
+			},
+		},
+		{
+			name: "ContextTakesPrecedence",
+			ctx:  metadata.AppendToOutgoingContext(context.Background(), "x-routing-hint", "b"),
+			opts: []Option{WithStaticMetadataOption(map[string]string{"x-routing-hint": "a"})},
+			expected: map[string][]string{
+				"x-routing-hint": {"b"},
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, err := New("/local", nil, nil, tt.opts...).Context(tt.ctx)
+			require.NoError(t, err)
+			md, has := metadata.FromOutgoingContext(ctx)
+			require.True(t, has)
+			for key, value := range tt.expected {
+				require.Equal(t, value, md.Get(key), key)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package mock
 
 import (
 	"context"
+	"sync/atomic"
 	"time"
 
 	"google.golang.org/grpc"
@@ -11,12 +12,16 @@ import (
 )
 
 type Conn struct {
-	PingErr       error
-	AddrField     string
-	LocationField string
-	NodeIDField   uint32
-	State         conn.State
-	LocalDCField  bool
+	PingErr         error
+	AddrField       string
+	LocationField   string
+	NodeIDField     uint32
+	State           conn.State
+	LocalDCField    bool
+	LoadFactorField float32
+
+	inFlight atomic.Int64
+	touched  atomic.Int64
 }
 
 func (c *Conn) Invoke(
@@ -38,10 +43,11 @@ func (c *Conn) NewStream(ctx context.Context,
 
 func (c *Conn) Endpoint() endpoint.Endpoint {
 	return &Endpoint{
-		AddrField:     c.AddrField,
-		LocalDCField:  c.LocalDCField,
-		LocationField: c.LocationField,
-		NodeIDField:   c.NodeIDField,
+		AddrField:       c.AddrField,
+		LocalDCField:    c.LocalDCField,
+		LocationField:   c.LocationField,
+		NodeIDField:     c.NodeIDField,
+		LoadFactorField: c.LoadFactorField,
 	}
 }
 
@@ -49,6 +55,15 @@ func (c *Conn) LastUsage() time.Time {
 	panic("not implemented in mock")
 }
 
+func (c *Conn) Touch() {
+	c.touched.Add(1)
+}
+
+// Touched returns count of touches of connection
+func (c *Conn) Touched() int {
+	return int(c.touched.Load())
+}
+
 func (c *Conn) Park(ctx context.Context) (err error) {
 	panic("not implemented in mock")
 }
@@ -71,6 +86,18 @@ func (c *Conn) SetState(ctx context.Context, state conn.State) conn.State {
 	return c.State
 }
 
+func (c *Conn) InFlight() int {
+	return int(c.inFlight.Load())
+}
+
+func (c *Conn) TrackInFlight() (done func()) {
+	c.inFlight.Add(1)
+
+	return func() {
+		c.inFlight.Add(-1)
+	}
+}
+
 func (c *Conn) Unban(ctx context.Context) conn.State {
 	c.SetState(ctx, conn.Online)
 
@@ -78,10 +105,11 @@ func (c *Conn) Unban(ctx context.Context) conn.State {
 }
 
 type Endpoint struct {
-	AddrField     string
-	LocationField string
-	NodeIDField   uint32
-	LocalDCField  bool
+	AddrField       string
+	LocationField   string
+	NodeIDField     uint32
+	LocalDCField    bool
+	LoadFactorField float32
 }
 
 func (e *Endpoint) Choose(bool) {
@@ -112,7 +140,7 @@ func (e *Endpoint) LastUpdated() time.Time {
 }
 
 func (e *Endpoint) LoadFactor() float32 {
-	panic("not implemented in mock")
+	return e.LoadFactorField
 }
 
 func (e *Endpoint) String() string {
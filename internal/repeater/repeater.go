@@ -34,6 +34,16 @@ type repeater struct {
 
 	force chan struct{}
 	clock clockwork.Clock
+
+	// intervalStrategy defines effective interval after each task execution
+	intervalStrategy IntervalStrategy
+}
+
+// IntervalStrategy defines adaptive interval between task executions
+type IntervalStrategy interface {
+	// Interval returns effective interval after execution of task on event with result err.
+	// Non-positive interval keeps current interval
+	Interval(event Event, err error) time.Duration
 }
 
 type option func(r *repeater)
@@ -56,6 +66,12 @@ func WithInterval(interval time.Duration) option {
 	}
 }
 
+func WithIntervalStrategy(strategy IntervalStrategy) option {
+	return func(r *repeater) {
+		r.intervalStrategy = strategy
+	}
+}
+
 func WithClock(clock clockwork.Clock) option {
 	return func(r *repeater) {
 		r.clock = clock
@@ -148,7 +164,7 @@ func (r *repeater) wakeUp(ctx context.Context, e Event) (err error) {
 
 	onDone := trace.DriverOnRepeaterWakeUp(r.trace, &ctx,
 		stack.FunctionID("github.com/ydb-platform/ydb-go-sdk/v3/internal/repeater.(*repeater).wakeUp"),
-		r.name, e,
+		r.name, e, r.interval,
 	)
 	defer func() {
 		onDone(err)
@@ -168,7 +184,9 @@ func (r *repeater) wakeUp(ctx context.Context, e Event) (err error) {
 
 func (r *repeater) worker(ctx context.Context, tick clockwork.Ticker) {
 	defer close(r.stopped)
-	defer tick.Stop()
+	defer func() {
+		tick.Stop()
+	}()
 
 	// force returns backoff with delays [500ms...32s]
 	force := backoff.New(
@@ -203,11 +221,20 @@ func (r *repeater) worker(ctx context.Context, tick clockwork.Ticker) {
 		if event == EventCancel {
 			return
 		}
-		if err := r.wakeUp(ctx, event); err != nil {
+		err := r.wakeUp(ctx, event)
+		if err != nil {
 			forceIndex++
 		} else {
 			forceIndex = 0
 		}
+		if r.intervalStrategy == nil {
+			return
+		}
+		if interval := r.intervalStrategy.Interval(event, err); interval > 0 && interval != r.interval {
+			r.interval = interval
+			tick.Stop()
+			tick = r.clock.NewTicker(interval)
+		}
 	}
 
 	for {
@@ -8,6 +8,8 @@ import (
 	"time"
 
 	"github.com/jonboulle/clockwork"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/trace"
 )
 
 func TestRepeaterNoWakeUpsAfterStop(t *testing.T) {
@@ -104,3 +106,54 @@ func TestRepeaterForceLogBackoff(t *testing.T) {
 		<-repeaterDone
 	}
 }
+
+type intervalStrategyFunc func(event Event, err error) time.Duration
+
+func (f intervalStrategyFunc) Interval(event Event, err error) time.Duration {
+	return f(event, err)
+}
+
+func TestRepeaterIntervalStrategy(t *testing.T) {
+	var (
+		fakeClock    = clockwork.NewFakeClock()
+		repeaterDone = make(chan struct{})
+		intervals    []time.Duration
+		events       []Event
+	)
+	r := New(context.Background(), 10*time.Minute, func(ctx context.Context) (err error) {
+		defer func() {
+			repeaterDone <- struct{}{}
+		}()
+
+		return nil
+	},
+		WithClock(fakeClock),
+		WithTrace(&trace.Driver{
+			OnRepeaterWakeUp: func(info trace.DriverRepeaterWakeUpStartInfo) func(trace.DriverRepeaterWakeUpDoneInfo) {
+				intervals = append(intervals, info.Interval)
+				events = append(events, info.Event)
+
+				return nil
+			},
+		}),
+		WithIntervalStrategy(intervalStrategyFunc(func(event Event, err error) time.Duration {
+			return time.Minute
+		})),
+	)
+	defer r.Stop()
+
+	r.Force()
+	<-repeaterDone
+
+	// ensure ticker with new interval attached
+	fakeClock.BlockUntil(1)
+	fakeClock.Advance(time.Minute)
+	<-repeaterDone
+
+	if exp := []time.Duration{10 * time.Minute, time.Minute}; fmt.Sprint(intervals) != fmt.Sprint(exp) {
+		t.Fatalf("unexpected intervals: %v, exp: %v", intervals, exp)
+	}
+	if exp := []Event{EventForce, EventTick}; fmt.Sprint(events) != fmt.Sprint(exp) {
+		t.Fatalf("unexpected events: %v, exp: %v", events, exp)
+	}
+}
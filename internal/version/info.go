@@ -0,0 +1,38 @@
+package version
+
+import "strconv"
+
+// Info is a structured version of sdk
+type Info struct {
+	// Prefix is a name of sdk package which prefixes version in FullVersion
+	Prefix string
+	Major  int
+	Minor  int
+	Patch  int
+}
+
+func (i Info) String() string {
+	return i.Prefix + "/" + strconv.Itoa(i.Major) + "." + strconv.Itoa(i.Minor) + "." + strconv.Itoa(i.Patch)
+}
+
+// Parsed returns numeric components of sdk version
+func Parsed() (major, minor, patch int) {
+	info := Get()
+
+	return info.Major, info.Minor, info.Patch
+}
+
+// Get returns structured version of sdk
+func Get() Info {
+	v, err := parse(Version)
+	if err != nil {
+		panic(err)
+	}
+
+	return Info{
+		Prefix: Package,
+		Major:  int(v.Major),
+		Minor:  int(v.Minor),
+		Patch:  int(v.Patch),
+	}
+}
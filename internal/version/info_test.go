@@ -0,0 +1,18 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInfo(t *testing.T) {
+	info := Get()
+	require.Equal(t, Package, info.Prefix)
+	require.Equal(t, FullVersion, info.String())
+
+	major, minor, patch := Parsed()
+	require.Equal(t, info.Major, major)
+	require.Equal(t, info.Minor, minor)
+	require.Equal(t, info.Patch, patch)
+}
@@ -4,7 +4,9 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"time"
 
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	grpcCodes "google.golang.org/grpc/codes"
 	grpcStatus "google.golang.org/grpc/status"
 
@@ -197,3 +199,22 @@ func TransportError(err error) Error {
 
 	return nil
 }
+
+// RetryDelay returns server retry hint (google.rpc.RetryInfo detail of gRPC status) from transport error.
+// RetryDelay returns false if error has no retry hint
+func RetryDelay(err error) (delay time.Duration, ok bool) {
+	var status *grpcStatus.Status
+	if t := (*transportError)(nil); errors.As(err, &t) {
+		status = t.status
+	} else if s, has := grpcStatus.FromError(err); has {
+		status = s
+	}
+
+	for _, detail := range status.Details() {
+		if info, has := detail.(*errdetails.RetryInfo); has && info.GetRetryDelay() != nil {
+			return info.GetRetryDelay().AsDuration(), true
+		}
+	}
+
+	return 0, false
+}
@@ -153,6 +153,16 @@ func internalDriver(l Logger, d trace.Detailer) trace.Driver {
 				)
 			}
 		},
+		OnConnGrpcStateChange: func(info trace.DriverConnGrpcStateChangeInfo) {
+			if d.Details()&trace.DriverConnEvents == 0 {
+				return
+			}
+			ctx := with(*info.Context, DEBUG, "ydb", "driver", "conn", "grpc", "state", "change")
+			l.Log(ctx, "grpc connection state changed",
+				Stringer("endpoint", info.Endpoint),
+				Stringer("state", info.State),
+			)
+		},
 		OnConnClose: func(info trace.DriverConnCloseStartInfo) func(trace.DriverConnCloseDoneInfo) {
 			if d.Details()&trace.DriverConnEvents == 0 {
 				return nil
@@ -368,6 +378,7 @@ func internalDriver(l Logger, d trace.Detailer) trace.Driver {
 			l.Log(ctx, "start",
 				String("name", name),
 				String("event", event),
+				Duration("interval", info.Interval),
 			)
 			start := time.Now()
 
@@ -425,6 +436,36 @@ func internalDriver(l Logger, d trace.Detailer) trace.Driver {
 				}
 			}
 		},
+		OnBalancerCall: func(info trace.DriverBalancerCallStartInfo) func(trace.DriverBalancerCallDoneInfo) {
+			if d.Details()&trace.DriverBalancerEvents == 0 {
+				return nil
+			}
+			ctx := with(*info.Context, TRACE, "ydb", "driver", "balancer", "call")
+			l.Log(ctx, "start",
+				String("method", info.Method),
+				Int("attempt", info.Attempt),
+			)
+			start := time.Now()
+
+			return func(done trace.DriverBalancerCallDoneInfo) {
+				if done.Error == nil {
+					l.Log(ctx, "done",
+						latencyField(start),
+						String("method", info.Method),
+						Stringer("endpoint", done.Endpoint),
+					)
+				} else {
+					l.Log(WithLevel(ctx, WARN), "failed",
+						Error(done.Error),
+						latencyField(start),
+						String("method", info.Method),
+						Int("attempt", info.Attempt),
+						Stringer("endpoint", done.Endpoint),
+						versionField(),
+					)
+				}
+			}
+		},
 		OnBalancerChooseEndpoint: func(
 			info trace.DriverBalancerChooseEndpointStartInfo,
 		) func(
@@ -442,6 +483,9 @@ func internalDriver(l Logger, d trace.Detailer) trace.Driver {
 					l.Log(ctx, "done",
 						latencyField(start),
 						Stringer("endpoint", info.Endpoint),
+						String("role", info.Role),
+						Int("localityLevel", info.LocalityLevel),
+						Int64("preferredNodeID", int64(info.PreferredNodeID)),
 					)
 				} else {
 					l.Log(WithLevel(ctx, ERROR), "failed",
@@ -452,6 +496,34 @@ func internalDriver(l Logger, d trace.Detailer) trace.Driver {
 				}
 			}
 		},
+		OnBalancerClusterDiscoveryAttempt: func(
+			info trace.DriverBalancerClusterDiscoveryAttemptStartInfo,
+		) func(
+			trace.DriverBalancerClusterDiscoveryAttemptDoneInfo,
+		) {
+			if d.Details()&trace.DriverBalancerEvents == 0 {
+				return nil
+			}
+			ctx := with(*info.Context, TRACE, "ydb", "driver", "balancer", "discovery")
+			l.Log(ctx, "start",
+				String("address", info.Address),
+			)
+			start := time.Now()
+
+			return func(info trace.DriverBalancerClusterDiscoveryAttemptDoneInfo) {
+				if info.Error == nil {
+					l.Log(ctx, "done",
+						latencyField(start),
+					)
+				} else {
+					l.Log(WithLevel(ctx, WARN), "failed",
+						Error(info.Error),
+						latencyField(start),
+						versionField(),
+					)
+				}
+			}
+		},
 		OnBalancerUpdate: func(
 			info trace.DriverBalancerUpdateStartInfo,
 		) func(
@@ -473,9 +545,139 @@ func internalDriver(l Logger, d trace.Detailer) trace.Driver {
 					Stringer("added", endpoints(info.Added)),
 					Stringer("dropped", endpoints(info.Dropped)),
 					String("detectedLocalDC", info.LocalDC),
+					Int("preferred", info.Preferred),
+					Int("fallback", info.Fallback),
+					String("addressFamily", info.AddressFamily),
+				)
+			}
+		},
+		OnBalancerFallback: func(info trace.DriverBalancerFallbackInfo) {
+			if d.Details()&trace.DriverBalancerEvents == 0 {
+				return
+			}
+			ctx := with(*info.Context, WARN, "ydb", "driver", "balancer", "fallback")
+			l.Log(ctx, "preferred connections exhausted, using fallback connections",
+				String("localDC", info.LocalDC),
+				Int("preferred", info.PreferredConn),
+				Int("fallback", info.FallbackConn),
+			)
+		},
+		OnBalancerCircuitBreakerStateChange: func(info trace.DriverBalancerCircuitBreakerStateChangeInfo) {
+			if d.Details()&trace.DriverBalancerEvents == 0 {
+				return
+			}
+			ctx := with(*info.Context, WARN, "ydb", "driver", "balancer", "circuit", "breaker")
+			l.Log(ctx, "circuit breaker state changed",
+				Stringer("endpoint", info.Endpoint),
+				String("from", info.From),
+				String("to", info.To),
+				Int("failures", info.Failures),
+			)
+		},
+		OnBalancerBanRecovery: func(info trace.DriverBalancerBanRecoveryInfo) {
+			if d.Details()&trace.DriverBalancerEvents == 0 {
+				return
+			}
+			ctx := with(*info.Context, INFO, "ydb", "driver", "balancer", "ban", "recovery")
+			l.Log(ctx, "banned endpoint recovered by probe",
+				Stringer("endpoint", info.Endpoint),
+				Int("attempts", info.Attempts),
+			)
+		},
+		OnBalancerLocalDCNotFound: func(info trace.DriverBalancerLocalDCNotFoundInfo) {
+			if d.Details()&trace.DriverBalancerEvents == 0 {
+				return
+			}
+			ctx := with(*info.Context, WARN, "ydb", "driver", "balancer", "local", "dc")
+			l.Log(ctx, "no one discovered endpoint in local DC",
+				String("localDC", info.LocalDC),
+				Strings("locations", info.Locations),
+			)
+		},
+		OnBalancerPartialDiscovery: func(info trace.DriverBalancerPartialDiscoveryInfo) {
+			if d.Details()&trace.DriverBalancerEvents == 0 {
+				return
+			}
+			ctx := with(*info.Context, WARN, "ydb", "driver", "balancer", "partial", "discovery")
+			msg := "partial discovery result skipped"
+			if info.Applied {
+				msg = "partial discovery result applied"
+			}
+			l.Log(ctx, msg,
+				Int("previous", info.Previous),
+				Int("discovered", info.Discovered),
+				Int("attempts", info.Attempts),
+			)
+		},
+		OnBalancerDuplicateEndpoints: func(info trace.DriverBalancerDuplicateEndpointsInfo) {
+			if d.Details()&trace.DriverBalancerEvents == 0 {
+				return
+			}
+			ctx := with(*info.Context, WARN, "ydb", "driver", "balancer", "duplicate", "endpoints")
+			l.Log(ctx, "duplicate endpoints dropped from discovery result",
+				Stringer("duplicates", endpoints(info.Duplicates)),
+			)
+		},
+		OnBalancerFailover: func(info trace.DriverBalancerFailoverInfo) {
+			if d.Details()&trace.DriverBalancerEvents == 0 {
+				return
+			}
+			ctx := with(*info.Context, WARN, "ydb", "driver", "balancer", "failover")
+			if info.Failback {
+				l.Log(WithLevel(ctx, INFO), "failback to primary database",
+					String("from", info.From),
+					String("to", info.To),
+				)
+			} else {
+				l.Log(ctx, "failover to secondary database",
+					String("from", info.From),
+					String("to", info.To),
+					Error(info.Error),
 				)
 			}
 		},
+		OnBalancerRecycleEndpoint: func(
+			info trace.DriverBalancerRecycleEndpointStartInfo,
+		) func(
+			trace.DriverBalancerRecycleEndpointDoneInfo,
+		) {
+			if d.Details()&trace.DriverBalancerEvents == 0 {
+				return nil
+			}
+			ctx := with(*info.Context, TRACE, "ydb", "driver", "balancer", "recycle", "endpoint")
+			endpoint := info.Endpoint
+			l.Log(ctx, "start",
+				Stringer("endpoint", endpoint),
+			)
+			start := time.Now()
+
+			return func(info trace.DriverBalancerRecycleEndpointDoneInfo) {
+				if info.Error == nil {
+					l.Log(WithLevel(ctx, INFO), "done",
+						Stringer("endpoint", endpoint),
+						latencyField(start),
+					)
+				} else {
+					l.Log(WithLevel(ctx, WARN), "failed",
+						Error(info.Error),
+						Stringer("endpoint", endpoint),
+						latencyField(start),
+						versionField(),
+					)
+				}
+			}
+		},
+		OnBalancerFallbackRecover: func(info trace.DriverBalancerFallbackRecoverInfo) {
+			if d.Details()&trace.DriverBalancerEvents == 0 {
+				return
+			}
+			ctx := with(*info.Context, INFO, "ydb", "driver", "balancer", "fallback", "recover")
+			l.Log(ctx, "preferred connections recovered",
+				String("localDC", info.LocalDC),
+				Int("preferred", info.PreferredConn),
+				Int("fallback", info.FallbackConn),
+			)
+		},
 		OnGetCredentials: func(info trace.DriverGetCredentialsStartInfo) func(trace.DriverGetCredentialsDoneInfo) {
 			if d.Details()&trace.DriverCredentialsEvents == 0 {
 				return nil
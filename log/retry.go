@@ -54,5 +54,19 @@ func internalRetry(l Logger, d trace.Detailer) (t trace.Retry) {
 		}
 	}
 
+	t.OnRetryAttempt = func(info trace.RetryAttemptInfo) {
+		if d.Details()&trace.RetryEvents == 0 {
+			return
+		}
+		ctx := with(*info.Context, DEBUG, "ydb", "retry", "attempt")
+		l.Log(ctx, "failed",
+			Error(info.Error),
+			String("label", info.Label),
+			Int("attempt", info.Attempt),
+			Duration("delay", info.Delay),
+			Bool("idempotent", info.Idempotent),
+		)
+	}
+
 	return t
 }
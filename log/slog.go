@@ -0,0 +1,157 @@
+package log
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/trace"
+)
+
+// SlogCategory is a category of driver events for Slog adapter
+type SlogCategory string
+
+const (
+	// SlogBalancer is a category of balancer events (such as choose of endpoint, fallback, circuit breakers)
+	SlogBalancer = SlogCategory("balancer")
+
+	// SlogDiscovery is a category of cluster discovery events (discovery attempts and balancer updates)
+	SlogDiscovery = SlogCategory("discovery")
+
+	// SlogConn is a category of connection events (such as dial, invoke, ban and allow)
+	SlogConn = SlogCategory("conn")
+)
+
+// slogKeys maps keys of fields to consistent attribute keys of slog records
+var slogKeys = map[string]string{
+	"localDC":         "local_dc",
+	"detectedLocalDC": "local_dc",
+}
+
+type SlogOption interface {
+	applySlogOption(l *slogLogger)
+}
+
+type slogMinLevelOption struct {
+	category SlogCategory
+	level    Level
+}
+
+func (o slogMinLevelOption) applySlogOption(l *slogLogger) {
+	l.minLevels[o.category] = o.level
+}
+
+// WithSlogMinLevel defines minimal level of events in category.
+// By default, all events of category are passed to slog.Logger, which filters records by own level
+func WithSlogMinLevel(category SlogCategory, level Level) SlogOption {
+	return slogMinLevelOption{
+		category: category,
+		level:    level,
+	}
+}
+
+// Slog makes trace.Driver with logging balancer, discovery and connection events into slog.Logger
+// Events maps to structured records with consistent attribute keys (endpoint, local_dc, error, latency)
+func Slog(logger *slog.Logger, opts ...SlogOption) trace.Driver {
+	l := &slogLogger{
+		logger:    logger,
+		minLevels: make(map[SlogCategory]Level),
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt.applySlogOption(l)
+		}
+	}
+
+	return internalDriver(l, trace.DriverBalancerEvents|trace.DriverConnEvents)
+}
+
+var _ Logger = (*slogLogger)(nil)
+
+type slogLogger struct {
+	logger    *slog.Logger
+	minLevels map[SlogCategory]Level
+}
+
+func (l *slogLogger) Log(ctx context.Context, msg string, fields ...Field) {
+	names := NamesFromContext(ctx)
+	lvl := LevelFromContext(ctx)
+	if minLevel, has := l.minLevels[slogCategory(names)]; has && lvl < minLevel {
+		return
+	}
+
+	level := slogLevel(lvl)
+	if !l.logger.Enabled(ctx, level) {
+		return
+	}
+
+	attrs := make([]slog.Attr, 0, len(fields)+1)
+	attrs = append(attrs, slog.String("namespace", strings.Join(names, ".")))
+	for i := range fields {
+		attrs = append(attrs, slogAttr(fields[i]))
+	}
+
+	l.logger.LogAttrs(ctx, level, msg, attrs...)
+}
+
+func slogCategory(names []string) SlogCategory {
+	if len(names) < 3 || names[0] != "ydb" || names[1] != "driver" {
+		return ""
+	}
+
+	switch names[2] {
+	case "conn":
+		return SlogConn
+	case "balancer":
+		if len(names) > 3 && (names[3] == "discovery" || names[3] == "update") {
+			return SlogDiscovery
+		}
+
+		return SlogBalancer
+	default:
+		return SlogCategory(names[2])
+	}
+}
+
+func slogLevel(lvl Level) slog.Level {
+	switch lvl {
+	case TRACE:
+		return slog.LevelDebug - 4
+	case DEBUG:
+		return slog.LevelDebug
+	case INFO:
+		return slog.LevelInfo
+	case WARN:
+		return slog.LevelWarn
+	case ERROR:
+		return slog.LevelError
+	default:
+		return slog.LevelError + 4
+	}
+}
+
+func slogAttr(f Field) slog.Attr {
+	key := f.Key()
+	if k, has := slogKeys[key]; has {
+		key = k
+	}
+
+	switch f.Type() {
+	case IntType:
+		return slog.Int(key, f.IntValue())
+	case Int64Type:
+		return slog.Int64(key, f.Int64Value())
+	case StringType:
+		return slog.String(key, f.StringValue())
+	case BoolType:
+		return slog.Bool(key, f.BoolValue())
+	case DurationType:
+		return slog.Duration(key, f.DurationValue())
+	case StringsType:
+		return slog.Any(key, f.StringsValue())
+	case ErrorType:
+		return slog.Any(key, f.ErrorValue())
+	default:
+		return slog.String(key, f.String())
+	}
+}
@@ -0,0 +1,91 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/endpoint"
+	"github.com/ydb-platform/ydb-go-sdk/v3/trace"
+)
+
+func slogRecords(t *testing.T, buf *bytes.Buffer) (records []map[string]interface{}) {
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var record map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &record))
+		records = append(records, record)
+	}
+
+	return records
+}
+
+func TestSlog(t *testing.T) {
+	ctx := context.Background()
+	newDriver := func(buf *bytes.Buffer, opts ...SlogOption) trace.Driver {
+		return Slog(slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug - 4})), opts...)
+	}
+
+	t.Run("Attributes", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		d := newDriver(buf)
+		d.OnBalancerFallback(trace.DriverBalancerFallbackInfo{Context: &ctx, LocalDC: "a"})
+		d.OnConnDial(trace.DriverConnDialStartInfo{
+			Context:  &ctx,
+			Endpoint: endpoint.New("a:123"),
+		})(trace.DriverConnDialDoneInfo{Error: errors.New("test")})
+
+		records := slogRecords(t, buf)
+		require.Len(t, records, 3)
+		require.Equal(t, "WARN", records[0]["level"])
+		require.Equal(t, "ydb.driver.balancer.fallback", records[0]["namespace"])
+		require.Equal(t, "a", records[0]["local_dc"])
+		require.Equal(t, "ydb.driver.conn.dial", records[2]["namespace"])
+		require.Contains(t, records[2]["endpoint"], "a:123")
+		require.Equal(t, "test", records[2]["error"])
+		require.Contains(t, records[2], "latency")
+	})
+	t.Run("MinLevel", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		d := newDriver(buf,
+			WithSlogMinLevel(SlogBalancer, ERROR),
+			WithSlogMinLevel(SlogDiscovery, INFO),
+		)
+		d.OnBalancerFallback(trace.DriverBalancerFallbackInfo{Context: &ctx, LocalDC: "a"})
+		d.OnBalancerUpdate(trace.DriverBalancerUpdateStartInfo{Context: &ctx})(trace.DriverBalancerUpdateDoneInfo{
+			LocalDC: "a",
+		})
+		d.OnConnDial(trace.DriverConnDialStartInfo{
+			Context:  &ctx,
+			Endpoint: endpoint.New("a:123"),
+		})(trace.DriverConnDialDoneInfo{})
+
+		records := slogRecords(t, buf)
+		require.Len(t, records, 2)
+		for _, record := range records {
+			require.Equal(t, "ydb.driver.conn.dial", record["namespace"])
+		}
+	})
+	t.Run("HandlerLevel", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		d := Slog(slog.New(slog.NewJSONHandler(buf, nil)))
+		d.OnConnDial(trace.DriverConnDialStartInfo{
+			Context:  &ctx,
+			Endpoint: endpoint.New("a:123"),
+		})(trace.DriverConnDialDoneInfo{Error: errors.New("test")})
+
+		records := slogRecords(t, buf)
+		require.Len(t, records, 1)
+		require.Equal(t, "WARN", records[0]["level"])
+		require.Equal(t, "test", records[0]["error"])
+		require.Contains(t, records[0], "latency")
+	})
+}
@@ -4,6 +4,34 @@ import (
 	"github.com/ydb-platform/ydb-go-sdk/v3/trace"
 )
 
+// Discovery makes trace.Discovery which publishes discovery metrics into registry from config.
+// Discovery metrics contains latency of ListEndpoints calls (by status).
+// Discovery trace may be merged into driver config with ydb.WithTraceDiscovery
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func Discovery(config Config) trace.Discovery {
+	if config == nil {
+		return trace.Discovery{}
+	}
+
+	return discovery(config.WithSystem("ydb"))
+}
+
 func discovery(config Config) (t trace.Discovery) {
+	config = config.WithSystem("discovery")
+	latency := config.TimerVec("latency", "status")
+
+	t.OnDiscover = func(info trace.DiscoveryDiscoverStartInfo) func(trace.DiscoveryDiscoverDoneInfo) {
+		if config.Details()&trace.DiscoveryEvents == 0 {
+			return nil
+		}
+
+		return func(info trace.DiscoveryDiscoverDoneInfo) {
+			latency.With(map[string]string{
+				"status": errorBrief(info.Error),
+			}).Record(info.Latency)
+		}
+	}
+
 	return t
 }
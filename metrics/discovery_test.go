@@ -0,0 +1,74 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/stack"
+	"github.com/ydb-platform/ydb-go-sdk/v3/trace"
+)
+
+type timersRegistryMock struct {
+	*registryMock
+
+	mu     *sync.Mutex
+	timers map[string][]time.Duration
+}
+
+type recordingTimerVecMock struct {
+	name     string
+	registry *timersRegistryMock
+}
+
+func (v recordingTimerVecMock) With(labels map[string]string) Timer {
+	return recordingTimerMock{name: v.name + "{status=" + labels["status"] + "}", registry: v.registry}
+}
+
+type recordingTimerMock struct {
+	name     string
+	registry *timersRegistryMock
+}
+
+func (t recordingTimerMock) Record(value time.Duration) {
+	t.registry.mu.Lock()
+	defer t.registry.mu.Unlock()
+	t.registry.timers[t.name] = append(t.registry.timers[t.name], value)
+}
+
+func (r *timersRegistryMock) TimerVec(name string, _ ...string) TimerVec {
+	return recordingTimerVecMock{name: r.system + "." + name, registry: r}
+}
+
+func (r *timersRegistryMock) WithSystem(subsystem string) Config {
+	return &timersRegistryMock{
+		registryMock: r.registryMock.WithSystem(subsystem).(*registryMock),
+		mu:           r.mu,
+		timers:       r.timers,
+	}
+}
+
+func TestDiscovery(t *testing.T) {
+	ctx := context.Background()
+	registry := &timersRegistryMock{
+		registryMock: newRegistryMock(),
+		mu:           &sync.Mutex{},
+		timers:       make(map[string][]time.Duration),
+	}
+	d := Discovery(registry)
+
+	trace.DiscoveryOnDiscover(&d, &ctx, stack.FunctionID(""), "localhost:2135", "/local")(
+		"", nil, time.Second, nil,
+	)
+	trace.DiscoveryOnDiscover(&d, &ctx, stack.FunctionID(""), "localhost:2135", "/local")(
+		"", nil, 2*time.Second, errors.New("test"),
+	)
+	require.Equal(t, map[string][]time.Duration{
+		"ydb.discovery.latency{status=OK}":      {time.Second},
+		"ydb.discovery.latency{status=unknown}": {2 * time.Second},
+	}, registry.timers)
+}
@@ -2,12 +2,27 @@ package metrics
 
 import (
 	"strconv"
+	"sync"
 
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/repeater"
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xerrors"
 	"github.com/ydb-platform/ydb-go-sdk/v3/trace"
 )
 
+// Driver makes trace.Driver which publishes driver metrics into registry from config.
+// Driver metrics contains counters of discovery attempts (by status), ban and unban events
+// and gauges of preferred, fallback and banned endpoints counts.
+// Driver trace may be merged into driver config with ydb.WithTraceDriver
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func Driver(config Config) trace.Driver {
+	if config == nil {
+		return trace.Driver{}
+	}
+
+	return driver(config.WithSystem("ydb"))
+}
+
 // driver makes driver with New publishing
 //
 //nolint:funlen
@@ -21,6 +36,17 @@ func driver(config Config) (t trace.Driver) {
 	requestStatuses := config.WithSystem("conn").CounterVec("request_statuses", "status", "endpoint", "node_id")
 	requestMethods := config.WithSystem("conn").CounterVec("request_methods", "method", "endpoint", "node_id")
 	tli := config.CounterVec("transaction_locks_invalidated")
+	preferred := config.WithSystem("balancer").GaugeVec("preferred")
+	fallback := config.WithSystem("balancer").GaugeVec("fallback")
+	bannedEndpoints := config.WithSystem("balancer").GaugeVec("banned")
+	bans := config.WithSystem("conn").CounterVec("bans", "endpoint", "node_id")
+	unbans := config.WithSystem("conn").CounterVec("unbans", "endpoint", "node_id")
+
+	// bannedAddresses contains addresses of banned endpoints for banned endpoints gauge
+	var (
+		bannedAddressesMtx sync.Mutex
+		bannedAddresses    = make(map[string]struct{})
+	)
 
 	type endpointKey struct {
 		az string
@@ -77,15 +103,50 @@ func driver(config Config) (t trace.Driver) {
 		}
 	}
 	t.OnConnBan = func(info trace.DriverConnBanStartInfo) func(trace.DriverConnBanDoneInfo) {
-		if config.Details()&trace.DriverConnEvents != 0 {
-			banned.With(map[string]string{
-				"endpoint": info.Endpoint.Address(),
-				"node_id":  idToString(info.Endpoint.NodeID()),
-				"cause":    errorBrief(info.Cause),
-			}).Add(1)
+		if config.Details()&trace.DriverConnEvents == 0 {
+			return nil
 		}
 
-		return nil
+		endpoint := info.Endpoint
+		banned.With(map[string]string{
+			"endpoint": endpoint.Address(),
+			"node_id":  idToString(endpoint.NodeID()),
+			"cause":    errorBrief(info.Cause),
+		}).Add(1)
+		bans.With(map[string]string{
+			"endpoint": endpoint.Address(),
+			"node_id":  idToString(endpoint.NodeID()),
+		}).Inc()
+
+		return func(info trace.DriverConnBanDoneInfo) {
+			if info.State != nil && info.State.String() == "banned" {
+				bannedAddressesMtx.Lock()
+				defer bannedAddressesMtx.Unlock()
+				bannedAddresses[endpoint.Address()] = struct{}{}
+				bannedEndpoints.With(nil).Set(float64(len(bannedAddresses)))
+			}
+		}
+	}
+	t.OnConnAllow = func(info trace.DriverConnAllowStartInfo) func(trace.DriverConnAllowDoneInfo) {
+		if config.Details()&trace.DriverConnEvents == 0 {
+			return nil
+		}
+
+		endpoint := info.Endpoint
+
+		return func(info trace.DriverConnAllowDoneInfo) {
+			bannedAddressesMtx.Lock()
+			defer bannedAddressesMtx.Unlock()
+			if _, has := bannedAddresses[endpoint.Address()]; !has {
+				return
+			}
+			delete(bannedAddresses, endpoint.Address())
+			bannedEndpoints.With(nil).Set(float64(len(bannedAddresses)))
+			unbans.With(map[string]string{
+				"endpoint": endpoint.Address(),
+				"node_id":  idToString(endpoint.NodeID()),
+			}).Inc()
+		}
 	}
 	t.OnBalancerClusterDiscoveryAttempt = func(info trace.DriverBalancerClusterDiscoveryAttemptStartInfo) func(
 		trace.DriverBalancerClusterDiscoveryAttemptDoneInfo,
@@ -128,6 +189,20 @@ func driver(config Config) (t trace.Driver) {
 						"az": e.az,
 					}).Set(float64(count))
 				}
+				preferred.With(nil).Set(float64(info.Preferred))
+				fallback.With(nil).Set(float64(info.Fallback))
+				actual := make(map[string]struct{}, len(info.Endpoints))
+				for _, e := range info.Endpoints {
+					actual[e.Address()] = struct{}{}
+				}
+				bannedAddressesMtx.Lock()
+				for address := range bannedAddresses {
+					if _, has := actual[address]; !has {
+						delete(bannedAddresses, address)
+					}
+				}
+				bannedEndpoints.With(nil).Set(float64(len(bannedAddresses)))
+				bannedAddressesMtx.Unlock()
 			}
 		}
 	}
@@ -0,0 +1,154 @@
+package metrics
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/conn"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/endpoint"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/stack"
+	"github.com/ydb-platform/ydb-go-sdk/v3/trace"
+)
+
+type registryMock struct {
+	system string
+	mu     *sync.Mutex
+	values map[string]float64
+}
+
+func newRegistryMock() *registryMock {
+	return &registryMock{
+		mu:     &sync.Mutex{},
+		values: make(map[string]float64),
+	}
+}
+
+type valueMock struct {
+	name     string
+	registry *registryMock
+}
+
+func (v valueMock) Inc() {
+	v.Add(1)
+}
+
+func (v valueMock) Add(delta float64) {
+	v.registry.mu.Lock()
+	defer v.registry.mu.Unlock()
+	v.registry.values[v.name] += delta
+}
+
+func (v valueMock) Set(value float64) {
+	v.registry.mu.Lock()
+	defer v.registry.mu.Unlock()
+	v.registry.values[v.name] = value
+}
+
+func (v valueMock) Record(float64) {}
+
+type vecMock struct {
+	name     string
+	registry *registryMock
+}
+
+func (v vecMock) With(map[string]string) Counter {
+	return valueMock(v)
+}
+
+type gaugeVecMock vecMock
+
+func (v gaugeVecMock) With(map[string]string) Gauge {
+	return valueMock(v)
+}
+
+type timerVecMock struct{}
+
+func (timerVecMock) With(map[string]string) Timer {
+	return timerMock{}
+}
+
+type timerMock struct{}
+
+func (timerMock) Record(time.Duration) {}
+
+type histogramVecMock vecMock
+
+func (v histogramVecMock) With(map[string]string) Histogram {
+	return valueMock(v)
+}
+
+func (r *registryMock) CounterVec(name string, _ ...string) CounterVec {
+	return vecMock{name: r.system + "." + name, registry: r}
+}
+
+func (r *registryMock) GaugeVec(name string, _ ...string) GaugeVec {
+	return gaugeVecMock{name: r.system + "." + name, registry: r}
+}
+
+func (r *registryMock) TimerVec(string, ...string) TimerVec {
+	return timerVecMock{}
+}
+
+func (r *registryMock) HistogramVec(name string, _ []float64, _ ...string) HistogramVec {
+	return histogramVecMock{name: r.system + "." + name, registry: r}
+}
+
+func (r *registryMock) Details() trace.Details {
+	return trace.DetailsAll
+}
+
+func (r *registryMock) WithSystem(subsystem string) Config {
+	return &registryMock{
+		system: strings.TrimPrefix(r.system+"."+subsystem, "."),
+		mu:     r.mu,
+		values: r.values,
+	}
+}
+
+func (r *registryMock) value(name string) float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.values[name]
+}
+
+func TestDriver(t *testing.T) {
+	ctx := context.Background()
+	registry := newRegistryMock()
+	d := Driver(registry)
+
+	e1 := endpoint.New("a:1", endpoint.WithID(1))
+	e2 := endpoint.New("b:2", endpoint.WithID(2))
+
+	trace.DriverOnBalancerUpdate(&d, &ctx, stack.FunctionID(""), false)(
+		[]trace.EndpointInfo{e1, e2}, []trace.EndpointInfo{e1, e2}, nil, "", 2, 0, "",
+	)
+	require.EqualValues(t, 2, registry.value("ydb.driver.balancer.preferred"))
+	require.EqualValues(t, 0, registry.value("ydb.driver.balancer.fallback"))
+
+	trace.DriverOnConnBan(&d, &ctx, stack.FunctionID(""), e1, conn.Online, nil)(conn.Banned)
+	require.EqualValues(t, 1, registry.value("ydb.driver.conn.bans"))
+	require.EqualValues(t, 1, registry.value("ydb.driver.balancer.banned"))
+
+	trace.DriverOnConnAllow(&d, &ctx, stack.FunctionID(""), e2, conn.Online)(conn.Online)
+	require.EqualValues(t, 0, registry.value("ydb.driver.conn.unbans"))
+
+	trace.DriverOnConnAllow(&d, &ctx, stack.FunctionID(""), e1, conn.Banned)(conn.Online)
+	require.EqualValues(t, 1, registry.value("ydb.driver.conn.unbans"))
+	require.EqualValues(t, 0, registry.value("ydb.driver.balancer.banned"))
+
+	t.Run("DroppedBannedEndpoint", func(t *testing.T) {
+		trace.DriverOnConnBan(&d, &ctx, stack.FunctionID(""), e2, conn.Online, nil)(conn.Banned)
+		require.EqualValues(t, 1, registry.value("ydb.driver.balancer.banned"))
+		trace.DriverOnBalancerUpdate(&d, &ctx, stack.FunctionID(""), false)(
+			[]trace.EndpointInfo{e1}, nil, []trace.EndpointInfo{e2}, "", 1, 0, "",
+		)
+		require.EqualValues(t, 0, registry.value("ydb.driver.balancer.banned"))
+		require.EqualValues(t, 1, registry.value("ydb.driver.balancer.preferred"))
+	})
+}
@@ -35,5 +35,16 @@ func retry(config Config) (t trace.Retry) {
 		}
 	}
 
+	t.OnRetryAttempt = func(info trace.RetryAttemptInfo) {
+		if info.Label == "" || config.Details()&trace.RetryEvents == 0 {
+			return
+		}
+		errs.With(map[string]string{
+			"status":      errorBrief(info.Error),
+			"retry_label": info.Label,
+			"final":       "false",
+		}).Inc()
+	}
+
 	return t
 }
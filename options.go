@@ -5,10 +5,13 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"fmt"
+	"net"
 	"os"
 	"path/filepath"
 	"time"
 
+	"google.golang.org/grpc/encoding"
+
 	"github.com/ydb-platform/ydb-go-sdk/v3/config"
 	"github.com/ydb-platform/ydb-go-sdk/v3/credentials"
 	balancerConfig "github.com/ydb-platform/ydb-go-sdk/v3/internal/balancer/config"
@@ -17,6 +20,7 @@ import (
 	coordinationConfig "github.com/ydb-platform/ydb-go-sdk/v3/internal/coordination/config"
 	discoveryConfig "github.com/ydb-platform/ydb-go-sdk/v3/internal/discovery/config"
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/dsn"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/meta"
 	queryConfig "github.com/ydb-platform/ydb-go-sdk/v3/internal/query/config"
 	ratelimiterConfig "github.com/ydb-platform/ydb-go-sdk/v3/internal/ratelimiter/config"
 	schemeConfig "github.com/ydb-platform/ydb-go-sdk/v3/internal/scheme/config"
@@ -25,6 +29,7 @@ import (
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xerrors"
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xsql"
 	"github.com/ydb-platform/ydb-go-sdk/v3/log"
+	"github.com/ydb-platform/ydb-go-sdk/v3/retry"
 	"github.com/ydb-platform/ydb-go-sdk/v3/retry/budget"
 	"github.com/ydb-platform/ydb-go-sdk/v3/topic/topicoptions"
 	"github.com/ydb-platform/ydb-go-sdk/v3/trace"
@@ -44,6 +49,18 @@ func WithStaticCredentials(user, password string) Option {
 	}
 }
 
+// WithMaxDiscoveredEndpoints limits count of endpoints in discovery response for protect driver
+// from misbehaving discovery endpoint. Discovery with greater count of endpoints fails
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithMaxDiscoveredEndpoints(maxEndpoints int) Option {
+	return func(ctx context.Context, c *Driver) error {
+		c.discoveryOptions = append(c.discoveryOptions, discoveryConfig.WithMaxEndpoints(maxEndpoints))
+
+		return nil
+	}
+}
+
 // WithNodeAddressMutator applies mutator for node addresses from discovery.ListEndpoints response
 //
 // Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
@@ -139,6 +156,39 @@ func WithApplicationName(applicationName string) Option {
 	}
 }
 
+// WithProductToken prepends product token "name/version" to sdk version in version header of all api requests
+// (such as "myorm/1.2 ydb-go-sdk/3.53.3") for attribute traffic of higher-level libraries in server-side telemetry
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithProductToken(name, version string) Option {
+	return func(ctx context.Context, c *Driver) error {
+		c.options = append(c.options, config.WithProductToken(name, version))
+
+		return nil
+	}
+}
+
+// WithStaticMetadata appends static headers to metadata of all api requests (discovery and data-plane requests)
+//
+// Static headers cannot override reserved headers of SDK (such as database, ticket, version) and keys with
+// prefix of request tags: WithStaticMetadata returns error on reserved key.
+// Headers from outgoing metadata of request context and per-call request tags (balancers.WithRequestTag)
+// takes precedence over static headers with same key
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithStaticMetadata(md map[string]string) Option {
+	return func(ctx context.Context, c *Driver) error {
+		for key := range md {
+			if meta.IsReservedHeader(key) {
+				return xerrors.WithStackTrace(fmt.Errorf("static metadata key '%s' is reserved", key))
+			}
+		}
+		c.options = append(c.options, config.WithStaticMetadata(md))
+
+		return nil
+	}
+}
+
 // WithUserAgent add provided user agent value to all api requests
 //
 // Deprecated: use WithApplicationName instead.
@@ -185,6 +235,24 @@ func WithConnectionString(connectionString string) Option {
 	}
 }
 
+// WithCompression enables gRPC compression (such as "gzip") of request messages of unary calls with size
+// not less than minMessageSize. Small messages stay uncompressed for avoid of CPU overhead.
+// Client advertises all registered compressors, so server may compress responses with same algorithm.
+// Compressor from per-call options (grpc.UseCompressor) of higher-level services takes precedence
+// over WithCompression. Streaming calls not compresses by WithCompression
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithCompression(algorithm string, minMessageSize int) Option {
+	return func(ctx context.Context, c *Driver) error {
+		if algorithm != "" && encoding.GetCompressor(algorithm) == nil {
+			return xerrors.WithStackTrace(fmt.Errorf("unknown gRPC compressor '%s'", algorithm))
+		}
+		c.options = append(c.options, config.WithCompression(algorithm, minMessageSize))
+
+		return nil
+	}
+}
+
 // WithConnectionTTL defines duration for parking idle connections
 func WithConnectionTTL(ttl time.Duration) Option {
 	return func(ctx context.Context, c *Driver) error {
@@ -194,6 +262,39 @@ func WithConnectionTTL(ttl time.Duration) Option {
 	}
 }
 
+// WithKeepalive defines grpc keepalive parameters of discovery and pooled connections for keep alive
+// idle connections through intermediary load balancers.
+//
+// Default keepalive parameters is config.DefaultGrpcConnectionPolicy. Keepalive time must not be lower than
+// server-side keepalive enforcement interval, otherwise server closes connections with GOAWAY "too_many_pings".
+// Pings without active streams also must be permitted by server if permitWithoutStream is true
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithKeepalive(keepaliveTime, timeout time.Duration, permitWithoutStream bool) Option {
+	return func(ctx context.Context, c *Driver) error {
+		c.options = append(c.options,
+			config.WithKeepaliveTime(keepaliveTime),
+			config.WithKeepaliveTimeout(timeout),
+			config.WithKeepalivePermitWithoutStream(permitWithoutStream),
+		)
+
+		return nil
+	}
+}
+
+// WithContextDialer defines custom dialer of network connections instead of default TCP dialer
+// (such as net.Pipe for in-memory tests or dialer through proxy or tunnel).
+// Dialer receives resolved address of endpoint and used for discovery and for pooled connections
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithContextDialer(dialer func(ctx context.Context, address string) (net.Conn, error)) Option {
+	return func(ctx context.Context, c *Driver) error {
+		c.options = append(c.options, config.WithContextDialer(dialer))
+
+		return nil
+	}
+}
+
 // WithEndpoint defines endpoint option
 //
 // Warning: use ydb.Open with required Driver string parameter instead
@@ -207,6 +308,51 @@ func WithEndpoint(endpoint string) Option {
 	}
 }
 
+// WithBootstrapEndpoints defines list of endpoints for initial cluster discovery
+//
+// Endpoints are tried in order until one of them answers on discovery request.
+// If all of endpoints failed - returned error contains all of causes
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithBootstrapEndpoints(endpoints ...string) Option {
+	return func(ctx context.Context, c *Driver) error {
+		c.options = append(c.options, config.WithBootstrapEndpoints(endpoints...))
+
+		return nil
+	}
+}
+
+// WithFailoverDatabase defines standby database (endpoint and database name) for disaster recovery.
+// Driver routes calls to standby database if calls of primary database fails without successful call
+// during failoverDelay (primary database has no available endpoints (balancers.ErrNoEndpoints), has no online
+// connections or calls fails with transport errors), and fails back to primary database after primary database
+// has available connections during failbackDelay. Driver connects to standby database on first failover,
+// so unavailable standby database not fails start of driver. Standby database uses credentials, TLS and
+// balancer options of primary database. Trace event trace.Driver.OnBalancerFailover fires on failover and failback
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithFailoverDatabase(endpoint, database string, failoverDelay, failbackDelay time.Duration) Option {
+	return func(ctx context.Context, c *Driver) error {
+		c.options = append(c.options, config.WithFailoverDatabase(endpoint, database, failoverDelay, failbackDelay))
+
+		return nil
+	}
+}
+
+// WithDialTarget defines grpc dial target of driver endpoint (such as "xds:///cluster") for custom grpc name resolver
+//
+// Dial target used verbatim for initial discovery. Scheme of dial target used for dial of discovered endpoints.
+// Custom grpc name resolver must be registered globally or with ydb.With(config.WithGrpcOptions(grpc.WithResolvers(...)))
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithDialTarget(target string) Option {
+	return func(ctx context.Context, c *Driver) error {
+		c.options = append(c.options, config.WithDialTarget(target))
+
+		return nil
+	}
+}
+
 // WithDatabase defines database option
 //
 // Warning: use ydb.Open with required Driver string parameter instead
@@ -357,6 +503,60 @@ func WithDiscoveryInterval(discoveryInterval time.Duration) Option {
 	}
 }
 
+// WithDiscoveryRetryOptions appends options of retries of cluster discovery over defaults
+// (idempotent retries with driver retry trace and retry budget until done of context).
+// For example, retry.WithBudget(budget.Limited(...)) limits attempts of initial discovery for fail fast of ydb.Open
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithDiscoveryRetryOptions(opts ...retry.Option) Option {
+	return func(ctx context.Context, c *Driver) error {
+		c.discoveryOptions = append(c.discoveryOptions, discoveryConfig.WithRetryOptions(opts...))
+
+		return nil
+	}
+}
+
+// WithRawDiscoveryResponseHandler defines handler of serialized result of every successful cluster discovery
+// (such as for shared external cache of discovery results across short-lived processes).
+// Serialized result accepts by balancers.WithInitialState. Handler calls synchronously in discovery,
+// so slow handler must offload work. Panics of handler are recovered and never fail discovery
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithRawDiscoveryResponseHandler(handler func(response []byte)) Option {
+	return func(ctx context.Context, c *Driver) error {
+		c.discoveryOptions = append(c.discoveryOptions, discoveryConfig.WithRawResponseHandler(handler))
+
+		return nil
+	}
+}
+
+// WithPersistentDiscoveryConnection makes driver dial dedicated connection for cluster discovery once
+// and reuse it across discovery cycles (redial only after connection-level transport error such as Unavailable).
+// Driver closes connection on close.
+// Persistent connection trades held socket for lower overhead of discovery with short discovery interval
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithPersistentDiscoveryConnection(persistent bool) Option {
+	return func(ctx context.Context, c *Driver) error {
+		c.discoveryOptions = append(c.discoveryOptions, discoveryConfig.WithPersistentConnection(persistent))
+
+		return nil
+	}
+}
+
+// WithDiscoveryInitialJitter sets maximum random delay before initial cluster discovery
+// for spread discovery load of many simultaneously started instances (such as after deploy).
+// Delay is bounded by deadline of context of ydb.Open
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithDiscoveryInitialJitter(jitter time.Duration) Option {
+	return func(ctx context.Context, c *Driver) error {
+		c.discoveryOptions = append(c.discoveryOptions, discoveryConfig.WithInitialJitter(jitter))
+
+		return nil
+	}
+}
+
 // WithRetryBudget sets retry budget for all calls of all retryers.
 //
 // Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
@@ -434,7 +634,8 @@ func WithCertificatesFromFile(caFile string, opts ...certificates.FromFileOption
 // WithTLSConfig replaces older TLS config
 //
 // Warning: all early TLS config changes (such as WithCertificate, WithCertificatesFromFile, WithCertificatesFromPem,
-// WithMinTLSVersion, WithTLSSInsecureSkipVerify) will be lost
+// WithMinTLSVersion, WithTLSSInsecureSkipVerify) will be lost.
+// TLS config takes precedence over client certificates from WithClientCertificate
 func WithTLSConfig(tlsConfig *tls.Config) Option {
 	return func(ctx context.Context, c *Driver) error {
 		c.options = append(c.options, config.WithTLSConfig(tlsConfig))
@@ -443,7 +644,22 @@ func WithTLSConfig(tlsConfig *tls.Config) Option {
 	}
 }
 
-// WithCertificatesFromPem appends certificates from pem-encoded data to TLS config root certificates
+// WithClientCertificate appends client certificate for mutual TLS authentication of client
+//
+// Warning: client certificate has no effect if TLS config defined with WithTLSConfig
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithClientCertificate(cert tls.Certificate) Option {
+	return func(ctx context.Context, c *Driver) error {
+		c.options = append(c.options, config.WithClientCertificate(cert))
+
+		return nil
+	}
+}
+
+// WithCertificatesFromPem appends certificates from pem-encoded data to TLS config root certificates.
+// Use certificates.FromPemStrict(true) option for require every pem block to be valid certificate,
+// otherwise New returns error with certificates.ErrMalformedPem
 func WithCertificatesFromPem(bytes []byte, opts ...certificates.FromPemOption) Option {
 	return func(ctx context.Context, c *Driver) error {
 		certs, err := certificates.FromPem(bytes, opts...)
@@ -458,6 +674,21 @@ func WithCertificatesFromPem(bytes []byte, opts ...certificates.FromPemOption) O
 	}
 }
 
+// WithSystemCertPool defines usage of system certificates pool as base of TLS config root certificates.
+// If use is true - certificates from WithCertificate, WithCertificatesFromFile and WithCertificatesFromPem
+// appends to copy of system pool (such as for private CA of YDB cluster and public CA of token endpoint
+// of credentials), otherwise only these certificates are trusted.
+// System certificates pool used by default
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithSystemCertPool(use bool) Option {
+	return func(ctx context.Context, c *Driver) error {
+		c.options = append(c.options, config.WithSystemCertPool(use))
+
+		return nil
+	}
+}
+
 // WithTableConfigOption collects additional configuration options for table.Client.
 // This option does not replace collected option, instead it will appen provided options.
 func WithTableConfigOption(option tableConfig.Option) Option {
@@ -0,0 +1,22 @@
+package ydb
+
+import (
+	"context"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/config"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/balancer"
+)
+
+// EndpointProbeResult is a result of probe of discovered endpoint with round-trip time and error
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+type EndpointProbeResult = balancer.EndpointProbeResult
+
+// ProbeEndpoints makes dry-run discovery with driver config and probes each discovered endpoint
+// with lightweight request. ProbeEndpoints does not initialize driver and useful for preflight
+// connectivity checks (such as before switching service to new cluster)
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func ProbeEndpoints(ctx context.Context, cfg *config.Config) ([]EndpointProbeResult, error) {
+	return balancer.Probe(ctx, cfg)
+}
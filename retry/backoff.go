@@ -6,6 +6,24 @@ import (
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/backoff"
 )
 
+const (
+	// NoJitter keeps backoff delay as is
+	//
+	// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+	NoJitter = backoff.NoJitter
+
+	// FullJitter makes backoff delay as random duration from zero to calculated exponential delay
+	//
+	// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+	FullJitter = backoff.FullJitter
+
+	// DecorrelatedJitter makes backoff delay as random duration from slot duration to triple
+	// delay of previous attempt limited by maximum delay
+	//
+	// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+	DecorrelatedJitter = backoff.DecorrelatedJitter
+)
+
 // Backoff makes backoff object with custom params
 func Backoff(slotDuration time.Duration, ceiling uint, jitterLimit float64) backoff.Backoff {
 	return backoff.New(
@@ -3,6 +3,8 @@ package budget
 import (
 	"context"
 	"fmt"
+	"math"
+	"sync"
 	"time"
 
 	"github.com/jonboulle/clockwork"
@@ -28,6 +30,16 @@ type (
 		percent int
 		rand    xrand.Rand
 	}
+	tokenBucketBudget struct {
+		clock    clockwork.Clock
+		capacity float64
+		rate     float64
+
+		mu     sync.Mutex
+		tokens float64
+		last   time.Time
+	}
+	tokenBucketBudgetOption func(b *tokenBucketBudget)
 )
 
 func withFixedBudgetClock(clock clockwork.Clock) fixedBudgetOption {
@@ -116,3 +128,52 @@ func (b *percentBudget) Acquire(ctx context.Context) error {
 
 	return ErrNoQuota
 }
+
+func withTokenBucketClock(clock clockwork.Clock) tokenBucketBudgetOption {
+	return func(b *tokenBucketBudget) {
+		b.clock = clock
+	}
+}
+
+// TokenBucket makes budget with capacity of tokens which refills with refillPerSecond rate.
+// Unlike Limited budget, TokenBucket does not wait for quota and returns ErrNoQuota immediately
+// if no tokens left. Single TokenBucket budget can be shared between concurrent retryers
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func TokenBucket(capacity int, refillPerSecond float64, opts ...tokenBucketBudgetOption) *tokenBucketBudget {
+	b := &tokenBucketBudget{
+		clock:    clockwork.NewRealClock(),
+		capacity: float64(capacity),
+		rate:     refillPerSecond,
+		tokens:   float64(capacity),
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	b.last = b.clock.Now()
+
+	return b
+}
+
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func (b *tokenBucketBudget) Acquire(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return xerrors.WithStackTrace(err)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.clock.Now()
+	if b.rate > 0 {
+		b.tokens = math.Min(b.capacity, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	}
+	b.last = now
+
+	if b.tokens < 1 {
+		return ErrNoQuota
+	}
+	b.tokens--
+
+	return nil
+}
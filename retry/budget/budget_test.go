@@ -70,3 +70,37 @@ func TestPercent(t *testing.T) {
 		require.LessOrEqual(t, success, int(float64(total)*(percent+0.1*percent)))
 	}, xtest.StopAfter(5*time.Second))
 }
+
+func TestTokenBucket(t *testing.T) {
+	ctx := xtest.Context(t)
+	t.Run("Capacity", func(t *testing.T) {
+		b := TokenBucket(3, 1, withTokenBucketClock(clockwork.NewFakeClock()))
+		for i := 0; i < 3; i++ {
+			require.NoError(t, b.Acquire(ctx))
+		}
+		require.ErrorIs(t, b.Acquire(ctx), ErrNoQuota)
+	})
+	t.Run("BoundedByRefillRate", func(t *testing.T) {
+		var (
+			clock    = clockwork.NewFakeClock()
+			b        = TokenBucket(10, 5, withTokenBucketClock(clock))
+			acquired int
+		)
+		// sustained failures: 100 retryers try to acquire quota every 100ms during 10s
+		for tick := 0; tick < 100; tick++ {
+			for retryer := 0; retryer < 100; retryer++ {
+				if b.Acquire(ctx) == nil {
+					acquired++
+				}
+			}
+			clock.Advance(100 * time.Millisecond)
+		}
+		// initial capacity and refills for 10s (last refill not acquired)
+		require.Equal(t, 10+5*10-1, acquired)
+	})
+	t.Run("Canceled", func(t *testing.T) {
+		ctx, cancel := xcontext.WithCancel(ctx)
+		cancel()
+		require.ErrorIs(t, TokenBucket(1, 1).Acquire(ctx), context.Canceled)
+	})
+}
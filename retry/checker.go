@@ -0,0 +1,73 @@
+package retry
+
+import (
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/backoff"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xerrors"
+)
+
+// RetryType is a decision of custom retryable checker about retry of error
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+type RetryType int
+
+const (
+	// UnknownRetryType defers decision about retry of error to built-in classification of errors
+	UnknownRetryType = RetryType(iota)
+
+	// NonRetryable stops retry loop with error
+	NonRetryable
+
+	// Retryable retries operation immediately without backoff
+	Retryable
+
+	// RetryableWithBackoff retries operation after backoff. Backoff type of error uses if defined,
+	// otherwise fast backoff uses
+	RetryableWithBackoff
+)
+
+var _ Option = retryableCheckerOption(nil)
+
+type retryableCheckerOption func(err error) RetryType
+
+func (checker retryableCheckerOption) ApplyRetryOption(opts *retryOptions) {
+	opts.retryableChecker = checker
+}
+
+func (checker retryableCheckerOption) ApplyDoOption(opts *doOptions) {
+	opts.retryOptions = append(opts.retryOptions, WithRetryableChecker(checker))
+}
+
+func (checker retryableCheckerOption) ApplyDoTxOption(opts *doTxOptions) {
+	opts.retryOptions = append(opts.retryOptions, WithRetryableChecker(checker))
+}
+
+// WithRetryableChecker defines custom classification of errors (such as application-level errors)
+// which consulted before built-in classification. Built-in classification uses only if checker returns
+// UnknownRetryType. Retryable decision of checker retries operation regardless of idempotency of operation
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithRetryableChecker(checker func(err error) RetryType) retryableCheckerOption {
+	return checker
+}
+
+// check returns retry mode of error with custom retryable checker if defined
+func (opts *retryOptions) check(err error) retryMode {
+	m := Check(err)
+	if opts.retryableChecker == nil {
+		return m
+	}
+
+	switch opts.retryableChecker(err) {
+	case NonRetryable:
+		m.errType, m.backoff = xerrors.TypeNonRetryable, backoff.TypeNoBackoff
+	case Retryable:
+		m.errType, m.backoff = xerrors.TypeRetryable, backoff.TypeNoBackoff
+	case RetryableWithBackoff:
+		m.errType = xerrors.TypeRetryable
+		if !m.MustBackoff() {
+			m.backoff = backoff.TypeFast
+		}
+	}
+
+	return m
+}
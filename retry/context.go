@@ -4,8 +4,21 @@ import "context"
 
 type (
 	ctxIsOperationIdempotentKey struct{}
+	ctxAttemptKey               struct{}
 )
 
+func withAttempt(ctx context.Context, attempt int) context.Context {
+	return context.WithValue(ctx, ctxAttemptKey{}, attempt)
+}
+
+// Attempt returns number of attempt of retry operation with context (such as for tracing of calls).
+// First attempt has number 1. Attempt returns 0 for context outside of retry operation
+func Attempt(ctx context.Context) int {
+	attempt, _ := ctx.Value(ctxAttemptKey{}).(int)
+
+	return attempt
+}
+
 // WithIdempotentOperation returns a copy of parent context with idempotent operation feature
 //
 // Deprecated: use retry.WithIdempotent option instead.
@@ -26,9 +26,13 @@ type retryOptions struct {
 	stackTrace  bool
 	fastBackoff backoff.Backoff
 	slowBackoff backoff.Backoff
+	jitter      backoff.Jitter
 	budget      budget.Budget
 
-	panicCallback func(e interface{})
+	honorServerRetryHint bool
+
+	panicCallback    func(e interface{})
+	retryableChecker func(err error) RetryType
 }
 
 type Option interface {
@@ -126,6 +130,11 @@ func WithTrace(t *trace.Retry) traceOption {
 	return traceOption{t: t}
 }
 
+// Budget limits retry attempts. Single budget can be shared between concurrent retryers
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+type Budget = budget.Budget
+
 var _ Option = budgetOption{}
 
 type budgetOption struct {
@@ -172,6 +181,34 @@ func WithIdempotent(idempotent bool) idempotentOption {
 	return idempotentOption(idempotent)
 }
 
+// maxServerRetryHint is a maximum delay before retry from server retry hint
+const maxServerRetryHint = time.Minute
+
+var _ Option = honorServerRetryHintOption(false)
+
+type honorServerRetryHintOption bool
+
+func (honor honorServerRetryHintOption) ApplyRetryOption(opts *retryOptions) {
+	opts.honorServerRetryHint = bool(honor)
+}
+
+func (honor honorServerRetryHintOption) ApplyDoOption(opts *doOptions) {
+	opts.retryOptions = append(opts.retryOptions, WithHonorServerRetryHint(bool(honor)))
+}
+
+func (honor honorServerRetryHintOption) ApplyDoTxOption(opts *doTxOptions) {
+	opts.retryOptions = append(opts.retryOptions, WithHonorServerRetryHint(bool(honor)))
+}
+
+// WithHonorServerRetryHint makes retry wait not less than server retry hint from error
+// (google.rpc.RetryInfo detail of gRPC status) before next attempt. Server retry hint is capped by one minute.
+// Retry uses backoff delay if error has no server retry hint
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithHonorServerRetryHint(honor bool) honorServerRetryHintOption {
+	return honorServerRetryHintOption(honor)
+}
+
 var _ Option = fastBackoffOption{}
 
 type fastBackoffOption struct {
@@ -222,6 +259,30 @@ func WithSlowBackoff(b backoff.Backoff) slowBackoffOption {
 	return slowBackoffOption{backoff: b}
 }
 
+var _ Option = jitterOption(0)
+
+type jitterOption backoff.Jitter
+
+func (jitter jitterOption) ApplyRetryOption(opts *retryOptions) {
+	opts.jitter = backoff.Jitter(jitter)
+}
+
+func (jitter jitterOption) ApplyDoOption(opts *doOptions) {
+	opts.retryOptions = append(opts.retryOptions, WithJitter(backoff.Jitter(jitter)))
+}
+
+func (jitter jitterOption) ApplyDoTxOption(opts *doTxOptions) {
+	opts.retryOptions = append(opts.retryOptions, WithJitter(backoff.Jitter(jitter)))
+}
+
+// WithJitter applies jitter strategy to fast and slow backoff
+// Jitter applies to default backoff and backoff created with Backoff function
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithJitter(jitter backoff.Jitter) jitterOption {
+	return jitterOption(jitter)
+}
+
 var _ Option = panicCallbackOption{}
 
 type panicCallbackOption struct {
@@ -307,6 +368,10 @@ func RetryWithResult[T any](ctx context.Context, //nolint:revive,funlen
 	if options.idempotent {
 		ctx = xcontext.WithIdempotent(ctx, options.idempotent)
 	}
+	if options.jitter != backoff.NoJitter {
+		options.fastBackoff = backoff.WithJitter(options.fastBackoff, options.jitter)
+		options.slowBackoff = backoff.WithJitter(options.slowBackoff, options.jitter)
+	}
 
 	defer func() {
 		if finalErr != nil && options.stackTrace {
@@ -340,13 +405,13 @@ func RetryWithResult[T any](ctx context.Context, //nolint:revive,funlen
 			))
 
 		default:
-			v, err := opWithRecover(ctx, options, op)
+			v, err := opWithRecover(withAttempt(ctx, attempts), options, op)
 
 			if err == nil {
 				return v, nil
 			}
 
-			m := Check(err)
+			m := options.check(err)
 
 			if m.StatusCode() != code {
 				i = 0
@@ -362,10 +427,19 @@ func RetryWithResult[T any](ctx context.Context, //nolint:revive,funlen
 				))
 			}
 
-			t := time.NewTimer(backoff.Delay(m.BackoffType(), i,
+			delay := backoff.Delay(m.BackoffType(), i,
 				backoff.WithFastBackoff(options.fastBackoff),
 				backoff.WithSlowBackoff(options.slowBackoff),
-			))
+			)
+			if options.honorServerRetryHint {
+				delay = withServerRetryHint(delay, err)
+			}
+
+			trace.RetryOnRetryAttempt(options.trace, &ctx,
+				options.call, options.label, attempts, err, delay, options.idempotent,
+			)
+
+			t := time.NewTimer(delay)
 
 			select {
 			case <-ctx.Done():
@@ -398,6 +472,25 @@ func RetryWithResult[T any](ctx context.Context, //nolint:revive,funlen
 	}
 }
 
+// withServerRetryHint returns server retry hint from error (capped by maxServerRetryHint) if hint is greater
+// than backoff delay
+func withServerRetryHint(delay time.Duration, err error) time.Duration {
+	hint, ok := xerrors.RetryDelay(err)
+	if !ok {
+		return delay
+	}
+
+	if hint > maxServerRetryHint {
+		hint = maxServerRetryHint
+	}
+
+	if hint > delay {
+		return hint
+	}
+
+	return delay
+}
+
 func opWithRecover[T any](ctx context.Context,
 	options *retryOptions, op func(context.Context) (T, error),
 ) (_ T, finalErr error) {
@@ -10,12 +10,17 @@ import (
 
 	"github.com/stretchr/testify/require"
 	"github.com/ydb-platform/ydb-go-genproto/protos/Ydb"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	grpcCodes "google.golang.org/grpc/codes"
 	grpcStatus "google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
 
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/backoff"
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xcontext"
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xerrors"
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xtest"
+	"github.com/ydb-platform/ydb-go-sdk/v3/retry/budget"
+	"github.com/ydb-platform/ydb-go-sdk/v3/trace"
 )
 
 func TestRetryModes(t *testing.T) {
@@ -188,6 +193,30 @@ func TestRetryWithBudget(t *testing.T) {
 	})
 }
 
+type noBackoff struct{}
+
+func (noBackoff) Delay(int) time.Duration {
+	return 0
+}
+
+func TestRetryWithTokenBucketBudget(t *testing.T) {
+	var (
+		ctx      = xtest.Context(t)
+		b        = budget.TokenBucket(3, 0)
+		attempts int
+	)
+	for i := 0; i < 10; i++ {
+		err := Retry(ctx, func(ctx context.Context) (err error) {
+			attempts++
+
+			return RetryableError(errors.New("custom error"))
+		}, WithBudget(b), WithFastBackoff(noBackoff{}), WithSlowBackoff(noBackoff{}))
+		require.ErrorIs(t, err, budget.ErrNoQuota)
+	}
+	// first attempt of each call not requires quota
+	require.Equal(t, 10+3, attempts)
+}
+
 type MockPanicCallback struct {
 	called   bool
 	received interface{}
@@ -281,3 +310,189 @@ func TestRetryWithResult(t *testing.T) {
 		})
 	})
 }
+
+func TestAttempt(t *testing.T) {
+	ctx := context.Background()
+	require.Equal(t, 0, Attempt(ctx))
+
+	var attempts []int
+	err := Retry(ctx, func(ctx context.Context) error {
+		attempts = append(attempts, Attempt(ctx))
+		if len(attempts) < 3 {
+			return RetryableError(errors.New("test"))
+		}
+
+		return nil
+	}, WithFastBackoff(backoff.New(backoff.WithSlotDuration(time.Nanosecond))))
+	require.NoError(t, err)
+	require.Equal(t, []int{1, 2, 3}, attempts)
+}
+
+func TestRetryableChecker(t *testing.T) {
+	ctx := xtest.Context(t)
+	errLocksInvalidated := errors.New("transaction locks invalidated")
+	checker := WithRetryableChecker(func(err error) RetryType {
+		switch {
+		case errors.Is(err, errLocksInvalidated):
+			return Retryable
+		case errors.Is(err, context.Canceled):
+			return NonRetryable
+		default:
+			return UnknownRetryType
+		}
+	})
+
+	t.Run("Retryable", func(t *testing.T) {
+		attempts := 0
+		err := Retry(ctx, func(ctx context.Context) error {
+			if attempts++; attempts < 3 {
+				return fmt.Errorf("wrapped: %w", errLocksInvalidated)
+			}
+
+			return nil
+		}, checker)
+		require.NoError(t, err)
+		require.Equal(t, 3, attempts)
+	})
+	t.Run("NonRetryable", func(t *testing.T) {
+		attempts := 0
+		err := Retry(ctx, func(ctx context.Context) error {
+			attempts++
+
+			return RetryableError(context.Canceled)
+		}, checker)
+		require.ErrorIs(t, err, context.Canceled)
+		require.Equal(t, 1, attempts)
+	})
+	t.Run("Unknown", func(t *testing.T) {
+		attempts := 0
+		err := Retry(ctx, func(ctx context.Context) error {
+			if attempts++; attempts < 2 {
+				return RetryableError(errors.New("test"))
+			}
+
+			return errors.New("non-retryable")
+		}, checker, WithFastBackoff(backoff.New(backoff.WithSlotDuration(time.Nanosecond))))
+		require.Error(t, err)
+		require.Equal(t, 2, attempts)
+	})
+	t.Run("Modes", func(t *testing.T) {
+		for _, tt := range []struct {
+			retryType RetryType
+			err       error
+			retry     bool
+			backoff   backoff.Type
+		}{
+			{UnknownRetryType, errors.New("test"), false, backoff.TypeNoBackoff},
+			{UnknownRetryType, RetryableError(errors.New("test"), WithBackoff(TypeSlowBackoff)), true, backoff.TypeSlow},
+			{NonRetryable, RetryableError(errors.New("test"), WithBackoff(TypeSlowBackoff)), false, backoff.TypeNoBackoff},
+			{Retryable, RetryableError(errors.New("test"), WithBackoff(TypeSlowBackoff)), true, backoff.TypeNoBackoff},
+			{RetryableWithBackoff, errors.New("test"), true, backoff.TypeFast},
+			{RetryableWithBackoff, RetryableError(errors.New("test"), WithBackoff(TypeSlowBackoff)), true, backoff.TypeSlow},
+		} {
+			t.Run("", func(t *testing.T) {
+				options := &retryOptions{
+					retryableChecker: func(err error) RetryType {
+						return tt.retryType
+					},
+				}
+				m := options.check(tt.err)
+				require.Equal(t, tt.retry, m.MustRetry(false))
+				require.Equal(t, tt.backoff, m.BackoffType())
+			})
+		}
+	})
+}
+
+func TestRetryAttemptTrace(t *testing.T) {
+	ctx := xtest.Context(t)
+	testErr := errors.New("test")
+
+	var (
+		attempts []trace.RetryAttemptInfo
+		done     []trace.RetryLoopDoneInfo
+	)
+	err := Retry(ctx, func(ctx context.Context) error {
+		if Attempt(ctx) < 3 {
+			return RetryableError(testErr, WithBackoff(TypeFastBackoff))
+		}
+
+		return nil
+	},
+		WithLabel("test"),
+		WithIdempotent(true),
+		WithFastBackoff(backoff.New(backoff.WithSlotDuration(time.Millisecond), backoff.WithCeiling(0))),
+		WithTrace(&trace.Retry{
+			OnRetry: func(info trace.RetryLoopStartInfo) func(trace.RetryLoopDoneInfo) {
+				return func(info trace.RetryLoopDoneInfo) {
+					done = append(done, info)
+				}
+			},
+			OnRetryAttempt: func(info trace.RetryAttemptInfo) {
+				attempts = append(attempts, info)
+			},
+		}),
+	)
+	require.NoError(t, err)
+	require.Len(t, attempts, 2)
+	for i, info := range attempts {
+		require.Equal(t, i+1, info.Attempt)
+		require.Equal(t, "test", info.Label)
+		require.True(t, info.Idempotent)
+		require.ErrorIs(t, info.Error, testErr)
+		require.Positive(t, info.Delay)
+		require.Less(t, info.Delay, time.Second)
+	}
+	require.Equal(t, []trace.RetryLoopDoneInfo{{Attempts: 3}}, done)
+}
+
+func TestHonorServerRetryHint(t *testing.T) {
+	ctx := xtest.Context(t)
+	retryInfoErr := func(delay time.Duration) error {
+		s, err := grpcStatus.New(grpcCodes.Unavailable, "retry later").WithDetails(&errdetails.RetryInfo{
+			RetryDelay: durationpb.New(delay),
+		})
+		require.NoError(t, err)
+
+		return xerrors.Transport(s.Err())
+	}
+	retry := func(testErr error, opts ...Option) (delays []time.Duration) {
+		err := Retry(ctx, func(ctx context.Context) error {
+			if Attempt(ctx) < 2 {
+				return testErr
+			}
+
+			return nil
+		}, append([]Option{
+			WithIdempotent(true),
+			WithFastBackoff(backoff.New(backoff.WithSlotDuration(time.Millisecond), backoff.WithCeiling(0))),
+			WithTrace(&trace.Retry{
+				OnRetryAttempt: func(info trace.RetryAttemptInfo) {
+					delays = append(delays, info.Delay)
+				},
+			}),
+		}, opts...)...)
+		require.NoError(t, err)
+
+		return delays
+	}
+
+	t.Run("Hint", func(t *testing.T) {
+		delays := retry(retryInfoErr(50*time.Millisecond), WithHonorServerRetryHint(true))
+		require.Equal(t, []time.Duration{50 * time.Millisecond}, delays)
+	})
+	t.Run("HintIgnored", func(t *testing.T) {
+		delays := retry(retryInfoErr(50 * time.Millisecond))
+		require.Len(t, delays, 1)
+		require.Less(t, delays[0], 50*time.Millisecond)
+	})
+	t.Run("NoHint", func(t *testing.T) {
+		delays := retry(xerrors.Transport(grpcStatus.Error(grpcCodes.Unavailable, "")), WithHonorServerRetryHint(true))
+		require.Len(t, delays, 1)
+		require.Less(t, delays[0], 50*time.Millisecond)
+	})
+	t.Run("Cap", func(t *testing.T) {
+		require.Equal(t, maxServerRetryHint, withServerRetryHint(time.Millisecond, retryInfoErr(time.Hour)))
+		require.Equal(t, time.Second, withServerRetryHint(time.Second, retryInfoErr(time.Millisecond)))
+	})
+}
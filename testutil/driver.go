@@ -256,6 +256,9 @@ func NewBalancer(opts ...balancerOption) *balancerStub {
 func (b *balancerStub) OnUpdate(func(context.Context, []endpoint.Info)) {
 }
 
+func (b *balancerStub) OnBan(func(context.Context, endpoint.Info, error)) {
+}
+
 type clientConn struct {
 	onInvoke func(
 		ctx context.Context,
@@ -1,6 +1,9 @@
 package trace
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // tool gtrace used from ./internal/cmd/gtrace
 
@@ -31,7 +34,9 @@ type (
 	DiscoveryDiscoverDoneInfo struct {
 		Location  string
 		Endpoints []EndpointInfo
-		Error     error
+		// Latency is a time taken by ListEndpoints call
+		Latency time.Duration
+		Error   error
 	}
 	// Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
 	DiscoveryWhoAmIStartInfo struct {
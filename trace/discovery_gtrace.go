@@ -4,6 +4,7 @@ package trace
 
 import (
 	"context"
+	"time"
 )
 
 // discoveryComposeOptions is a holder of options
@@ -136,17 +137,18 @@ func (t *Discovery) onWhoAmI(d DiscoveryWhoAmIStartInfo) func(DiscoveryWhoAmIDon
 	return res
 }
 // Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
-func DiscoveryOnDiscover(t *Discovery, c *context.Context, call call, address string, database string) func(location string, endpoints []EndpointInfo, _ error) {
+func DiscoveryOnDiscover(t *Discovery, c *context.Context, call call, address string, database string) func(location string, endpoints []EndpointInfo, latency time.Duration, _ error) {
 	var p DiscoveryDiscoverStartInfo
 	p.Context = c
 	p.Call = call
 	p.Address = address
 	p.Database = database
 	res := t.onDiscover(p)
-	return func(location string, endpoints []EndpointInfo, e error) {
+	return func(location string, endpoints []EndpointInfo, latency time.Duration, e error) {
 		var p DiscoveryDiscoverDoneInfo
 		p.Location = location
 		p.Endpoints = endpoints
+		p.Latency = latency
 		p.Error = e
 		res(p)
 	}
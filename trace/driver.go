@@ -38,6 +38,8 @@ type (
 		// Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
 		OnConnStateChange func(DriverConnStateChangeStartInfo) func(DriverConnStateChangeDoneInfo)
 		// Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
+		OnConnGrpcStateChange func(DriverConnGrpcStateChangeInfo)
+		// Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
 		OnConnInvoke func(DriverConnInvokeStartInfo) func(DriverConnInvokeDoneInfo)
 		// Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
 		OnConnNewStream func(DriverConnNewStreamStartInfo) func(DriverConnNewStreamDoneInfo)
@@ -83,7 +85,31 @@ type (
 			DriverBalancerClusterDiscoveryAttemptDoneInfo,
 		)
 		// Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
+		OnBalancerCall func(DriverBalancerCallStartInfo) func(DriverBalancerCallDoneInfo)
+		// Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
 		OnBalancerUpdate func(DriverBalancerUpdateStartInfo) func(DriverBalancerUpdateDoneInfo)
+		// Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
+		OnBalancerFallback func(DriverBalancerFallbackInfo)
+		// Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
+		OnBalancerFallbackRecover func(DriverBalancerFallbackRecoverInfo)
+		// Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
+		OnBalancerCircuitBreakerStateChange func(DriverBalancerCircuitBreakerStateChangeInfo)
+		// Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
+		OnBalancerBanRecovery func(DriverBalancerBanRecoveryInfo)
+		// Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
+		OnBalancerLocalDCNotFound func(DriverBalancerLocalDCNotFoundInfo)
+		// Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
+		OnBalancerFailover func(DriverBalancerFailoverInfo)
+		// Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
+		OnBalancerPartialDiscovery func(DriverBalancerPartialDiscoveryInfo)
+		// Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
+		OnBalancerDuplicateEndpoints func(DriverBalancerDuplicateEndpointsInfo)
+		// Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
+		OnBalancerRecycleEndpoint func(
+			DriverBalancerRecycleEndpointStartInfo,
+		) func(
+			DriverBalancerRecycleEndpointDoneInfo,
+		)
 
 		// Credentials events
 		OnGetCredentials func(DriverGetCredentialsStartInfo) func(DriverGetCredentialsDoneInfo)
@@ -171,6 +197,20 @@ type (
 		State ConnState
 	}
 	// Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
+	DriverConnGrpcStateChangeInfo struct {
+		// Context make available context in trace callback function.
+		// Pointer to context provide replacement of context in trace callback function.
+		// Warning: concurrent access to pointer on client side must be excluded.
+		// Safe replacement of context are provided only inside callback function
+		Context  *context.Context
+		Call     call
+		Endpoint EndpointInfo
+		// State is a new connectivity state of underlying gRPC connection
+		// (IDLE, CONNECTING, READY, TRANSIENT_FAILURE or SHUTDOWN).
+		// Transition from READY to IDLE usually means GOAWAY from server (such as on restart of node)
+		State fmt.Stringer
+	}
+	// Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
 	DriverResolveStartInfo struct {
 		Call     call
 		Target   string
@@ -196,6 +236,130 @@ type (
 		Added     []EndpointInfo
 		Dropped   []EndpointInfo
 		LocalDC   string
+		// Preferred is a count of preferred connections after update
+		Preferred int
+		// Fallback is a count of fallback connections after update
+		Fallback int
+		// AddressFamily is a preferred address family of endpoints after update (empty if preference not defined)
+		AddressFamily string
+	}
+	// Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
+	DriverBalancerFallbackInfo struct {
+		// Context make available context in trace callback function.
+		// Pointer to context provide replacement of context in trace callback function.
+		// Warning: concurrent access to pointer on client side must be excluded.
+		// Safe replacement of context are provided only inside callback function
+		Context       *context.Context
+		Call          call
+		LocalDC       string
+		PreferredConn int
+		FallbackConn  int
+	}
+	// Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
+	DriverBalancerFallbackRecoverInfo struct {
+		// Context make available context in trace callback function.
+		// Pointer to context provide replacement of context in trace callback function.
+		// Warning: concurrent access to pointer on client side must be excluded.
+		// Safe replacement of context are provided only inside callback function
+		Context       *context.Context
+		Call          call
+		LocalDC       string
+		PreferredConn int
+		FallbackConn  int
+	}
+	// Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
+	DriverBalancerCircuitBreakerStateChangeInfo struct {
+		// Context make available context in trace callback function.
+		// Pointer to context provide replacement of context in trace callback function.
+		// Warning: concurrent access to pointer on client side must be excluded.
+		// Safe replacement of context are provided only inside callback function
+		Context  *context.Context
+		Call     call
+		Endpoint EndpointInfo
+		From     string
+		To       string
+		Failures int
+	}
+	// Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
+	DriverBalancerBanRecoveryInfo struct {
+		// Context make available context in trace callback function.
+		// Pointer to context provide replacement of context in trace callback function.
+		// Warning: concurrent access to pointer on client side must be excluded.
+		// Safe replacement of context are provided only inside callback function
+		Context  *context.Context
+		Call     call
+		Endpoint EndpointInfo
+		Attempts int
+	}
+	// Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
+	DriverBalancerFailoverInfo struct {
+		// Context make available context in trace callback function.
+		// Pointer to context provide replacement of context in trace callback function.
+		// Warning: concurrent access to pointer on client side must be excluded.
+		// Safe replacement of context are provided only inside callback function
+		Context *context.Context
+		Call    call
+		// From is a database of balancer which was active before switch
+		From string
+		// To is a database of balancer which is active after switch
+		To string
+		// Failback is true on switch from secondary database back to primary database
+		Failback bool
+		// Error is a cause of failover. Error is nil on failback
+		Error error
+	}
+	// Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
+	DriverBalancerPartialDiscoveryInfo struct {
+		// Context make available context in trace callback function.
+		// Pointer to context provide replacement of context in trace callback function.
+		// Warning: concurrent access to pointer on client side must be excluded.
+		// Safe replacement of context are provided only inside callback function
+		Context *context.Context
+		Call    call
+		// Previous is a count of endpoints of previous discovery result
+		Previous int
+		// Discovered is a count of endpoints of partial discovery result
+		Discovered int
+		// Attempts is a count of consecutive partial discovery results
+		Attempts int
+		// Applied is true if partial discovery result applied after too many consecutive partial results
+		Applied bool
+	}
+	// Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
+	DriverBalancerDuplicateEndpointsInfo struct {
+		// Context make available context in trace callback function.
+		// Pointer to context provide replacement of context in trace callback function.
+		// Warning: concurrent access to pointer on client side must be excluded.
+		// Safe replacement of context are provided only inside callback function
+		Context *context.Context
+		Call    call
+		// Duplicates contains dropped duplicates of endpoints from discovery result
+		Duplicates []EndpointInfo
+	}
+	// Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
+	DriverBalancerRecycleEndpointStartInfo struct {
+		// Context make available context in trace callback function.
+		// Pointer to context provide replacement of context in trace callback function.
+		// Warning: concurrent access to pointer on client side must be excluded.
+		// Safe replacement of context are provided only inside callback function
+		Context  *context.Context
+		Call     call
+		Endpoint EndpointInfo
+	}
+	// Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
+	DriverBalancerRecycleEndpointDoneInfo struct {
+		Error error
+	}
+	// Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
+	DriverBalancerLocalDCNotFoundInfo struct {
+		// Context make available context in trace callback function.
+		// Pointer to context provide replacement of context in trace callback function.
+		// Warning: concurrent access to pointer on client side must be excluded.
+		// Safe replacement of context are provided only inside callback function
+		Context   *context.Context
+		Call      call
+		LocalDC   string
+		Locations []string
 	}
 	// Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
 	DriverBalancerClusterDiscoveryAttemptStartInfo struct {
@@ -283,6 +447,8 @@ type (
 	// Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
 	DriverConnDialDoneInfo struct {
 		Error error
+		// Latency is a time taken by dial
+		Latency time.Duration
 	}
 	// Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
 	DriverConnParkStartInfo struct {
@@ -479,6 +645,34 @@ type (
 	}
 	// Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
 	DriverBalancerChooseEndpointDoneInfo struct {
+		Endpoint EndpointInfo
+		// Role is a role of chosen endpoint ("replica" or "primary"). Role is empty if read replicas not defined
+		Role string
+		// LocalityLevel is a count of matched levels of locality of chosen endpoint with self locality.
+		// LocalityLevel is zero if locality not defined
+		LocalityLevel int
+		// PreferredNodeID is a node ID from context which balancer honored with chosen endpoint.
+		// PreferredNodeID is zero if context has no preferred node ID or preferred node is unavailable
+		PreferredNodeID uint32
+		Error           error
+	}
+	// Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
+	DriverBalancerCallStartInfo struct {
+		// Context make available context in trace callback function.
+		// Pointer to context provide replacement of context in trace callback function.
+		// Replaced context uses for choose of endpoint and for call, so outgoing gRPC metadata
+		// from replaced context (such as context of distributed tracing) sends with call.
+		// Warning: concurrent access to pointer on client side must be excluded.
+		// Safe replacement of context are provided only inside callback function
+		Context *context.Context
+		Call    call
+		Method  string
+		// Attempt is a number of attempt of retry operation. Attempt is 0 for call outside of retry operation
+		Attempt int
+	}
+	// Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
+	DriverBalancerCallDoneInfo struct {
+		// Endpoint is a chosen endpoint of call. Endpoint is nil if balancer cannot choose endpoint
 		Endpoint EndpointInfo
 		Error    error
 	}
@@ -488,10 +682,11 @@ type (
 		// Pointer to context provide replacement of context in trace callback function.
 		// Warning: concurrent access to pointer on client side must be excluded.
 		// Safe replacement of context are provided only inside callback function
-		Context *context.Context
-		Call    call
-		Name    string
-		Event   string
+		Context  *context.Context
+		Call     call
+		Name     string
+		Event    string
+		Interval time.Duration
 	}
 	// Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
 	DriverRepeaterWakeUpDoneInfo struct {
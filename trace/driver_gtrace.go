@@ -4,6 +4,8 @@ package trace
 
 import (
 	"context"
+	"fmt"
+	"time"
 )
 
 // driverComposeOptions is a holder of options
@@ -278,6 +280,25 @@ func (t *Driver) Compose(x *Driver, opts ...DriverComposeOption) *Driver {
 			}
 		}
 	}
+	{
+		h1 := t.OnConnGrpcStateChange
+		h2 := x.OnConnGrpcStateChange
+		ret.OnConnGrpcStateChange = func(d DriverConnGrpcStateChangeInfo) {
+			if options.panicCallback != nil {
+				defer func() {
+					if e := recover(); e != nil {
+						options.panicCallback(e)
+					}
+				}()
+			}
+			if h1 != nil {
+				h1(d)
+			}
+			if h2 != nil {
+				h2(d)
+			}
+		}
+	}
 	{
 		h1 := t.OnConnInvoke
 		h2 := x.OnConnInvoke
@@ -822,6 +843,41 @@ func (t *Driver) Compose(x *Driver, opts ...DriverComposeOption) *Driver {
 			}
 		}
 	}
+	{
+		h1 := t.OnBalancerCall
+		h2 := x.OnBalancerCall
+		ret.OnBalancerCall = func(d DriverBalancerCallStartInfo) func(DriverBalancerCallDoneInfo) {
+			if options.panicCallback != nil {
+				defer func() {
+					if e := recover(); e != nil {
+						options.panicCallback(e)
+					}
+				}()
+			}
+			var r, r1 func(DriverBalancerCallDoneInfo)
+			if h1 != nil {
+				r = h1(d)
+			}
+			if h2 != nil {
+				r1 = h2(d)
+			}
+			return func(d DriverBalancerCallDoneInfo) {
+				if options.panicCallback != nil {
+					defer func() {
+						if e := recover(); e != nil {
+							options.panicCallback(e)
+						}
+					}()
+				}
+				if r != nil {
+					r(d)
+				}
+				if r1 != nil {
+					r1(d)
+				}
+			}
+		}
+	}
 	{
 		h1 := t.OnBalancerUpdate
 		h2 := x.OnBalancerUpdate
@@ -857,6 +913,193 @@ func (t *Driver) Compose(x *Driver, opts ...DriverComposeOption) *Driver {
 			}
 		}
 	}
+	{
+		h1 := t.OnBalancerFallback
+		h2 := x.OnBalancerFallback
+		ret.OnBalancerFallback = func(d DriverBalancerFallbackInfo) {
+			if options.panicCallback != nil {
+				defer func() {
+					if e := recover(); e != nil {
+						options.panicCallback(e)
+					}
+				}()
+			}
+			if h1 != nil {
+				h1(d)
+			}
+			if h2 != nil {
+				h2(d)
+			}
+		}
+	}
+	{
+		h1 := t.OnBalancerFallbackRecover
+		h2 := x.OnBalancerFallbackRecover
+		ret.OnBalancerFallbackRecover = func(d DriverBalancerFallbackRecoverInfo) {
+			if options.panicCallback != nil {
+				defer func() {
+					if e := recover(); e != nil {
+						options.panicCallback(e)
+					}
+				}()
+			}
+			if h1 != nil {
+				h1(d)
+			}
+			if h2 != nil {
+				h2(d)
+			}
+		}
+	}
+	{
+		h1 := t.OnBalancerCircuitBreakerStateChange
+		h2 := x.OnBalancerCircuitBreakerStateChange
+		ret.OnBalancerCircuitBreakerStateChange = func(d DriverBalancerCircuitBreakerStateChangeInfo) {
+			if options.panicCallback != nil {
+				defer func() {
+					if e := recover(); e != nil {
+						options.panicCallback(e)
+					}
+				}()
+			}
+			if h1 != nil {
+				h1(d)
+			}
+			if h2 != nil {
+				h2(d)
+			}
+		}
+	}
+	{
+		h1 := t.OnBalancerBanRecovery
+		h2 := x.OnBalancerBanRecovery
+		ret.OnBalancerBanRecovery = func(d DriverBalancerBanRecoveryInfo) {
+			if options.panicCallback != nil {
+				defer func() {
+					if e := recover(); e != nil {
+						options.panicCallback(e)
+					}
+				}()
+			}
+			if h1 != nil {
+				h1(d)
+			}
+			if h2 != nil {
+				h2(d)
+			}
+		}
+	}
+	{
+		h1 := t.OnBalancerLocalDCNotFound
+		h2 := x.OnBalancerLocalDCNotFound
+		ret.OnBalancerLocalDCNotFound = func(d DriverBalancerLocalDCNotFoundInfo) {
+			if options.panicCallback != nil {
+				defer func() {
+					if e := recover(); e != nil {
+						options.panicCallback(e)
+					}
+				}()
+			}
+			if h1 != nil {
+				h1(d)
+			}
+			if h2 != nil {
+				h2(d)
+			}
+		}
+	}
+	{
+		h1 := t.OnBalancerFailover
+		h2 := x.OnBalancerFailover
+		ret.OnBalancerFailover = func(d DriverBalancerFailoverInfo) {
+			if options.panicCallback != nil {
+				defer func() {
+					if e := recover(); e != nil {
+						options.panicCallback(e)
+					}
+				}()
+			}
+			if h1 != nil {
+				h1(d)
+			}
+			if h2 != nil {
+				h2(d)
+			}
+		}
+	}
+	{
+		h1 := t.OnBalancerPartialDiscovery
+		h2 := x.OnBalancerPartialDiscovery
+		ret.OnBalancerPartialDiscovery = func(d DriverBalancerPartialDiscoveryInfo) {
+			if options.panicCallback != nil {
+				defer func() {
+					if e := recover(); e != nil {
+						options.panicCallback(e)
+					}
+				}()
+			}
+			if h1 != nil {
+				h1(d)
+			}
+			if h2 != nil {
+				h2(d)
+			}
+		}
+	}
+	{
+		h1 := t.OnBalancerDuplicateEndpoints
+		h2 := x.OnBalancerDuplicateEndpoints
+		ret.OnBalancerDuplicateEndpoints = func(d DriverBalancerDuplicateEndpointsInfo) {
+			if options.panicCallback != nil {
+				defer func() {
+					if e := recover(); e != nil {
+						options.panicCallback(e)
+					}
+				}()
+			}
+			if h1 != nil {
+				h1(d)
+			}
+			if h2 != nil {
+				h2(d)
+			}
+		}
+	}
+	{
+		h1 := t.OnBalancerRecycleEndpoint
+		h2 := x.OnBalancerRecycleEndpoint
+		ret.OnBalancerRecycleEndpoint = func(d DriverBalancerRecycleEndpointStartInfo) func(DriverBalancerRecycleEndpointDoneInfo) {
+			if options.panicCallback != nil {
+				defer func() {
+					if e := recover(); e != nil {
+						options.panicCallback(e)
+					}
+				}()
+			}
+			var r, r1 func(DriverBalancerRecycleEndpointDoneInfo)
+			if h1 != nil {
+				r = h1(d)
+			}
+			if h2 != nil {
+				r1 = h2(d)
+			}
+			return func(d DriverBalancerRecycleEndpointDoneInfo) {
+				if options.panicCallback != nil {
+					defer func() {
+						if e := recover(); e != nil {
+							options.panicCallback(e)
+						}
+					}()
+				}
+				if r != nil {
+					r(d)
+				}
+				if r1 != nil {
+					r1(d)
+				}
+			}
+		}
+	}
 	{
 		h1 := t.OnGetCredentials
 		h2 := x.OnGetCredentials
@@ -999,6 +1242,13 @@ func (t *Driver) onConnStateChange(d DriverConnStateChangeStartInfo) func(Driver
 	}
 	return res
 }
+func (t *Driver) onConnGrpcStateChange(d DriverConnGrpcStateChangeInfo) {
+	fn := t.OnConnGrpcStateChange
+	if fn == nil {
+		return
+	}
+	fn(d)
+}
 func (t *Driver) onConnInvoke(d DriverConnInvokeStartInfo) func(DriverConnInvokeDoneInfo) {
 	fn := t.OnConnInvoke
 	if fn == nil {
@@ -1231,6 +1481,21 @@ func (t *Driver) onBalancerClusterDiscoveryAttempt(d DriverBalancerClusterDiscov
 	}
 	return res
 }
+func (t *Driver) onBalancerCall(d DriverBalancerCallStartInfo) func(DriverBalancerCallDoneInfo) {
+	fn := t.OnBalancerCall
+	if fn == nil {
+		return func(DriverBalancerCallDoneInfo) {
+			return
+		}
+	}
+	res := fn(d)
+	if res == nil {
+		return func(DriverBalancerCallDoneInfo) {
+			return
+		}
+	}
+	return res
+}
 func (t *Driver) onBalancerUpdate(d DriverBalancerUpdateStartInfo) func(DriverBalancerUpdateDoneInfo) {
 	fn := t.OnBalancerUpdate
 	if fn == nil {
@@ -1246,6 +1511,77 @@ func (t *Driver) onBalancerUpdate(d DriverBalancerUpdateStartInfo) func(DriverBa
 	}
 	return res
 }
+func (t *Driver) onBalancerFallback(d DriverBalancerFallbackInfo) {
+	fn := t.OnBalancerFallback
+	if fn == nil {
+		return
+	}
+	fn(d)
+}
+func (t *Driver) onBalancerFallbackRecover(d DriverBalancerFallbackRecoverInfo) {
+	fn := t.OnBalancerFallbackRecover
+	if fn == nil {
+		return
+	}
+	fn(d)
+}
+func (t *Driver) onBalancerCircuitBreakerStateChange(d DriverBalancerCircuitBreakerStateChangeInfo) {
+	fn := t.OnBalancerCircuitBreakerStateChange
+	if fn == nil {
+		return
+	}
+	fn(d)
+}
+func (t *Driver) onBalancerBanRecovery(d DriverBalancerBanRecoveryInfo) {
+	fn := t.OnBalancerBanRecovery
+	if fn == nil {
+		return
+	}
+	fn(d)
+}
+func (t *Driver) onBalancerLocalDCNotFound(d DriverBalancerLocalDCNotFoundInfo) {
+	fn := t.OnBalancerLocalDCNotFound
+	if fn == nil {
+		return
+	}
+	fn(d)
+}
+func (t *Driver) onBalancerFailover(d DriverBalancerFailoverInfo) {
+	fn := t.OnBalancerFailover
+	if fn == nil {
+		return
+	}
+	fn(d)
+}
+func (t *Driver) onBalancerPartialDiscovery(d DriverBalancerPartialDiscoveryInfo) {
+	fn := t.OnBalancerPartialDiscovery
+	if fn == nil {
+		return
+	}
+	fn(d)
+}
+func (t *Driver) onBalancerDuplicateEndpoints(d DriverBalancerDuplicateEndpointsInfo) {
+	fn := t.OnBalancerDuplicateEndpoints
+	if fn == nil {
+		return
+	}
+	fn(d)
+}
+func (t *Driver) onBalancerRecycleEndpoint(d DriverBalancerRecycleEndpointStartInfo) func(DriverBalancerRecycleEndpointDoneInfo) {
+	fn := t.OnBalancerRecycleEndpoint
+	if fn == nil {
+		return func(DriverBalancerRecycleEndpointDoneInfo) {
+			return
+		}
+	}
+	res := fn(d)
+	if res == nil {
+		return func(DriverBalancerRecycleEndpointDoneInfo) {
+			return
+		}
+	}
+	return res
+}
 func (t *Driver) onGetCredentials(d DriverGetCredentialsStartInfo) func(DriverGetCredentialsDoneInfo) {
 	fn := t.OnGetCredentials
 	if fn == nil {
@@ -1354,6 +1690,15 @@ func DriverOnConnStateChange(t *Driver, c *context.Context, call call, endpoint
 	}
 }
 // Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
+func DriverOnConnGrpcStateChange(t *Driver, c *context.Context, call call, endpoint EndpointInfo, state fmt.Stringer) {
+	var p DriverConnGrpcStateChangeInfo
+	p.Context = c
+	p.Call = call
+	p.Endpoint = endpoint
+	p.State = state
+	t.onConnGrpcStateChange(p)
+}
+// Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
 func DriverOnConnInvoke(t *Driver, c *context.Context, call call, endpoint EndpointInfo, m Method) func(_ error, issues []Issue, opID string, state ConnState, metadata map[string][]string) {
 	var p DriverConnInvokeStartInfo
 	p.Context = c
@@ -1431,15 +1776,16 @@ func DriverOnConnStreamFinish(t *Driver, c context.Context, call call, e error)
 	t.onConnStreamFinish(p)
 }
 // Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
-func DriverOnConnDial(t *Driver, c *context.Context, call call, endpoint EndpointInfo) func(error) {
+func DriverOnConnDial(t *Driver, c *context.Context, call call, endpoint EndpointInfo) func(_ error, latency time.Duration) {
 	var p DriverConnDialStartInfo
 	p.Context = c
 	p.Call = call
 	p.Endpoint = endpoint
 	res := t.onConnDial(p)
-	return func(e error) {
+	return func(e error, latency time.Duration) {
 		var p DriverConnDialDoneInfo
 		p.Error = e
+		p.Latency = latency
 		res(p)
 	}
 }
@@ -1499,12 +1845,13 @@ func DriverOnConnClose(t *Driver, c *context.Context, call call, endpoint Endpoi
 	}
 }
 // Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
-func DriverOnRepeaterWakeUp(t *Driver, c *context.Context, call call, name string, event string) func(error) {
+func DriverOnRepeaterWakeUp(t *Driver, c *context.Context, call call, name string, event string, interval time.Duration) func(error) {
 	var p DriverRepeaterWakeUpStartInfo
 	p.Context = c
 	p.Call = call
 	p.Name = name
 	p.Event = event
+	p.Interval = interval
 	res := t.onRepeaterWakeUp(p)
 	return func(e error) {
 		var p DriverRepeaterWakeUpDoneInfo
@@ -1538,14 +1885,17 @@ func DriverOnBalancerClose(t *Driver, c *context.Context, call call) func(error)
 	}
 }
 // Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
-func DriverOnBalancerChooseEndpoint(t *Driver, c *context.Context, call call) func(endpoint EndpointInfo, _ error) {
+func DriverOnBalancerChooseEndpoint(t *Driver, c *context.Context, call call) func(endpoint EndpointInfo, role string, localityLevel int, preferredNodeID uint32, _ error) {
 	var p DriverBalancerChooseEndpointStartInfo
 	p.Context = c
 	p.Call = call
 	res := t.onBalancerChooseEndpoint(p)
-	return func(endpoint EndpointInfo, e error) {
+	return func(endpoint EndpointInfo, role string, localityLevel int, preferredNodeID uint32, e error) {
 		var p DriverBalancerChooseEndpointDoneInfo
 		p.Endpoint = endpoint
+		p.Role = role
+		p.LocalityLevel = localityLevel
+		p.PreferredNodeID = preferredNodeID
 		p.Error = e
 		res(p)
 	}
@@ -1564,18 +1914,128 @@ func DriverOnBalancerClusterDiscoveryAttempt(t *Driver, c *context.Context, call
 	}
 }
 // Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
-func DriverOnBalancerUpdate(t *Driver, c *context.Context, call call, needLocalDC bool) func(endpoints []EndpointInfo, added []EndpointInfo, dropped []EndpointInfo, localDC string) {
+func DriverOnBalancerCall(t *Driver, c *context.Context, call call, method string, attempt int) func(endpoint EndpointInfo, _ error) {
+	var p DriverBalancerCallStartInfo
+	p.Context = c
+	p.Call = call
+	p.Method = method
+	p.Attempt = attempt
+	res := t.onBalancerCall(p)
+	return func(endpoint EndpointInfo, e error) {
+		var p DriverBalancerCallDoneInfo
+		p.Endpoint = endpoint
+		p.Error = e
+		res(p)
+	}
+}
+// Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
+func DriverOnBalancerUpdate(t *Driver, c *context.Context, call call, needLocalDC bool) func(endpoints []EndpointInfo, added []EndpointInfo, dropped []EndpointInfo, localDC string, preferred int, fallback int, addressFamily string) {
 	var p DriverBalancerUpdateStartInfo
 	p.Context = c
 	p.Call = call
 	p.NeedLocalDC = needLocalDC
 	res := t.onBalancerUpdate(p)
-	return func(endpoints []EndpointInfo, added []EndpointInfo, dropped []EndpointInfo, localDC string) {
+	return func(endpoints []EndpointInfo, added []EndpointInfo, dropped []EndpointInfo, localDC string, preferred int, fallback int, addressFamily string) {
 		var p DriverBalancerUpdateDoneInfo
 		p.Endpoints = endpoints
 		p.Added = added
 		p.Dropped = dropped
 		p.LocalDC = localDC
+		p.Preferred = preferred
+		p.Fallback = fallback
+		p.AddressFamily = addressFamily
+		res(p)
+	}
+}
+// Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
+func DriverOnBalancerFallback(t *Driver, c *context.Context, call call, localDC string, preferredConn int, fallbackConn int) {
+	var p DriverBalancerFallbackInfo
+	p.Context = c
+	p.Call = call
+	p.LocalDC = localDC
+	p.PreferredConn = preferredConn
+	p.FallbackConn = fallbackConn
+	t.onBalancerFallback(p)
+}
+// Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
+func DriverOnBalancerFallbackRecover(t *Driver, c *context.Context, call call, localDC string, preferredConn int, fallbackConn int) {
+	var p DriverBalancerFallbackRecoverInfo
+	p.Context = c
+	p.Call = call
+	p.LocalDC = localDC
+	p.PreferredConn = preferredConn
+	p.FallbackConn = fallbackConn
+	t.onBalancerFallbackRecover(p)
+}
+// Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
+func DriverOnBalancerCircuitBreakerStateChange(t *Driver, c *context.Context, call call, endpoint EndpointInfo, from string, to string, failures int) {
+	var p DriverBalancerCircuitBreakerStateChangeInfo
+	p.Context = c
+	p.Call = call
+	p.Endpoint = endpoint
+	p.From = from
+	p.To = to
+	p.Failures = failures
+	t.onBalancerCircuitBreakerStateChange(p)
+}
+// Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
+func DriverOnBalancerBanRecovery(t *Driver, c *context.Context, call call, endpoint EndpointInfo, attempts int) {
+	var p DriverBalancerBanRecoveryInfo
+	p.Context = c
+	p.Call = call
+	p.Endpoint = endpoint
+	p.Attempts = attempts
+	t.onBalancerBanRecovery(p)
+}
+// Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
+func DriverOnBalancerLocalDCNotFound(t *Driver, c *context.Context, call call, localDC string, locations []string) {
+	var p DriverBalancerLocalDCNotFoundInfo
+	p.Context = c
+	p.Call = call
+	p.LocalDC = localDC
+	p.Locations = locations
+	t.onBalancerLocalDCNotFound(p)
+}
+// Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
+func DriverOnBalancerFailover(t *Driver, c *context.Context, call call, from string, to string, failback bool, e error) {
+	var p DriverBalancerFailoverInfo
+	p.Context = c
+	p.Call = call
+	p.From = from
+	p.To = to
+	p.Failback = failback
+	p.Error = e
+	t.onBalancerFailover(p)
+}
+// Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
+func DriverOnBalancerPartialDiscovery(t *Driver, c *context.Context, call call, previous int, discovered int, attempts int, applied bool) {
+	var p DriverBalancerPartialDiscoveryInfo
+	p.Context = c
+	p.Call = call
+	p.Previous = previous
+	p.Discovered = discovered
+	p.Attempts = attempts
+	p.Applied = applied
+	t.onBalancerPartialDiscovery(p)
+}
+// Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
+func DriverOnBalancerDuplicateEndpoints(t *Driver, c *context.Context, call call, duplicates []EndpointInfo) {
+	var p DriverBalancerDuplicateEndpointsInfo
+	p.Context = c
+	p.Call = call
+	p.Duplicates = duplicates
+	t.onBalancerDuplicateEndpoints(p)
+}
+// Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
+func DriverOnBalancerRecycleEndpoint(t *Driver, c *context.Context, call call, endpoint EndpointInfo) func(error) {
+	var p DriverBalancerRecycleEndpointStartInfo
+	p.Context = c
+	p.Call = call
+	p.Endpoint = endpoint
+	res := t.onBalancerRecycleEndpoint(p)
+	return func(e error) {
+		var p DriverBalancerRecycleEndpointDoneInfo
+		p.Error = e
 		res(p)
 	}
 }
@@ -0,0 +1,113 @@
+package trace
+
+import (
+	"reflect"
+	"sync"
+)
+
+// DriverEvent is a driver trace event captured by DriverRecorder
+type DriverEvent struct {
+	// Name is a name of Driver callback (such as OnBalancerChooseEndpoint)
+	Name string
+	// Info is an info of event (start info for events with done callback)
+	Info interface{}
+	// Done is a done info of event. Done is nil for events without done callback or for not finished events
+	Done interface{}
+}
+
+// DriverRecorder is a Driver which captures all driver trace events with their payloads (such as for tests)
+type DriverRecorder struct {
+	Driver
+
+	mu     sync.Mutex
+	events []DriverEvent
+}
+
+// Recorder makes DriverRecorder which captures all events of Driver trace in order of their start
+func Recorder() *DriverRecorder {
+	r := &DriverRecorder{}
+
+	v := reflect.ValueOf(&r.Driver).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		if field.Kind() != reflect.Func || field.Type().NumIn() != 1 {
+			continue
+		}
+		field.Set(r.recordFunc(v.Type().Field(i).Name, field.Type()))
+	}
+
+	return r
+}
+
+func (r *DriverRecorder) recordFunc(name string, t reflect.Type) reflect.Value {
+	return reflect.MakeFunc(t, func(args []reflect.Value) []reflect.Value {
+		i := r.add(DriverEvent{
+			Name: name,
+			Info: args[0].Interface(),
+		})
+
+		if t.NumOut() != 1 {
+			return nil
+		}
+
+		done := t.Out(0)
+		if done.Kind() != reflect.Func || done.NumIn() != 1 {
+			return []reflect.Value{reflect.Zero(done)}
+		}
+
+		return []reflect.Value{reflect.MakeFunc(done, func(args []reflect.Value) []reflect.Value {
+			r.done(i, args[0].Interface())
+
+			return nil
+		})}
+	})
+}
+
+func (r *DriverRecorder) add(e DriverEvent) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.events = append(r.events, e)
+
+	return len(r.events) - 1
+}
+
+func (r *DriverRecorder) done(i int, info interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.events[i].Done = info
+}
+
+// Events returns captured events in order of their start.
+// If names defined - Events returns only events of Driver callbacks with these names
+func (r *DriverRecorder) Events(names ...string) []DriverEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(names) == 0 {
+		return append([]DriverEvent(nil), r.events...)
+	}
+
+	filter := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		filter[name] = struct{}{}
+	}
+
+	events := make([]DriverEvent, 0, len(r.events))
+	for _, e := range r.events {
+		if _, has := filter[e.Name]; has {
+			events = append(events, e)
+		}
+	}
+
+	return events
+}
+
+// Reset removes all captured events
+func (r *DriverRecorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.events = nil
+}
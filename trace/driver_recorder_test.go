@@ -0,0 +1,38 @@
+package trace
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder(t *testing.T) {
+	ctx := context.Background()
+	r := Recorder()
+
+	DriverOnBalancerChooseEndpoint(&r.Driver, &ctx, nil)(nil, "replica", 0, 0, nil)
+	DriverOnBalancerFallback(&r.Driver, &ctx, nil, "a", 0, 3)
+	onDone := DriverOnBalancerChooseEndpoint(&r.Driver, &ctx, nil)
+
+	events := r.Events()
+	require.Len(t, events, 3)
+	require.Equal(t, "OnBalancerChooseEndpoint", events[0].Name)
+	require.IsType(t, DriverBalancerChooseEndpointStartInfo{}, events[0].Info)
+	require.Equal(t, "replica", events[0].Done.(DriverBalancerChooseEndpointDoneInfo).Role)
+	require.Equal(t, "OnBalancerFallback", events[1].Name)
+	require.Equal(t, 3, events[1].Info.(DriverBalancerFallbackInfo).FallbackConn)
+	require.Nil(t, events[1].Done)
+	require.Nil(t, events[2].Done)
+
+	testErr := errors.New("test")
+	onDone(nil, "", 0, 0, testErr)
+	events = r.Events("OnBalancerChooseEndpoint")
+	require.Len(t, events, 2)
+	require.ErrorIs(t, events[1].Done.(DriverBalancerChooseEndpointDoneInfo).Error, testErr)
+	require.Empty(t, r.Events("OnConnBan"))
+
+	r.Reset()
+	require.Empty(t, r.Events())
+}
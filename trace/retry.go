@@ -6,6 +6,7 @@ package trace
 
 import (
 	"context"
+	"time"
 )
 
 type (
@@ -15,6 +16,8 @@ type (
 	Retry struct {
 		// Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
 		OnRetry func(RetryLoopStartInfo) func(RetryLoopDoneInfo)
+		// Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
+		OnRetryAttempt func(RetryAttemptInfo)
 	}
 	// Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
 	RetryLoopStartInfo struct {
@@ -30,6 +33,24 @@ type (
 
 		NestedCall bool // a sign for detect Retry calls inside head Retry
 	}
+	// RetryAttemptInfo is an info of failed attempt of retry loop which will be retried after delay
+	// Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
+	RetryAttemptInfo struct {
+		// Context make available context in trace callback function.
+		// Pointer to context provide replacement of context in trace callback function.
+		// Warning: concurrent access to pointer on client side must be excluded.
+		// Safe replacement of context are provided only inside callback function
+		Context *context.Context
+
+		Call  call
+		Label string
+		// Attempt is a number of failed attempt starting from 1
+		Attempt int
+		Error   error
+		// Delay is a computed backoff delay before next attempt
+		Delay      time.Duration
+		Idempotent bool
+	}
 	// Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
 	RetryLoopDoneInfo struct {
 		Attempts int
@@ -4,6 +4,7 @@ package trace
 
 import (
 	"context"
+	"time"
 )
 
 // retryComposeOptions is a holder of options
@@ -68,6 +69,25 @@ func (t *Retry) Compose(x *Retry, opts ...RetryComposeOption) *Retry {
 			}
 		}
 	}
+	{
+		h1 := t.OnRetryAttempt
+		h2 := x.OnRetryAttempt
+		ret.OnRetryAttempt = func(r RetryAttemptInfo) {
+			if options.panicCallback != nil {
+				defer func() {
+					if e := recover(); e != nil {
+						options.panicCallback(e)
+					}
+				}()
+			}
+			if h1 != nil {
+				h1(r)
+			}
+			if h2 != nil {
+				h2(r)
+			}
+		}
+	}
 	return &ret
 }
 func (t *Retry) onRetry(r RetryLoopStartInfo) func(RetryLoopDoneInfo) {
@@ -85,6 +105,13 @@ func (t *Retry) onRetry(r RetryLoopStartInfo) func(RetryLoopDoneInfo) {
 	}
 	return res
 }
+func (t *Retry) onRetryAttempt(r RetryAttemptInfo) {
+	fn := t.OnRetryAttempt
+	if fn == nil {
+		return
+	}
+	fn(r)
+}
 // Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
 func RetryOnRetry(t *Retry, c *context.Context, call call, label string, idempotent bool, nestedCall bool) func(attempts int, _ error) {
 	var p RetryLoopStartInfo
@@ -101,3 +128,15 @@ func RetryOnRetry(t *Retry, c *context.Context, call call, label string, idempot
 		res(p)
 	}
 }
+// Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
+func RetryOnRetryAttempt(t *Retry, c *context.Context, call call, label string, attempt int, e error, delay time.Duration, idempotent bool) {
+	var p RetryAttemptInfo
+	p.Context = c
+	p.Call = call
+	p.Label = label
+	p.Attempt = attempt
+	p.Error = e
+	p.Delay = delay
+	p.Idempotent = idempotent
+	t.onRetryAttempt(p)
+}
@@ -6,3 +6,22 @@ import (
 
 // Version reports current version of sdk
 const Version = version.Version
+
+// VersionInfo is a structured version of sdk with numeric components
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+type VersionInfo = version.Info
+
+// ParsedVersion returns numeric components of current version of sdk
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func ParsedVersion() (major, minor, patch int) {
+	return version.Parsed()
+}
+
+// GetVersionInfo returns structured current version of sdk
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func GetVersionInfo() VersionInfo {
+	return version.Get()
+}
@@ -5,17 +5,23 @@ import (
 	"context"
 	"crypto/rand"
 	"crypto/rsa"
+	"crypto/tls"
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/pem"
+	"errors"
 	"math/big"
+	"net"
 	"os"
 	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
 
+	"github.com/ydb-platform/ydb-go-sdk/v3/balancers"
 	"github.com/ydb-platform/ydb-go-sdk/v3/config"
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/certificates"
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/conn"
@@ -151,3 +157,229 @@ func TestWithCertificatesCached(t *testing.T) {
 		})
 	}
 }
+
+func TestWithDialTarget(t *testing.T) {
+	t.Run("Default", func(t *testing.T) {
+		cfg := config.New(config.WithEndpoint("localhost:2135"))
+		require.Equal(t, "ydb:///localhost:2135", cfg.DialTarget("localhost:2135"))
+		require.Equal(t, "ydb:///node:2136", cfg.DialTarget("node:2136"))
+	})
+	t.Run("Custom", func(t *testing.T) {
+		cfg := config.New(
+			config.WithEndpoint("localhost:2135"),
+			config.WithDialTarget("xds:///cluster"),
+		)
+		require.Equal(t, "xds:///cluster", cfg.DialTarget("localhost:2135"))
+		require.Equal(t, "xds:///node:2136", cfg.DialTarget("node:2136"))
+	})
+}
+
+func TestWithFailoverDatabase(t *testing.T) {
+	t.Run("Undefined", func(t *testing.T) {
+		_, _, _, has := config.New(config.WithEndpoint("localhost:2135")).FailoverDatabase()
+		require.False(t, has)
+	})
+	t.Run("Defined", func(t *testing.T) {
+		cfg := config.New(
+			config.WithEndpoint("localhost:2135"),
+			config.WithDatabase("/primary"),
+			config.WithBootstrapEndpoints("localhost:2136"),
+			config.WithSecure(true),
+			config.WithFailoverDatabase("standby:2135", "/standby", time.Minute, 5*time.Minute),
+		)
+		standby, failoverDelay, failbackDelay, has := cfg.FailoverDatabase()
+		require.True(t, has)
+		require.Equal(t, time.Minute, failoverDelay)
+		require.Equal(t, 5*time.Minute, failbackDelay)
+		require.Equal(t, "standby:2135", standby.Endpoint())
+		require.Equal(t, "/standby", standby.Database())
+		require.Equal(t, []string{"standby:2135"}, standby.BootstrapEndpoints())
+		require.True(t, standby.Secure())
+		_, _, _, has = standby.FailoverDatabase()
+		require.False(t, has)
+		require.Equal(t, "localhost:2135", cfg.Endpoint())
+		require.Equal(t, "/primary", cfg.Database())
+	})
+}
+
+func TestWithIdleTimeout(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		opts []config.Option
+		ttl  time.Duration
+	}{
+		{
+			name: "IdleTimeoutOnly",
+			opts: []config.Option{config.WithBalancer(balancers.Default().With(balancers.WithIdleTimeout(time.Minute)))},
+			ttl:  time.Minute,
+		},
+		{
+			name: "ShorterIdleTimeout",
+			opts: []config.Option{
+				config.WithConnectionTTL(time.Hour),
+				config.WithBalancer(balancers.Default().With(balancers.WithIdleTimeout(time.Minute))),
+			},
+			ttl: time.Minute,
+		},
+		{
+			name: "ShorterConnectionTTL",
+			opts: []config.Option{
+				config.WithConnectionTTL(time.Second),
+				config.WithBalancer(balancers.Default().With(balancers.WithIdleTimeout(time.Minute))),
+			},
+			ttl: time.Second,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.ttl, config.New(tt.opts...).ConnectionTTL())
+		})
+	}
+}
+
+func TestWithClientCertificate(t *testing.T) {
+	cert := tls.Certificate{Certificate: [][]byte{[]byte("test")}}
+	t.Run("Appended", func(t *testing.T) {
+		cfg := config.New(config.WithSecure(true), config.WithClientCertificate(cert))
+		require.Equal(t, []tls.Certificate{cert}, cfg.TLSConfig().Certificates)
+	})
+	t.Run("TLSConfigPrecedence", func(t *testing.T) {
+		tlsConfig := &tls.Config{} //nolint:gosec
+		for _, opts := range [][]config.Option{
+			{config.WithClientCertificate(cert), config.WithTLSConfig(tlsConfig)},
+			{config.WithTLSConfig(tlsConfig), config.WithClientCertificate(cert)},
+		} {
+			cfg := config.New(append(opts, config.WithSecure(true))...)
+			require.Same(t, tlsConfig, cfg.TLSConfig())
+			require.Empty(t, cfg.TLSConfig().Certificates)
+		}
+	})
+	t.Run("Insecure", func(t *testing.T) {
+		_, err := Open(context.Background(), "grpc://localhost:2135/local", WithClientCertificate(cert))
+		require.ErrorContains(t, err, "insecure connection")
+	})
+}
+
+func TestWithKeepalive(t *testing.T) {
+	t.Run("Default", func(t *testing.T) {
+		require.Equal(t, config.DefaultGrpcConnectionPolicy, config.New().KeepaliveParams())
+	})
+	t.Run("Custom", func(t *testing.T) {
+		cfg := config.New(
+			config.WithKeepaliveTime(time.Minute),
+			config.WithKeepaliveTimeout(20*time.Second),
+			config.WithKeepalivePermitWithoutStream(false),
+		)
+		require.Equal(t, keepalive.ClientParameters{
+			Time:                time.Minute,
+			Timeout:             20 * time.Second,
+			PermitWithoutStream: false,
+		}, cfg.KeepaliveParams())
+	})
+}
+
+func TestWithContextDialer(t *testing.T) {
+	addresses := make(chan string, 1)
+	cfg := config.New(config.WithContextDialer(func(ctx context.Context, address string) (net.Conn, error) {
+		select {
+		case addresses <- address:
+		default:
+		}
+
+		return nil, errors.New("test")
+	}))
+	cc, err := grpc.DialContext(context.Background(), //nolint:staticcheck,nolintlint
+		cfg.DialTarget("127.0.0.1:2136"), cfg.GrpcDialOptions()...,
+	)
+	require.NoError(t, err)
+	defer cc.Close()
+
+	cc.Connect()
+	select {
+	case address := <-addresses:
+		require.Equal(t, "127.0.0.1:2136", address)
+	case <-time.After(10 * time.Second):
+		t.Fatal("custom dialer not called")
+	}
+}
+
+func TestWithSystemCertPool(t *testing.T) {
+	caPrivKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ca := &x509.Certificate{
+		SerialNumber:          big.NewInt(2024),
+		Subject:               pkix.Name{Organization: []string{"Private CA"}},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().AddDate(1, 0, 0),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caBytes, err := x509.CreateCertificate(rand.Reader, ca, ca, &caPrivKey.PublicKey, caPrivKey)
+	require.NoError(t, err)
+	caCert, err := x509.ParseCertificate(caBytes)
+	require.NoError(t, err)
+	caPEM := pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: caBytes,
+	})
+
+	systemPool, err := x509.SystemCertPool()
+	if err != nil {
+		systemPool = x509.NewCertPool()
+	}
+	systemPool.AddCert(caCert)
+	customPool := x509.NewCertPool()
+	customPool.AddCert(caCert)
+
+	ctx := context.Background()
+	for _, tt := range []struct {
+		name    string
+		options []Option
+		pool    *x509.CertPool
+	}{
+		{
+			name:    "Default",
+			options: []Option{WithCertificatesFromPem(caPEM, certificates.FromPemStrict(true))},
+			pool:    systemPool,
+		},
+		{
+			name:    "SystemPoolAndCustomCA",
+			options: []Option{WithSystemCertPool(true), WithCertificatesFromPem(caPEM, certificates.FromPemStrict(true))},
+			pool:    systemPool,
+		},
+		{
+			name:    "CustomCAOnly",
+			options: []Option{WithCertificatesFromPem(caPEM, certificates.FromPemStrict(true)), WithSystemCertPool(false)},
+			pool:    customPool,
+		},
+		{
+			name:    "CustomCAOnlyReversed",
+			options: []Option{WithSystemCertPool(false), WithCertificatesFromPem(caPEM, certificates.FromPemStrict(true))},
+			pool:    customPool,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			db, err := newConnectionFromOptions(ctx,
+				append(tt.options,
+					WithSecure(true),
+					withConnPool(conn.NewPool(context.Background(), config.New())), //nolint:contextcheck
+				)...,
+			)
+			require.NoError(t, err)
+			require.True(t, tt.pool.Equal(db.config.TLSConfig().RootCAs))
+		})
+	}
+
+	t.Run("MalformedPEM", func(t *testing.T) {
+		for _, data := range [][]byte{
+			nil,
+			[]byte("not a pem"),
+			append(caPEM, []byte("garbage")...),
+			pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("garbage")}),
+			pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: caBytes}),
+		} {
+			_, err := newConnectionFromOptions(ctx, WithCertificatesFromPem(data, certificates.FromPemStrict(true)))
+			require.ErrorIs(t, err, certificates.ErrMalformedPem)
+		}
+	})
+}